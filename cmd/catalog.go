@@ -0,0 +1,390 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/literalog/library/pkg/client"
+	"github.com/literalog/library/pkg/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var (
+	server string
+	output string
+)
+
+// collection is the part of a client collection the catalog commands use,
+// for entity T created from request R.
+type collection[T, R any] interface {
+	Create(ctx context.Context, req R) (*T, error)
+	Get(ctx context.Context, id string) (*T, error)
+	List(ctx context.Context, opts client.ListOptions) (*models.List[T], error)
+	All(ctx context.Context, opts client.ListOptions) *client.Iterator[T]
+	Update(ctx context.Context, id string, req R) (*T, error)
+	Patch(ctx context.Context, id string, fields map[string]any) (*T, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// column is a table column.
+type column[T any] struct {
+	header string
+	value  func(T) string
+}
+
+// catalog describes the commands managing one collection.
+type catalog[T, R any] struct {
+	name       string
+	collection func(*client.Client) collection[T, R]
+	columns    []column[T]
+	// fields binds the input flags of create and update to req. Each flag
+	// sets the request field named like it, with dashes for underscores.
+	fields func(fs *pflag.FlagSet, req *R)
+}
+
+func (c catalog[T, R]) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   c.name,
+		Short: "manages " + c.name + " on a running server",
+	}
+	cmd.PersistentFlags().StringVarP(&output, "output", "o", outputTable, "output format (table, json or yaml)")
+	cmd.PersistentFlags().StringVar(&server, "server", "", "server url, overriding the profile")
+
+	cmd.AddCommand(c.listCommand(), c.getCommand(), c.createCommand(), c.updateCommand(), c.deleteCommand())
+	return cmd
+}
+
+func (c catalog[T, R]) listCommand() *cobra.Command {
+	var (
+		opts    client.ListOptions
+		sort    string
+		filters []string
+		all     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "lists " + c.name,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cl, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			opts.Sort = parseSort(sort)
+			opts.Filter = url.Values{}
+			for _, f := range filters {
+				k, v, ok := strings.Cut(f, "=")
+				if !ok {
+					return fmt.Errorf("invalid filter %q, expected key=value", f)
+				}
+				opts.Filter.Add(k, v)
+			}
+
+			if !all {
+				l, err := c.collection(cl).List(cmd.Context(), opts)
+				if err != nil {
+					return err
+				}
+				if output == outputTable {
+					return c.table(cmd.OutOrStdout(), l.Items)
+				}
+				return write(cmd.OutOrStdout(), l)
+			}
+
+			var items []T
+			it := c.collection(cl).All(cmd.Context(), opts)
+			for it.Next() {
+				items = append(items, it.Item())
+			}
+			if err := it.Err(); err != nil {
+				return err
+			}
+			if output == outputTable {
+				return c.table(cmd.OutOrStdout(), items)
+			}
+			return write(cmd.OutOrStdout(), items)
+		},
+	}
+	cmd.Flags().IntVar(&opts.Limit, "limit", 0, "page size")
+	cmd.Flags().StringVar(&opts.Cursor, "cursor", "", "cursor of the page to list")
+	cmd.Flags().StringVar(&sort, "sort", "", "comma separated sort fields, - for descending")
+	cmd.Flags().StringArrayVar(&filters, "filter", nil, "filter as key=value, may be repeated")
+	cmd.Flags().BoolVar(&all, "all", false, "list every page")
+	return cmd
+}
+
+func (c catalog[T, R]) getCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <id>",
+		Short: "shows one of the " + c.name,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cl, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			v, err := c.collection(cl).Get(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return c.print(cmd.OutOrStdout(), *v)
+		},
+	}
+}
+
+func (c catalog[T, R]) createCommand() *cobra.Command {
+	var (
+		req  R
+		file string
+	)
+
+	inputs := pflag.NewFlagSet("inputs", pflag.ContinueOnError)
+	c.fields(inputs, &req)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "creates one of the " + c.name + " from flags or a file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file != "" {
+				if changed(inputs) {
+					return fmt.Errorf("use either -f or field flags")
+				}
+				if err := readFile(file, &req); err != nil {
+					return err
+				}
+			}
+
+			cl, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			v, err := c.collection(cl).Create(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			return c.print(cmd.OutOrStdout(), *v)
+		},
+	}
+	cmd.Flags().AddFlagSet(inputs)
+	cmd.Flags().StringVarP(&file, "file", "f", "", "JSON or YAML file with the request, - for stdin")
+	return cmd
+}
+
+func (c catalog[T, R]) updateCommand() *cobra.Command {
+	var (
+		req  R
+		file string
+	)
+
+	inputs := pflag.NewFlagSet("inputs", pflag.ContinueOnError)
+	c.fields(inputs, &req)
+
+	cmd := &cobra.Command{
+		Use:   "update <id>",
+		Short: "replaces one of the " + c.name + " from a file, or changes the fields given as flags",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cl, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			var v *T
+			switch {
+			case file != "" && changed(inputs):
+				return fmt.Errorf("use either -f or field flags")
+			case file != "":
+				if err := readFile(file, &req); err != nil {
+					return err
+				}
+				v, err = c.collection(cl).Update(cmd.Context(), args[0], req)
+			case changed(inputs):
+				var fields map[string]any
+				if fields, err = changedFields(inputs, req); err != nil {
+					return err
+				}
+				v, err = c.collection(cl).Patch(cmd.Context(), args[0], fields)
+			default:
+				return fmt.Errorf("nothing to update, use -f or field flags")
+			}
+			if err != nil {
+				return err
+			}
+			return c.print(cmd.OutOrStdout(), *v)
+		},
+	}
+	cmd.Flags().AddFlagSet(inputs)
+	cmd.Flags().StringVarP(&file, "file", "f", "", "JSON or YAML file with the whole request, - for stdin")
+	return cmd
+}
+
+func (c catalog[T, R]) deleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <id>",
+		Short: "deletes one of the " + c.name,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cl, err := newClient()
+			if err != nil {
+				return err
+			}
+			return c.collection(cl).Delete(cmd.Context(), args[0])
+		},
+	}
+}
+
+func (c catalog[T, R]) print(w io.Writer, v T) error {
+	if output == outputTable {
+		return c.table(w, []T{v})
+	}
+	return write(w, v)
+}
+
+func (c catalog[T, R]) table(w io.Writer, items []T) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	headers := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		headers[i] = col.header
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, item := range items {
+		values := make([]string, len(c.columns))
+		for i, col := range c.columns {
+			values[i] = col.value(item)
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
+
+// write prints v as JSON or YAML, using the JSON field names for both.
+func write(w io.Writer, v any) error {
+	switch output {
+	case outputJSON:
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(v)
+	case outputYAML:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		// Reading the JSON as a YAML node keeps the field order.
+		var doc yaml.Node
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return err
+		}
+		blockStyle(&doc)
+
+		e := yaml.NewEncoder(w)
+		e.SetIndent(2)
+		return e.Encode(&doc)
+	default:
+		return fmt.Errorf("unknown output %q, expected table, json or yaml", output)
+	}
+}
+
+// blockStyle drops the flow and quoting styles the node and its children
+// took from JSON. Strings that need quotes are still quoted.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+// readFile decodes a JSON or YAML file, or stdin for "-", into v.
+func readFile(path string, v any) error {
+	var (
+		b   []byte
+		err error
+	)
+	if path == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	// JSON is valid YAML, so one decoder reads both.
+	var doc any
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+	return convert(doc, v)
+}
+
+// convert copies in to out through their JSON representation.
+func convert(in, out any) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// changed reports whether any flag of fs was set. The flags are parsed as
+// part of the command's flag set, so fs.Visit would see none of them.
+func changed(fs *pflag.FlagSet) bool {
+	n := 0
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			n++
+		}
+	})
+	return n > 0
+}
+
+// changedFields returns the request fields whose flags were set, keyed by
+// their JSON names.
+func changedFields[R any](fs *pflag.FlagSet, req R) (map[string]any, error) {
+	var all map[string]any
+	if err := convert(req, &all); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]any)
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			name := strings.ReplaceAll(f.Name, "-", "_")
+			fields[name] = all[name]
+		}
+	})
+	return fields, nil
+}
+
+func parseSort(s string) []models.SortField {
+	if s == "" {
+		return nil
+	}
+
+	var fields []models.SortField
+	for _, f := range strings.Split(s, ",") {
+		field, desc := strings.CutPrefix(f, "-")
+		fields = append(fields, models.SortField{Field: field, Desc: desc})
+	}
+	return fields
+}
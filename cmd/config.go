@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/literalog/library/internal/app/config"
+	"github.com/spf13/cobra"
+)
+
+// configFlags are the configuration flags shared by the commands that need
+// the server configuration.
+var configFlags = config.NewFlags()
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "inspects the configuration",
+}
+
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "prints the effective configuration of the profile, with secrets redacted",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		b, err := cfg.Redacted().YAML()
+		if err != nil {
+			return err
+		}
+		_, err = cmd.OutOrStdout().Write(b)
+		return err
+	},
+}
+
+func init() {
+	configFlags.Register(configPrintCmd.Flags())
+	configCmd.AddCommand(configPrintCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// loadConfig resolves the configuration of the selected profile from the
+// config directory, or LIBRARY_CONFIG_DIR. A profile chosen with --profile
+// must have a file.
+func loadConfig(cmd *cobra.Command) (config.Config, error) {
+	dir := os.Getenv("LIBRARY_CONFIG_DIR")
+	if dir == "" {
+		dir = "config"
+	}
+	return config.Load(profile, dir, cmd.Flags().Changed("profile"), configFlags)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/literalog/library/internal/app/config"
+	"github.com/literalog/library/internal/app/gateways/database/mongodb"
+	"github.com/spf13/cobra"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "manages the storage database",
+}
+
+var dbEnsureCmd = &cobra.Command{
+	Use:   "ensure",
+	Short: "migrates the mongo documents and installs the validators and indexes",
+	Long: `Brings the mongo database to the schema version of this binary, as the
+server does on start: authors stored by earlier versions are moved under
+their id, the collection validators and indexes are installed and the version
+is recorded. Running it again is a no-op.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if cfg.Storage != config.StorageMongo && cfg.Storage != "" {
+			return fmt.Errorf("db ensure applies to the mongo storage, not %s", cfg.Storage)
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeouts.Connect)
+		defer cancel()
+
+		s, err := mongodb.NewMongoStorage(ctx, cfg.Mongo.URI)
+		if err != nil {
+			return err
+		}
+		defer s.Close(context.Background())
+
+		// Migrating a large collection may outlast the connect timeout.
+		report, err := mongodb.Bootstrap(cmd.Context(), s.Client.Database(cfg.Mongo.Database))
+		if err != nil {
+			return err
+		}
+		if len(report.DuplicateTags) > 0 {
+			fmt.Fprintln(os.Stderr, "genre tags are not unique yet: merge the genres sharing a tag with POST /genres/{id}/merge and run db ensure again")
+		}
+		return json.NewEncoder(os.Stdout).Encode(report)
+	},
+}
+
+func init() {
+	configFlags.Register(dbCmd.PersistentFlags())
+	dbCmd.AddCommand(dbEnsureCmd)
+	rootCmd.AddCommand(dbCmd)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/literalog/library/pkg/client"
+	"github.com/literalog/library/pkg/models"
+	"github.com/spf13/pflag"
+)
+
+var booksCatalog = catalog[models.Book, models.BookRequest]{
+	name: "books",
+	collection: func(c *client.Client) collection[models.Book, models.BookRequest] {
+		return c.Books()
+	},
+	columns: []column[models.Book]{
+		{"ID", func(b models.Book) string { return b.Id }},
+		{"TITLE", func(b models.Book) string { return b.Title }},
+		{"AUTHOR", func(b models.Book) string { return b.AuthorId }},
+		{"ISBN", func(b models.Book) string { return strings.Join(b.Isbn, ",") }},
+		{"FORMAT", func(b models.Book) string { return string(b.Format) }},
+		{"YEAR", func(b models.Book) string { return itoa(b.Year) }},
+		{"VERSION", func(b models.Book) string { return strconv.FormatInt(b.Version, 10) }},
+	},
+	fields: func(fs *pflag.FlagSet, req *models.BookRequest) {
+		fs.StringVar(&req.Title, "title", "", "title")
+		fs.StringVar(&req.AuthorId, "author-id", "", "primary author id")
+		fs.StringVar(&req.WorkId, "work-id", "", "work id")
+		fs.StringSliceVar(&req.Isbn, "isbn", nil, "ISBN, may be repeated")
+		fs.StringVar(&req.SeriesId, "series-id", "", "series id")
+		fs.IntVar(&req.SeriesNo, "series-no", 0, "position in the series")
+		fs.IntVar(&req.Year, "year", 0, "publication year")
+		fs.StringVar(&req.Publisher, "publisher", "", "publisher")
+		fs.StringVar(&req.Language, "language", "", "language")
+		fs.StringVar((*string)(&req.Format), "format", "", "format (Hardcover, Paperback, Digital or Audio)")
+		fs.IntVar(&req.PagesNo, "pages-no", 0, "number of pages")
+		fs.IntVar(&req.HoursNo, "hours-no", 0, "length in hours")
+		fs.StringSliceVar(&req.Genre, "genre", nil, "genre tag, may be repeated")
+		fs.StringVar(&req.Blurb, "blurb", "", "blurb")
+		fs.StringVar(&req.Cover, "cover", "", "cover url")
+		fs.BoolVar(&req.NotABook, "not-a-book", false, "not a book")
+	},
+}
+
+var authorsCatalog = catalog[models.Author, models.AuthorRequest]{
+	name: "authors",
+	collection: func(c *client.Client) collection[models.Author, models.AuthorRequest] {
+		return c.Authors()
+	},
+	columns: []column[models.Author]{
+		{"ID", func(a models.Author) string { return a.Id }},
+		{"NAME", func(a models.Author) string { return a.Name }},
+		{"VERSION", func(a models.Author) string { return strconv.FormatInt(a.Version, 10) }},
+	},
+	fields: func(fs *pflag.FlagSet, req *models.AuthorRequest) {
+		fs.StringVar(&req.Name, "name", "", "name")
+	},
+}
+
+var seriesCatalog = catalog[models.Series, models.SeriesRequest]{
+	name: "series",
+	collection: func(c *client.Client) collection[models.Series, models.SeriesRequest] {
+		return c.Series()
+	},
+	columns: []column[models.Series]{
+		{"ID", func(s models.Series) string { return s.Id }},
+		{"NAME", func(s models.Series) string { return s.Name }},
+		{"VERSION", func(s models.Series) string { return strconv.FormatInt(s.Version, 10) }},
+	},
+	fields: func(fs *pflag.FlagSet, req *models.SeriesRequest) {
+		fs.StringVar(&req.Name, "name", "", "name")
+	},
+}
+
+var genresCatalog = catalog[models.Genre, models.GenreRequest]{
+	name: "genres",
+	collection: func(c *client.Client) collection[models.Genre, models.GenreRequest] {
+		return c.Genres()
+	},
+	columns: []column[models.Genre]{
+		{"ID", func(g models.Genre) string { return g.Id }},
+		{"TAG", func(g models.Genre) string { return g.Tag }},
+		{"VERSION", func(g models.Genre) string { return strconv.FormatInt(g.Version, 10) }},
+	},
+	fields: func(fs *pflag.FlagSet, req *models.GenreRequest) {
+		fs.StringVar(&req.Tag, "tag", "", "tag")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(
+		booksCatalog.command(),
+		authorsCatalog.command(),
+		seriesCatalog.command(),
+		genresCatalog.command(),
+	)
+}
+
+// itoa formats n, leaving zero blank.
+func itoa(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/literalog/library/internal/app/config"
+	"github.com/literalog/library/internal/app/domain/book"
+	"github.com/literalog/library/internal/app/gateways/api"
+	"github.com/literalog/library/internal/app/gateways/database/postgres"
+	"github.com/spf13/cobra"
+)
+
+var dryRun bool
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "migrates the storage schema and stored data",
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "applies the pending postgres migrations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withMigrator(cmd, func(m *postgres.Migrator) error {
+			done, err := m.Up(cmd.Context())
+			for _, mig := range done {
+				fmt.Printf("applied %04d_%s\n", mig.Version, mig.Name)
+			}
+			if err == nil && len(done) == 0 {
+				fmt.Println("no pending migrations")
+			}
+			return err
+		})
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "reverts the last applied postgres migration",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withMigrator(cmd, func(m *postgres.Migrator) error {
+			mig, err := m.Down(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if mig == nil {
+				fmt.Println("no applied migrations")
+				return nil
+			}
+			fmt.Printf("reverted %04d_%s\n", mig.Version, mig.Name)
+			return nil
+		})
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "lists the postgres migrations and whether they are applied",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withMigrator(cmd, func(m *postgres.Migrator) error {
+			ss, err := m.Status(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED")
+			for _, s := range ss {
+				applied := "pending"
+				if s.AppliedAt != nil {
+					applied = s.AppliedAt.Format(time.RFC3339)
+				}
+				fmt.Fprintf(tw, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+			}
+			return tw.Flush()
+		})
+	},
+}
+
+var migrateWorksCmd = &cobra.Command{
+	Use:   "works",
+	Short: "groups books into works by title and author",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		repos, err := api.NewRepositories(cfg)
+		if err != nil {
+			return err
+		}
+		defer repos.Storage.Close(context.Background())
+
+		m, err := book.GroupIntoWorks(cmd.Context(), repos.Book, repos.Work, dryRun)
+		if err != nil {
+			return err
+		}
+		return json.NewEncoder(os.Stdout).Encode(m)
+	},
+}
+
+// withMigrator connects to the configured postgres and runs fn with a
+// migrator of its schema. Migrations do not apply to other storages.
+func withMigrator(cmd *cobra.Command, fn func(m *postgres.Migrator) error) error {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if cfg.Storage != config.StoragePostgres {
+		return fmt.Errorf("schema migrations apply to the postgres storage, not %s", cfg.Storage)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeouts.Connect)
+	defer cancel()
+
+	s, err := postgres.NewStorage(ctx, cfg.Postgres.URL)
+	if err != nil {
+		return err
+	}
+	defer s.Close(context.Background())
+
+	m, err := postgres.NewMigrator(s.Pool)
+	if err != nil {
+		return err
+	}
+	return fn(m)
+}
+
+func init() {
+	configFlags.Register(migrateCmd.PersistentFlags())
+	migrateWorksCmd.Flags().BoolVar(&dryRun, "dry-run", false, "report the works that would be created without writing them")
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateStatusCmd, migrateWorksCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/literalog/library/internal/app/config"
+	"github.com/literalog/library/pkg/client"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// Profile is a saved server for the client commands.
+type Profile struct {
+	Server string `yaml:"server"`
+	Token  string `yaml:"token"`
+}
+
+// profilesPath is the file holding the saved profiles, keyed by name:
+//
+//	production:
+//	  server: https://library.example.com
+//	  token: s3cr3t
+func profilesPath() (string, error) {
+	if path := os.Getenv("LIBRARY_PROFILES"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "library", "profiles.yaml"), nil
+}
+
+// loadProfile reads the named profile. Without a saved development profile
+// the client talks to a local server.
+func loadProfile(name string) (Profile, error) {
+	path, err := profilesPath()
+	if err != nil {
+		return Profile{}, err
+	}
+
+	profiles := make(map[string]Profile)
+	b, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return Profile{}, err
+	default:
+		if err := yaml.Unmarshal(b, &profiles); err != nil {
+			return Profile{}, fmt.Errorf("error reading %s: %w", path, err)
+		}
+	}
+
+	p, ok := profiles[name]
+	if !ok && name != config.DefaultProfile {
+		return Profile{}, fmt.Errorf("profile %q not found in %s", name, path)
+	}
+	if p.Server == "" {
+		p.Server = defaultServer
+	}
+	return p, nil
+}
+
+// newClient returns a client for the selected profile, with the server
+// overridden by --server.
+func newClient() (*client.Client, error) {
+	p, err := loadProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	if server != "" {
+		p.Server = server
+	}
+
+	var opts []client.Option
+	if p.Token != "" {
+		opts = append(opts, client.WithToken(p.Token))
+	}
+	return client.New(p.Server, opts...)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"github.com/literalog/library/internal/app/config"
 	"github.com/spf13/cobra"
 )
 
@@ -9,10 +10,13 @@ var profile string
 var rootCmd = &cobra.Command{
 	Use:   "library",
 	Short: "literalog's management of books and related entities",
+	// Errors are reported once by Execute, without the usage.
+	SilenceUsage:  true,
+	SilenceErrors: true,
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVar(&profile, "profile", "development", "application profile (default is development)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", config.DefaultProfile, "application profile, read from config/<profile>.yaml")
 }
 
 func Execute() {
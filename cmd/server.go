@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/literalog/library/internal/app/gateways/api"
 	"github.com/spf13/cobra"
 )
@@ -9,11 +14,27 @@ var serverCmd = &cobra.Command{
 	Use:   "start",
 	Short: "starts library",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		server := api.NewServer(":8080")
-		return server.ServeHttp()
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		level, _ := cfg.Level()
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+
+		server, err := api.NewServer(cfg)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return server.Run(ctx)
 	},
 }
 
 func init() {
+	configFlags.Register(serverCmd.Flags())
 	rootCmd.AddCommand(serverCmd)
 }
@@ -0,0 +1,193 @@
+// Package config resolves the server configuration of a profile from, in
+// increasing precedence, the defaults, config/<profile>.yaml, LIBRARY_*
+// environment variables and command line flags.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/literalog/library/internal/app/domain/reference"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	StorageMongo    = "mongo"
+	StorageMemory   = "memory"
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+
+	// DefaultProfile is used when no profile is selected.
+	DefaultProfile = "development"
+
+	envPrefix = "LIBRARY_"
+)
+
+type Config struct {
+	Addr     string             `yaml:"addr" usage:"address to listen on"`
+	Storage  string             `yaml:"storage" usage:"storage backend (mongo, postgres, sqlite or memory)"`
+	LogLevel string             `yaml:"log_level" usage:"log level (debug, info, warn or error)"`
+	Mongo    Mongo              `yaml:"mongo"`
+	Postgres Postgres           `yaml:"postgres"`
+	SQLite   SQLite             `yaml:"sqlite"`
+	Timeouts Timeouts           `yaml:"timeouts"`
+	OnDelete reference.Policies `yaml:"on_delete"`
+	Features Features           `yaml:"features"`
+}
+
+type Mongo struct {
+	URI      string `yaml:"uri" usage:"MongoDB connection string" secret:"true"`
+	Database string `yaml:"database" usage:"MongoDB database"`
+}
+
+type Postgres struct {
+	URL string `yaml:"url" usage:"PostgreSQL connection string" secret:"true"`
+}
+
+type SQLite struct {
+	Path string `yaml:"path" usage:"SQLite database file, created if missing"`
+}
+
+type Timeouts struct {
+	Connect  time.Duration `yaml:"connect" usage:"time allowed to connect to the storage"`
+	Read     time.Duration `yaml:"read" usage:"time allowed to read a request"`
+	Write    time.Duration `yaml:"write" usage:"time allowed to write a response"`
+	Idle     time.Duration `yaml:"idle" usage:"time an idle keep-alive connection is kept open"`
+	Drain    time.Duration `yaml:"drain" usage:"time the server reports not ready on shutdown before it stops accepting connections"`
+	Shutdown time.Duration `yaml:"shutdown" usage:"time allowed to finish in-flight requests and close the storage on shutdown"`
+}
+
+// Features toggles optional parts of the server.
+type Features struct {
+	Docs bool `yaml:"docs" usage:"serve /openapi.json and /docs"`
+}
+
+func Default() Config {
+	return Config{
+		Addr:     ":8080",
+		Storage:  StorageMongo,
+		LogLevel: "info",
+		Mongo: Mongo{
+			URI:      "mongodb://localhost:27017",
+			Database: "library",
+		},
+		Postgres: Postgres{
+			URL: "postgres://localhost:5432/library",
+		},
+		SQLite: SQLite{
+			Path: "library.db",
+		},
+		Timeouts: Timeouts{
+			Connect:  10 * time.Second,
+			Read:     15 * time.Second,
+			Write:    30 * time.Second,
+			Idle:     2 * time.Minute,
+			Drain:    5 * time.Second,
+			Shutdown: 20 * time.Second,
+		},
+		OnDelete: reference.Policies{
+			Author: reference.Restrict,
+			Series: reference.Restrict,
+			Genre:  reference.Restrict,
+			Work:   reference.Restrict,
+		},
+		Features: Features{
+			Docs: true,
+		},
+	}
+}
+
+// Load resolves the configuration of profile, reading its file from dir.
+// The file may be missing unless required is set, which it should be when
+// the profile was chosen explicitly.
+func Load(profile, dir string, required bool, flags *Flags) (Config, error) {
+	c := Default()
+
+	path := filepath.Join(dir, profile+".yaml")
+	b, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && !required:
+	case err != nil:
+		return c, fmt.Errorf("error reading profile %s: %w", profile, err)
+	default:
+		d := yaml.NewDecoder(bytes.NewReader(b))
+		d.KnownFields(true)
+		if err := d.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+			return c, fmt.Errorf("error reading %s: %w", path, err)
+		}
+	}
+
+	// Deployments predating LIBRARY_MONGO_URI set MONGO_URI. It overrides
+	// the profile file, as any environment variable does, but yields to
+	// LIBRARY_MONGO_URI.
+	if uri := os.Getenv("MONGO_URI"); uri != "" {
+		c.Mongo.URI = uri
+	}
+
+	for _, f := range c.fields() {
+		s, ok := os.LookupEnv(f.env())
+		if !ok {
+			continue
+		}
+		if err := f.set(s); err != nil {
+			return c, fmt.Errorf("invalid %s: %w", f.env(), err)
+		}
+	}
+
+	if flags != nil {
+		if err := flags.apply(&c); err != nil {
+			return c, err
+		}
+	}
+
+	return c, c.Validate()
+}
+
+// Validate checks the values that have a fixed set of choices, and puts
+// them in canonical form.
+func (c *Config) Validate() error {
+	switch c.Storage {
+	case StorageMongo, StoragePostgres, StorageSQLite, StorageMemory:
+	default:
+		return fmt.Errorf("unknown storage %q", c.Storage)
+	}
+
+	if _, err := c.Level(); err != nil {
+		return err
+	}
+
+	for _, p := range []*reference.Policy{&c.OnDelete.Author, &c.OnDelete.Series, &c.OnDelete.Genre, &c.OnDelete.Work} {
+		policy, err := reference.ParsePolicy(string(*p))
+		if err != nil {
+			return err
+		}
+		*p = policy
+	}
+	return nil
+}
+
+// Level is the configured log level.
+func (c Config) Level() (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		return l, fmt.Errorf("invalid log level %q", c.LogLevel)
+	}
+	return l, nil
+}
+
+// Redacted returns a copy of c safe to print, with the password of
+// connection strings and any other secrets masked.
+func (c Config) Redacted() Config {
+	for _, f := range c.fields() {
+		if f.secret && f.value.String() != "" {
+			f.value.SetString(redact(f.value.String()))
+		}
+	}
+	return c
+}
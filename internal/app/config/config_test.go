@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/literalog/library/internal/app/domain/reference"
+	"github.com/spf13/pflag"
+)
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	file := "addr: \":9000\"\nstorage: memory\nlog_level: debug\ntimeouts:\n  read: 5s\n"
+	if err := os.WriteFile(filepath.Join(dir, "staging.yaml"), []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MONGO_URI", "")
+	t.Setenv("LIBRARY_STORAGE", "mongo")
+	t.Setenv("LIBRARY_TIMEOUTS_READ", "7s")
+
+	flags := NewFlags()
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Register(fs)
+	if err := fs.Parse([]string{"--timeouts-read=9s", "--on-delete-genre=NULLIFY"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load("staging", dir, true, flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Addr != ":9000" {
+		t.Errorf("addr from file: got %q", c.Addr)
+	}
+	if c.Storage != StorageMongo {
+		t.Errorf("storage from env: got %q", c.Storage)
+	}
+	if c.Timeouts.Read != 9*time.Second {
+		t.Errorf("read timeout from flag: got %s", c.Timeouts.Read)
+	}
+	if c.OnDelete.Genre != reference.Nullify {
+		t.Errorf("genre policy from flag: got %q", c.OnDelete.Genre)
+	}
+	if c.Timeouts.Write != Default().Timeouts.Write {
+		t.Errorf("write timeout default: got %s", c.Timeouts.Write)
+	}
+}
+
+func TestLoadLegacyMongoURI(t *testing.T) {
+	const dir = "../../../config"
+	if _, err := os.Stat(filepath.Join(dir, DefaultProfile+".yaml")); err != nil {
+		t.Fatalf("default profile file: %v", err)
+	}
+
+	t.Setenv("LIBRARY_MONGO_URI", "")
+	os.Unsetenv("LIBRARY_MONGO_URI")
+	t.Setenv("MONGO_URI", "mongodb://legacy:27017")
+
+	c, err := Load(DefaultProfile, dir, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Mongo.URI != "mongodb://legacy:27017" {
+		t.Errorf("MONGO_URI over the profile file: got %q", c.Mongo.URI)
+	}
+
+	t.Setenv("LIBRARY_MONGO_URI", "mongodb://current:27017")
+	if c, err = Load(DefaultProfile, dir, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if c.Mongo.URI != "mongodb://current:27017" {
+		t.Errorf("LIBRARY_MONGO_URI over MONGO_URI: got %q", c.Mongo.URI)
+	}
+}
+
+func TestLoadMissingProfile(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := Load("production", dir, true, nil); err == nil {
+		t.Error("expected an error for a missing profile file")
+	}
+	if _, err := Load(DefaultProfile, dir, false, nil); err != nil {
+		t.Errorf("default profile without file: %v", err)
+	}
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "development.yaml"), []byte("adr: \":9000\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(DefaultProfile, dir, false, nil); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	c := Default()
+	c.Mongo.URI = "mongodb://admin:hunter2@db:27017/library"
+
+	b, err := c.Redacted().YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "hunter2") {
+		t.Errorf("password printed:\n%s", b)
+	}
+	if !strings.Contains(string(b), "admin:REDACTED@db:27017") {
+		t.Errorf("redacted uri missing:\n%s", b)
+	}
+	if c.Mongo.URI != "mongodb://admin:hunter2@db:27017/library" {
+		t.Error("Redacted changed the original")
+	}
+}
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"postgres://db:5432/library?sslmode=require&password=hunter2", "postgres://db:5432/library?sslmode=require&password=REDACTED"},
+		{"postgres://u:hunter2@db/library?sslpassword=hunter2&application_name=library", "postgres://u:REDACTED@db/library?sslpassword=REDACTED&application_name=library"},
+		{"mongodb://db:27017/?tlsCertificateKeyFilePassword=hunter2", "mongodb://db:27017/?tlsCertificateKeyFilePassword=REDACTED"},
+		{"mongodb://db:27017/?replicaSet=rs0", "mongodb://db:27017/?replicaSet=rs0"},
+		{"not a url", "REDACTED"},
+	}
+	for _, tt := range tests {
+		if got := redact(tt.in); got != tt.want {
+			t.Errorf("redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field is a single setting, named by the path of its YAML keys, such as
+// "mongo.uri".
+type field struct {
+	key    string
+	usage  string
+	secret bool
+	value  reflect.Value
+}
+
+// fields lists the settings of c in declaration order. Their values can be
+// set through the returned fields.
+func (c *Config) fields() []field {
+	return collect("", reflect.ValueOf(c).Elem())
+}
+
+func collect(prefix string, v reflect.Value) []field {
+	var fields []field
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		key, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		key = prefix + key
+
+		if sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, collect(key+".", v.Field(i))...)
+			continue
+		}
+		fields = append(fields, field{
+			key:    key,
+			usage:  sf.Tag.Get("usage"),
+			secret: sf.Tag.Get("secret") == "true",
+			value:  v.Field(i),
+		})
+	}
+	return fields
+}
+
+// env is the environment variable of the field, such as LIBRARY_MONGO_URI.
+func (f field) env() string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(f.key, ".", "_"))
+}
+
+// flag is the command line flag of the field, such as mongo-uri.
+func (f field) flag() string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(f.key)
+}
+
+func (f field) set(s string) error {
+	switch {
+	case f.value.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(int64(d))
+	case f.value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetBool(b)
+	case f.value.Kind() == reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(int64(n))
+	case f.value.Kind() == reflect.String:
+		f.value.SetString(s)
+	default:
+		return fmt.Errorf("unsupported setting type %s", f.value.Type())
+	}
+	return nil
+}
+
+func (f field) String() string {
+	if f.value.Type() == durationType {
+		return time.Duration(f.value.Int()).String()
+	}
+	return fmt.Sprint(f.value.Interface())
+}
+
+// secretParams are the parts of query parameter names, lower cased, that
+// mark a secret, such as postgres' password and sslpassword or mongo's
+// tlsCertificateKeyFilePassword.
+var secretParams = []string{"password", "passwd", "pwd", "secret", "token"}
+
+// redact masks the password of a connection string and the values of its
+// secret query parameters, or the whole value if it is not one.
+func redact(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "REDACTED"
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "REDACTED")
+	}
+
+	// The query is rewritten in place rather than re-encoded, which would
+	// reorder the parameters.
+	params := strings.Split(u.RawQuery, "&")
+	for i, p := range params {
+		name, _, ok := strings.Cut(p, "=")
+		if !ok {
+			continue
+		}
+		if n, err := url.QueryUnescape(name); err == nil && secret(n) {
+			params[i] = name + "=REDACTED"
+		}
+	}
+	u.RawQuery = strings.Join(params, "&")
+	return u.String()
+}
+
+func secret(param string) bool {
+	param = strings.ToLower(param)
+	for _, s := range secretParams {
+		if strings.Contains(param, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// YAML renders c as a YAML document in declaration order.
+func (c Config) YAML() ([]byte, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, f := range c.fields() {
+		node := root
+		keys := strings.Split(f.key, ".")
+		for _, k := range keys[:len(keys)-1] {
+			node = child(node, k)
+		}
+
+		value := &yaml.Node{Kind: yaml.ScalarNode, Value: f.String()}
+		if f.value.Kind() == reflect.String && f.value.Type() != durationType {
+			value.Tag = "!!str"
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: keys[len(keys)-1]}, value)
+	}
+	var b bytes.Buffer
+	e := yaml.NewEncoder(&b)
+	e.SetIndent(2)
+	if err := e.Encode(root); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// child returns the mapping under key k of node, adding it if missing.
+func child(node *yaml.Node, k string) *yaml.Node {
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == k {
+			return node.Content[i+1]
+		}
+	}
+	m := &yaml.Node{Kind: yaml.MappingNode}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k}, m)
+	return m
+}
+
+// Flags holds the settings given on the command line, which override every
+// other source.
+type Flags struct {
+	values map[string]*flagValue
+}
+
+func NewFlags() *Flags {
+	f := &Flags{values: make(map[string]*flagValue)}
+	c := Default()
+	for _, field := range c.fields() {
+		f.values[field.key] = &flagValue{field: field}
+	}
+	return f
+}
+
+// Register adds a flag for every setting to fs. The same Flags may be
+// registered on several commands.
+func (f *Flags) Register(fs *pflag.FlagSet) {
+	c := Default()
+	for _, field := range c.fields() {
+		fl := fs.VarPF(f.values[field.key], field.flag(), "", field.usage)
+		fl.DefValue = field.String()
+		if field.value.Kind() == reflect.Bool {
+			fl.NoOptDefVal = "true"
+		}
+	}
+}
+
+func (f *Flags) apply(c *Config) error {
+	for _, field := range c.fields() {
+		v := f.values[field.key]
+		if !v.changed {
+			continue
+		}
+		if err := field.set(v.raw); err != nil {
+			return fmt.Errorf("invalid --%s: %w", field.flag(), err)
+		}
+	}
+	return nil
+}
+
+// flagValue records a flag until the configuration is loaded. Its field
+// belongs to a scratch configuration.
+type flagValue struct {
+	field   field
+	raw     string
+	changed bool
+}
+
+func (v *flagValue) String() string {
+	return v.field.String()
+}
+
+// Set parses s into a scratch configuration, so pflag reports bad values.
+func (v *flagValue) Set(s string) error {
+	if err := v.field.set(s); err != nil {
+		return err
+	}
+	v.raw, v.changed = s, true
+	return nil
+}
+
+func (v *flagValue) Type() string {
+	switch {
+	case v.field.value.Type() == durationType:
+		return "duration"
+	case v.field.value.Kind() == reflect.Bool:
+		return "bool"
+	case v.field.value.Kind() == reflect.Int:
+		return "int"
+	default:
+		return "string"
+	}
+}
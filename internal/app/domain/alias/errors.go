@@ -0,0 +1,9 @@
+package alias
+
+import (
+	"net/http"
+
+	"github.com/literalog/cerrors"
+)
+
+var ErrNotFound = cerrors.New("alias not found", http.StatusNotFound)
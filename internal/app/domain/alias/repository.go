@@ -0,0 +1,13 @@
+// Package alias keeps the ids of merged entities pointing to the entity they
+// were merged into.
+package alias
+
+import "context"
+
+type Repository interface {
+	// Redirect makes the ids in from, and the ids already redirected to
+	// them, redirect to id.
+	Redirect(ctx context.Context, id string, from []string) error
+	// Resolve returns the id the given id redirects to, or ErrNotFound.
+	Resolve(ctx context.Context, id string) (string, error)
+}
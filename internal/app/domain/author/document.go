@@ -0,0 +1,18 @@
+package author
+
+import (
+	"github.com/literalog/library/internal/app/domain/search"
+	"github.com/literalog/library/pkg/models"
+)
+
+// Document is the searchable form of an author.
+func Document(a *models.Author) search.Document {
+	return search.Document{
+		Kind:  search.KindAuthor,
+		Id:    a.Id,
+		Title: a.Name,
+		Fields: []search.Field{
+			{Name: "name", Text: a.Name, Weight: 3},
+		},
+	}
+}
@@ -7,5 +7,9 @@ import (
 )
 
 var (
-	ErrEmptyId = cerrors.New("empty id", http.StatusBadRequest)
+	ErrEmptyId         = cerrors.New("empty id", http.StatusBadRequest)
+	ErrNotFound        = cerrors.New("author not found", http.StatusNotFound)
+	ErrConflict        = cerrors.New("an author with this id already exists", http.StatusConflict)
+	ErrEmptyName       = cerrors.New("empty name", http.StatusBadRequest)
+	ErrVersionMismatch = cerrors.New("author was modified since it was read", http.StatusPreconditionFailed)
 )
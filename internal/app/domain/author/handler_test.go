@@ -0,0 +1,52 @@
+package author_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/author"
+	"github.com/literalog/library/pkg/models"
+)
+
+// fakeService fails every lookup with err and resolves every id to
+// "le-guin"; any other call panics on the nil embedded interface.
+type fakeService struct {
+	author.Service
+	err      error
+	resolved int
+}
+
+func (f *fakeService) GetById(ctx context.Context, id string) (*models.Author, error) {
+	return nil, f.err
+}
+
+func (f *fakeService) Resolve(ctx context.Context, id string) (string, error) {
+	f.resolved++
+	return "le-guin", nil
+}
+
+func TestGetByIdResolvesOnlyMissingIds(t *testing.T) {
+	tests := []struct {
+		err      error
+		code     int
+		resolved int
+	}{
+		{err: author.ErrNotFound, code: http.StatusPermanentRedirect, resolved: 1},
+		{err: errors.New("storage unavailable"), code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			s := &fakeService{err: tt.err}
+			w := httptest.NewRecorder()
+			author.NewHandler(s).Routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ursula", nil))
+
+			if w.Code != tt.code || s.resolved != tt.resolved {
+				t.Errorf("got %d after %d resolves, want %d after %d", w.Code, s.resolved, tt.code, tt.resolved)
+			}
+		})
+	}
+}
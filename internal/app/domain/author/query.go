@@ -0,0 +1,28 @@
+package author
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/literalog/cerrors"
+	"github.com/literalog/library/pkg/models"
+)
+
+var sortable = []string{"name"}
+
+type Query struct {
+	models.ListOptions
+	Name string
+}
+
+func NewQuery(v url.Values) (Query, error) {
+	opts, err := models.ParseListOptions(v, sortable...)
+	if err != nil {
+		return Query{}, cerrors.New(err.Error(), http.StatusBadRequest)
+	}
+
+	return Query{
+		ListOptions: opts,
+		Name:        v.Get("name"),
+	}, nil
+}
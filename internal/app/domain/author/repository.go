@@ -8,8 +8,17 @@ import (
 
 type Repository interface {
 	Create(ctx context.Context, a *models.Author) error
+	// Update replaces the stored author if its version is still a.Version, then
+	// moves a to the next version. It returns ErrNotFound or
+	// ErrVersionMismatch otherwise.
 	Update(ctx context.Context, a *models.Author) error
-	Delete(ctx context.Context, id string) error
+	// Delete removes the stored author if its version is still version, or
+	// whatever its version when version is 0. It returns ErrNotFound or
+	// ErrVersionMismatch otherwise.
+	Delete(ctx context.Context, id string, version int64) error
 	GetById(ctx context.Context, id string) (*models.Author, error)
+	// GetByIds returns the stored authors among ids, in no particular order.
+	GetByIds(ctx context.Context, ids []string) ([]models.Author, error)
 	GetAll(ctx context.Context) ([]models.Author, error)
+	List(ctx context.Context, q Query) ([]models.Author, int64, error)
 }
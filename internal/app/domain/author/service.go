@@ -3,44 +3,103 @@ package author
 import (
 	"context"
 
+	"github.com/literalog/library/internal/app/domain/alias"
+	"github.com/literalog/library/internal/app/domain/duplicate"
+	"github.com/literalog/library/internal/app/domain/reference"
+	"github.com/literalog/library/internal/app/domain/search"
 	"github.com/literalog/library/pkg/models"
 )
 
 type Service interface {
 	Create(ctx context.Context, a *models.Author) error
 	Update(ctx context.Context, a *models.Author) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, version int64) error
 	GetById(ctx context.Context, id string) (*models.Author, error)
+	GetByIds(ctx context.Context, ids []string) ([]models.Author, error)
 	GetAll(ctx context.Context) ([]models.Author, error)
+	List(ctx context.Context, q Query) (*models.List[models.Author], error)
+	Duplicates(ctx context.Context, q duplicate.Query) (*models.List[duplicate.Cluster], error)
+	Merge(ctx context.Context, id string, sourceIds []string) (*duplicate.Report, error)
+	Resolve(ctx context.Context, id string) (string, error)
 }
 
 type service struct {
+	*duplicate.Merger[models.Author]
 	repository Repository
+	references reference.Enforcer
+	index      search.Indexer
 	validator  Validator
 }
 
-func NewService(repo Repository) Service {
-	return &service{
+func NewService(repo Repository, refs reference.Enforcer, idx search.Indexer, aliases alias.Repository, replacers reference.Replacers) Service {
+	s := &service{
 		repository: repo,
+		references: refs,
+		index:      idx,
 	}
+	s.Merger = &duplicate.Merger[models.Author]{
+		Get: s.GetById,
+		All: repo.GetAll,
+		Member: func(a *models.Author) duplicate.Member {
+			return duplicate.Member{Id: a.Id, Name: a.Name}
+		},
+		Key: func(a *models.Author) string {
+			return a.Id
+		},
+		Delete:    s.deleteMerged,
+		Replacers: replacers,
+		Aliases:   aliases,
+	}
+	return s
 }
 
 func (s *service) Create(ctx context.Context, a *models.Author) error {
 	if err := s.validator.Validate(a); err != nil {
 		return err
 	}
-	return s.repository.Create(ctx, a)
+	if err := s.repository.Create(ctx, a); err != nil {
+		return err
+	}
+
+	search.Put(ctx, s.index, Document(a))
+	return nil
 }
 
 func (s *service) Update(ctx context.Context, a *models.Author) error {
-	return s.repository.Update(ctx, a)
+	if err := s.validator.Validate(a); err != nil {
+		return err
+	}
+	if err := s.repository.Update(ctx, a); err != nil {
+		return err
+	}
+
+	search.Put(ctx, s.index, Document(a))
+	return nil
 }
 
-func (s *service) Delete(ctx context.Context, id string) error {
+func (s *service) Delete(ctx context.Context, id string, version int64) error {
 	if id == "" {
 		return ErrEmptyId
 	}
-	return s.repository.Delete(ctx, id)
+	// A stale request fails before the reference policies change anything.
+	if version != 0 {
+		stored, err := s.repository.GetById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if stored.Version != version {
+			return ErrVersionMismatch
+		}
+	}
+	if err := s.references.Enforce(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repository.Delete(ctx, id, version); err != nil {
+		return err
+	}
+
+	search.Remove(ctx, s.index, search.KindAuthor, id)
+	return nil
 }
 
 func (s *service) GetById(ctx context.Context, id string) (*models.Author, error) {
@@ -50,6 +109,32 @@ func (s *service) GetById(ctx context.Context, id string) (*models.Author, error
 	return s.repository.GetById(ctx, id)
 }
 
+// GetByIds returns the authors among ids that exist, in a single lookup.
+func (s *service) GetByIds(ctx context.Context, ids []string) ([]models.Author, error) {
+	if len(ids) == 0 {
+		return []models.Author{}, nil
+	}
+	return s.repository.GetByIds(ctx, ids)
+}
+
 func (s *service) GetAll(ctx context.Context) ([]models.Author, error) {
 	return s.repository.GetAll(ctx)
 }
+
+func (s *service) List(ctx context.Context, q Query) (*models.List[models.Author], error) {
+	aa, total, err := s.repository.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewList(aa, total, q.ListOptions), nil
+}
+
+// deleteMerged removes an author merged into another.
+func (s *service) deleteMerged(ctx context.Context, id string) error {
+	if err := s.repository.Delete(ctx, id, 0); err != nil {
+		return err
+	}
+
+	search.Remove(ctx, s.index, search.KindAuthor, id)
+	return nil
+}
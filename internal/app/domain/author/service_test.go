@@ -0,0 +1,49 @@
+package author_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/author"
+	"github.com/literalog/library/internal/app/domain/reference"
+	"github.com/literalog/library/internal/app/domain/search"
+	"github.com/literalog/library/internal/app/gateways/database/memory"
+	"github.com/literalog/library/pkg/models"
+)
+
+// fakeEnforcer counts the keys it is asked to enforce, standing in for a
+// cascade that would change the referrers.
+type fakeEnforcer struct {
+	calls int
+}
+
+func (f *fakeEnforcer) Enforce(ctx context.Context, key string) error {
+	f.calls++
+	return nil
+}
+
+func TestDeleteVersionBeforeReferences(t *testing.T) {
+	ctx := context.Background()
+	refs := &fakeEnforcer{}
+	s := author.NewService(memory.NewAuthorRepository(), refs, search.Indexers{}, memory.NewAliasRepository(), reference.Replacers{})
+
+	a := models.NewAuthor(models.AuthorRequest{Name: "Ursula K. Le Guin"})
+	if err := s.Create(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Delete(ctx, a.Id, a.Version+1); !errors.Is(err, author.ErrVersionMismatch) {
+		t.Errorf("delete stale: got %v, want %v", err, author.ErrVersionMismatch)
+	}
+	if refs.calls != 0 {
+		t.Errorf("delete stale: enforced references %d times, want none", refs.calls)
+	}
+
+	if err := s.Delete(ctx, a.Id, a.Version); err != nil {
+		t.Fatalf("delete current: %v", err)
+	}
+	if refs.calls != 1 {
+		t.Errorf("delete current: enforced references %d times, want once", refs.calls)
+	}
+}
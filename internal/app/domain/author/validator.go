@@ -1,9 +1,8 @@
 package author
 
 import (
-	"errors"
-
 	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
 )
 
 type Validator struct {
@@ -13,18 +12,15 @@ func NewValidator(r Repository) *Validator {
 	return &Validator{}
 }
 
+// Validate returns a *problem.ValidationError with every rule a fails.
 func (v *Validator) Validate(a *models.Author) error {
-	switch {
-	case v.validateName(a.Name) != nil:
-		return errors.New("invalid author")
-	default:
-		return nil
-	}
+	errs := new(problem.ValidationError)
+	v.validateName(errs, a.Name)
+	return errs.Err()
 }
 
-func (v *Validator) validateName(name string) error {
+func (v *Validator) validateName(errs *problem.ValidationError, name string) {
 	if name == "" {
-		return errors.New("empty name")
+		errs.Add("name", problem.CodeRequired, ErrEmptyName)
 	}
-	return nil
 }
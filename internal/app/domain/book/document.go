@@ -0,0 +1,31 @@
+package book
+
+import (
+	"github.com/literalog/library/internal/app/domain/search"
+	"github.com/literalog/library/pkg/models"
+)
+
+// Document is the searchable form of a book. It refers to the authors and
+// series of the book, so searching their names finds it too.
+func Document(b *models.Book) search.Document {
+	doc := search.Document{
+		Kind:  search.KindBook,
+		Id:    b.Id,
+		Title: b.Title,
+		Fields: []search.Field{
+			{Name: "title", Text: b.Title, Weight: 3},
+			{Name: "blurb", Text: b.Blurb, Weight: 1},
+		},
+	}
+	for _, tag := range b.Genre {
+		doc.Fields = append(doc.Fields, search.Field{Name: "genre", Text: tag, Weight: 1})
+	}
+
+	for _, c := range b.Contributors {
+		doc.Refs = append(doc.Refs, search.Ref{Kind: search.KindAuthor, Id: c.AuthorId})
+	}
+	if b.SeriesId != "" {
+		doc.Refs = append(doc.Refs, search.Ref{Kind: search.KindSeries, Id: b.SeriesId})
+	}
+	return doc
+}
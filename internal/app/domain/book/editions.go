@@ -0,0 +1,41 @@
+package book
+
+import (
+	"context"
+
+	"github.com/literalog/library/internal/app/domain/search"
+	"github.com/literalog/library/internal/app/domain/work"
+	"github.com/literalog/library/pkg/models"
+)
+
+type editions struct {
+	repository Repository
+	index      search.Indexer
+}
+
+// NewEditions returns what keeps the editions stored in r in step with their
+// work.
+func NewEditions(r Repository, idx search.Indexer) work.Editions {
+	return &editions{
+		repository: r,
+		index:      idx,
+	}
+}
+
+func (e *editions) Sync(ctx context.Context, w *models.Work) error {
+	// A zero limit returns every edition.
+	bb, _, err := e.repository.GetByWorkId(ctx, w.Id, models.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for i := range bb {
+		b := &bb[i]
+		b.CopyWork(w)
+		if err := e.repository.Update(ctx, b); err != nil {
+			return err
+		}
+		search.Put(ctx, e.index, Document(b))
+	}
+	return nil
+}
@@ -1,15 +1,38 @@
 package book
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/literalog/cerrors"
+	"github.com/literalog/library/pkg/problem"
 )
 
 var (
-	ErrEmptyId            = cerrors.New("empty id", http.StatusBadRequest)
-	ErrInvalidTitle       = cerrors.New("invalid title", http.StatusBadRequest)
-	ErrEmptyTitle         = cerrors.New("empty title", http.StatusBadRequest)
-	ErrInvalidTitleLength = cerrors.New("title must be between x and y", http.StatusBadRequest)
-	ErrAuthorNotFound     = cerrors.New("", http.StatusBadRequest)
+	ErrEmptyId              = cerrors.New("empty id", http.StatusBadRequest)
+	ErrEmptyTitle           = cerrors.New("empty title", http.StatusBadRequest)
+	ErrInvalidTitleLength   = cerrors.New("title must be between 3 and 50 characters", http.StatusBadRequest)
+	ErrAuthorNotFound       = cerrors.New("author not found", http.StatusUnprocessableEntity)
+	ErrSeriesNotFound       = cerrors.New("series not found", http.StatusUnprocessableEntity)
+	ErrWorkNotFound         = cerrors.New("work not found", http.StatusUnprocessableEntity)
+	ErrGenreNotFound        = cerrors.New("genre not found", http.StatusUnprocessableEntity)
+	ErrNotFound             = cerrors.New("book not found", http.StatusNotFound)
+	ErrConflict             = cerrors.New("a book with this id already exists", http.StatusConflict)
+	ErrInvalidFormat        = cerrors.New("invalid format", http.StatusBadRequest)
+	ErrInvalidYear          = cerrors.New("invalid year", http.StatusBadRequest)
+	ErrInvalidRole          = cerrors.New("invalid contributor role", http.StatusBadRequest)
+	ErrNoContributors       = cerrors.New("book must have at least one contributor", http.StatusBadRequest)
+	ErrEmptyContributor     = cerrors.New("contributor without author id", http.StatusBadRequest)
+	ErrDuplicateContributor = cerrors.New("duplicate contributor role", http.StatusBadRequest)
+	ErrInvalidIsbn          = cerrors.New("invalid isbn", http.StatusBadRequest)
+	ErrDuplicateIsbn        = cerrors.New("a book with this isbn already exists", http.StatusConflict)
+	ErrVersionMismatch      = cerrors.New("book was modified since it was read", http.StatusPreconditionFailed)
+	ErrInvalidForce         = cerrors.New("force must be true or false", http.StatusBadRequest)
+	ErrMissingDependency    = errors.New("missing book service dependency")
 )
+
+// ErrProbableDuplicate is the conflict returned when creating a book that
+// probably duplicates stored ones, listing them in its candidate_ids member.
+func ErrProbableDuplicate(ids []string) error {
+	return problem.New(http.StatusConflict, "probable duplicate of stored books").With("candidate_ids", ids)
+}
@@ -1,20 +1,29 @@
 package book
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 
 	"github.com/gorilla/mux"
-	"github.com/literalog/cerrors"
+	"github.com/literalog/library/internal/app/gateways/api/httpio"
 	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
 )
 
 type Handler interface {
 	Create(w http.ResponseWriter, r *http.Request)
 	Update(w http.ResponseWriter, r *http.Request)
+	Patch(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
 	GetAll(w http.ResponseWriter, r *http.Request)
 	GetById(w http.ResponseWriter, r *http.Request)
+	GetByIsbn(w http.ResponseWriter, r *http.Request)
+	GetByAuthor(w http.ResponseWriter, r *http.Request)
+	GetBySeries(w http.ResponseWriter, r *http.Request)
+	GetByGenre(w http.ResponseWriter, r *http.Request)
+	GetByWork(w http.ResponseWriter, r *http.Request)
+	CreateEdition(w http.ResponseWriter, r *http.Request)
 	Routes() *mux.Router
 }
 
@@ -36,9 +45,11 @@ func NewHandler(s Service) Handler {
 
 func (h *handler) setupRoutes() {
 	h.router.HandleFunc("/", h.Create).Methods(http.MethodPost)
-	h.router.HandleFunc("/", h.Update).Methods(http.MethodPut)
+	h.router.HandleFunc("/{id}", h.Update).Methods(http.MethodPut)
+	h.router.HandleFunc("/{id}", h.Patch).Methods(http.MethodPatch)
 	h.router.HandleFunc("/{id}", h.Delete).Methods(http.MethodDelete)
 	h.router.HandleFunc("/{id}", h.GetById).Methods(http.MethodGet)
+	h.router.HandleFunc("/isbn/{isbn}", h.GetByIsbn).Methods(http.MethodGet)
 	h.router.HandleFunc("/", h.GetAll).Methods(http.MethodGet)
 }
 
@@ -48,12 +59,20 @@ func (h handler) Routes() *mux.Router {
 
 func (h *handler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	force, err := parseForce(r.URL.Query().Get("force"))
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
 	req := new(models.BookRequest)
-	json.NewDecoder(r.Body).Decode(&req)
+	if err := httpio.Decode(w, r, req); err != nil {
+		problem.Handle(err, w)
+		return
+	}
 
 	b := models.NewBook(*req)
-	if err := h.service.Create(ctx, b); err != nil {
-		cerrors.Handle(err, w)
+	if err := h.service.Create(ctx, b, force); err != nil {
+		problem.Handle(err, w)
 		return
 	}
 
@@ -64,26 +83,91 @@ func (h *handler) Create(w http.ResponseWriter, r *http.Request) {
 
 func (h *handler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	id := mux.Vars(r)["id"]
 	req := new(models.BookRequest)
-	json.NewDecoder(r.Body).Decode(&req)
+	if err := httpio.Decode(w, r, req); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	stored, err := h.service.GetById(ctx, id)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+	if !httpio.IfMatch(r, stored.Version) {
+		problem.Handle(ErrVersionMismatch, w)
+		return
+	}
 
 	b := models.NewBook(*req)
+	b.Id = id
+	b.Meta = stored.Meta
 	if err := h.service.Update(ctx, b); err != nil {
-		cerrors.Handle(err, w)
+		problem.Handle(err, w)
 		return
 	}
 
+	httpio.SetETag(w, b.Version)
 	w.Header().Add("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(b)
 }
 
+// Patch applies a merge patch or JSON patch to the stored book.
+func (h *handler) Patch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	b, err := h.service.GetById(ctx, id)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+	if !httpio.IfMatch(r, b.Version) {
+		problem.Handle(ErrVersionMismatch, w)
+		return
+	}
+
+	meta := b.Meta
+	if err := httpio.Patch(w, r, b); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	b.Id = id
+	b.Meta = meta
+	b.Normalize()
+	if err := h.service.Update(ctx, b); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	httpio.SetETag(w, b.Version)
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(b)
+}
+
 func (h *handler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := mux.Vars(r)["id"]
 
-	if err := h.service.Delete(ctx, id); err != nil {
-		cerrors.Handle(err, w)
+	var version int64
+	if r.Header.Get("If-Match") != "" {
+		b, err := h.service.GetById(ctx, id)
+		if err != nil {
+			problem.Handle(err, w)
+			return
+		}
+		if !httpio.IfMatch(r, b.Version) {
+			problem.Handle(ErrVersionMismatch, w)
+			return
+		}
+		version = b.Version
+	}
+
+	if err := h.service.Delete(ctx, id, version); err != nil {
+		problem.Handle(err, w)
 		return
 	}
 
@@ -93,9 +177,15 @@ func (h *handler) Delete(w http.ResponseWriter, r *http.Request) {
 func (h *handler) GetAll(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	bb, err := h.service.GetAll(ctx)
+	q, err := NewQuery(r.URL.Query())
 	if err != nil {
-		cerrors.Handle(err, w)
+		problem.Handle(err, w)
+		return
+	}
+
+	bb, err := h.service.List(ctx, q)
+	if err != nil {
+		problem.Handle(err, w)
 		return
 	}
 
@@ -109,10 +199,103 @@ func (h *handler) GetById(w http.ResponseWriter, r *http.Request) {
 
 	b, err := h.service.GetById(ctx, id)
 	if err != nil {
-		cerrors.Handle(err, w)
+		problem.Handle(err, w)
+		return
+	}
+	if httpio.NotModified(w, r, b.Version) {
+		return
+	}
+
+	httpio.SetETag(w, b.Version)
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(b)
+}
+
+func (h *handler) GetByIsbn(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	number := mux.Vars(r)["isbn"]
+
+	b, err := h.service.GetByIsbn(ctx, number)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+	if httpio.NotModified(w, r, b.Version) {
+		return
+	}
+
+	httpio.SetETag(w, b.Version)
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(b)
+}
+
+func (h *handler) GetByAuthor(w http.ResponseWriter, r *http.Request) {
+	role, err := parseRole(r.URL.Query().Get("role"))
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	h.getByParent(w, r, func(ctx context.Context, id string, opts models.ListOptions) (*models.List[models.Book], error) {
+		return h.service.GetByAuthor(ctx, id, role, opts)
+	})
+}
+
+func (h *handler) GetBySeries(w http.ResponseWriter, r *http.Request) {
+	h.getByParent(w, r, h.service.GetBySeries)
+}
+
+func (h *handler) GetByGenre(w http.ResponseWriter, r *http.Request) {
+	h.getByParent(w, r, h.service.GetByGenre)
+}
+
+func (h *handler) GetByWork(w http.ResponseWriter, r *http.Request) {
+	h.getByParent(w, r, h.service.GetByWork)
+}
+
+func (h *handler) CreateEdition(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+	force, err := parseForce(r.URL.Query().Get("force"))
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+	req := new(models.EditionRequest)
+	if err := httpio.Decode(w, r, req); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	b, err := h.service.CreateEdition(ctx, id, *req, force)
+	if err != nil {
+		problem.Handle(err, w)
 		return
 	}
 
 	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(b)
 }
+
+type listByParent func(ctx context.Context, id string, opts models.ListOptions) (*models.List[models.Book], error)
+
+func (h *handler) getByParent(w http.ResponseWriter, r *http.Request, list listByParent) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	opts, err := NewListOptions(r.URL.Query())
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	bb, err := list(ctx, id, opts)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bb)
+}
@@ -0,0 +1,97 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/literalog/library/internal/app/domain/duplicate"
+	"github.com/literalog/library/pkg/models"
+)
+
+// Matcher finds the stored books a new book would duplicate, for the service
+// to check before creating a book and for importers to check before sending
+// one.
+type Matcher struct {
+	repository Repository
+}
+
+func NewMatcher(r Repository) *Matcher {
+	return &Matcher{
+		repository: r,
+	}
+}
+
+// Match lists the stored books b would duplicate.
+type Match struct {
+	// Isbn is the book already published under one of the ISBNs of b, a
+	// certain duplicate.
+	Isbn *models.Book
+	// Candidates are the books with the same normalized title, an author in
+	// common and the same year, probable duplicates. Other editions of the
+	// work of b are not candidates.
+	Candidates []models.Book
+}
+
+// Err returns the error creating b should fail with: ErrDuplicateIsbn for an
+// ISBN match, ErrProbableDuplicate for candidates unless force is set, or
+// nil.
+func (m *Match) Err(force bool) error {
+	switch {
+	case m.Isbn != nil:
+		return ErrDuplicateIsbn
+	case len(m.Candidates) > 0 && !force:
+		ids := make([]string, len(m.Candidates))
+		for i, c := range m.Candidates {
+			ids[i] = c.Id
+		}
+		return ErrProbableDuplicate(ids)
+	default:
+		return nil
+	}
+}
+
+// Match looks for the books b would duplicate. The ISBNs of b must be in
+// canonical form, as the validator leaves them.
+func (m *Matcher) Match(ctx context.Context, b *models.Book) (*Match, error) {
+	match := new(Match)
+
+	for _, number := range b.Isbn {
+		stored, err := m.repository.GetByIsbn(ctx, number)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if stored.Id != b.Id {
+			match.Isbn = stored
+			return match, nil
+		}
+	}
+
+	title := duplicate.NormalizeTitle(b.Title)
+	for _, c := range b.Contributors {
+		if c.Role != models.RoleAuthor {
+			continue
+		}
+
+		bb, _, err := m.repository.GetByAuthorId(ctx, c.AuthorId, models.RoleAuthor, models.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, stored := range bb {
+			switch {
+			case stored.Id == b.Id,
+				stored.Year != b.Year,
+				b.WorkId != "" && stored.WorkId == b.WorkId,
+				duplicate.NormalizeTitle(stored.Title) != title:
+				continue
+			}
+			if !slices.ContainsFunc(match.Candidates, func(x models.Book) bool { return x.Id == stored.Id }) {
+				match.Candidates = append(match.Candidates, stored)
+			}
+		}
+	}
+	return match, nil
+}
@@ -0,0 +1,91 @@
+package book_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/book"
+	"github.com/literalog/library/internal/app/gateways/database/memory"
+	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
+)
+
+func newBook(title, authorId string, year int, isbn ...string) *models.Book {
+	return models.NewBook(models.BookRequest{Title: title, AuthorId: authorId, Year: year, Isbn: isbn})
+}
+
+func TestMatcher(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewBookRepository()
+
+	hobbit := newBook("The Hobbit", "tolkien", 1937, "9780306406157")
+	edition := newBook("The Hobbit", "tolkien", 1937)
+	edition.WorkId = "work"
+	for _, b := range []*models.Book{hobbit, edition} {
+		if err := repo.Create(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		book       *models.Book
+		isbn       bool
+		candidates int
+	}{
+		{"same isbn", newBook("Another Title", "someone", 2001, "9780306406157"), true, 0},
+		{"same title, author and year", newBook("Hobbit, The", "tolkien", 1937), false, 2},
+		{"co-author", &models.Book{Title: "the hobbit", Year: 1937, Contributors: []models.Contributor{
+			{AuthorId: "someone", Role: models.RoleAuthor},
+			{AuthorId: "tolkien", Role: models.RoleAuthor, Order: 1},
+		}}, false, 2},
+		{"edition of the same work", &models.Book{Title: "The Hobbit", Year: 1937, WorkId: "work", Contributors: []models.Contributor{
+			{AuthorId: "tolkien", Role: models.RoleAuthor},
+		}}, false, 1},
+		{"other year", newBook("The Hobbit", "tolkien", 1951), false, 0},
+		{"other author", newBook("The Hobbit", "someone", 1937), false, 0},
+		{"translator only", &models.Book{Title: "The Hobbit", Year: 1937, Contributors: []models.Contributor{
+			{AuthorId: "someone", Role: models.RoleAuthor},
+			{AuthorId: "tolkien", Role: models.RoleTranslator},
+		}}, false, 0},
+		{"the stored book itself", hobbit, false, 1},
+	}
+	m := book.NewMatcher(repo)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := m.Match(ctx, tt.book)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (match.Isbn != nil) != tt.isbn || len(match.Candidates) != tt.candidates {
+				t.Errorf("got isbn match %v and %d candidates, want %v and %d", match.Isbn != nil, len(match.Candidates), tt.isbn, tt.candidates)
+			}
+		})
+	}
+}
+
+func TestMatchErr(t *testing.T) {
+	stored := models.Book{Id: "stored"}
+
+	if err := (&book.Match{Isbn: &stored}).Err(true); !errors.Is(err, book.ErrDuplicateIsbn) {
+		t.Errorf("isbn match with force: got %v, want ErrDuplicateIsbn", err)
+	}
+
+	soft := &book.Match{Candidates: []models.Book{stored}}
+	if err := soft.Err(true); err != nil {
+		t.Errorf("candidates with force: got %v, want nil", err)
+	}
+	p := problem.From(soft.Err(false))
+	if p.Status != http.StatusConflict {
+		t.Errorf("candidates: got status %d, want 409", p.Status)
+	}
+	if ids, _ := p.Extensions["candidate_ids"].([]string); len(ids) != 1 || ids[0] != "stored" {
+		t.Errorf("candidate_ids: got %v", p.Extensions["candidate_ids"])
+	}
+
+	if err := (&book.Match{}).Err(false); err != nil {
+		t.Errorf("no match: got %v, want nil", err)
+	}
+}
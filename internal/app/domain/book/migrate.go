@@ -0,0 +1,61 @@
+package book
+
+import (
+	"context"
+	"strings"
+
+	"github.com/literalog/library/internal/app/domain/work"
+	"github.com/literalog/library/pkg/models"
+)
+
+type WorkMigration struct {
+	Works int `json:"works"`
+	Books int `json:"books"`
+}
+
+// GroupIntoWorks creates a work for every group of books without one that
+// share a title, ignoring case and spacing, and a primary author, and makes
+// the books editions of it. With dryRun nothing is written.
+func GroupIntoWorks(ctx context.Context, books Repository, works work.Repository, dryRun bool) (*WorkMigration, error) {
+	bb, err := books.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m := new(WorkMigration)
+	grouped := make(map[string]*models.Work)
+	for i := range bb {
+		b := &bb[i]
+		if b.WorkId != "" {
+			continue
+		}
+
+		key := workKey(b)
+		w, ok := grouped[key]
+		if !ok {
+			w = models.NewWorkFromBook(b)
+			grouped[key] = w
+			m.Works++
+			if !dryRun {
+				if err := works.Create(ctx, w); err != nil {
+					return m, err
+				}
+			}
+		}
+
+		b.WorkId = w.Id
+		m.Books++
+		if !dryRun {
+			if err := books.Update(ctx, b); err != nil {
+				return m, err
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func workKey(b *models.Book) string {
+	title := strings.Join(strings.Fields(strings.ToLower(b.Title)), " ")
+	return title + "\x00" + b.AuthorId
+}
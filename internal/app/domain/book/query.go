@@ -0,0 +1,121 @@
+package book
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/literalog/cerrors"
+	"github.com/literalog/library/pkg/isbn"
+	"github.com/literalog/library/pkg/models"
+)
+
+var sortable = []string{"title", "year", "series_no", "language", "format", "pages_no", "hours_no"}
+
+type Query struct {
+	models.ListOptions
+	WorkId   string
+	Isbn     string
+	AuthorId string
+	// Role narrows AuthorId to the books the author contributed to in that role.
+	Role     models.Role
+	SeriesId string
+	Genre    string
+	Language string
+	Format   models.Format
+	YearFrom int
+	YearTo   int
+	// Text narrows to the books whose title or blurb has every word of it,
+	// regardless of case, as found by the full-text index of the storage.
+	Text string
+}
+
+func NewQuery(v url.Values) (Query, error) {
+	opts, err := models.ParseListOptions(v, sortable...)
+	if err != nil {
+		return Query{}, cerrors.New(err.Error(), http.StatusBadRequest)
+	}
+
+	q := Query{
+		ListOptions: opts,
+		WorkId:      v.Get("work_id"),
+		AuthorId:    v.Get("author_id"),
+		SeriesId:    v.Get("series_id"),
+		Genre:       v.Get("genre"),
+		Language:    v.Get("language"),
+		Text:        strings.TrimSpace(v.Get("q")),
+	}
+
+	if s := v.Get("isbn"); s != "" {
+		i, err := isbn.Parse(s)
+		if err != nil {
+			return Query{}, ErrInvalidIsbn
+		}
+		q.Isbn = i.String()
+	}
+
+	if q.Role, err = parseRole(v.Get("role")); err != nil {
+		return Query{}, err
+	}
+
+	if s := v.Get("format"); s != "" {
+		if q.Format = models.NewFormat(s); q.Format == "" {
+			return Query{}, ErrInvalidFormat
+		}
+	}
+
+	if q.YearFrom, err = parseYear(v.Get("year_from")); err != nil {
+		return Query{}, err
+	}
+	if q.YearTo, err = parseYear(v.Get("year_to")); err != nil {
+		return Query{}, err
+	}
+
+	return q, nil
+}
+
+// NewListOptions parses the pagination and ordering of the nested book
+// endpoints, which take no filters of their own.
+func NewListOptions(v url.Values) (models.ListOptions, error) {
+	opts, err := models.ParseListOptions(v, sortable...)
+	if err != nil {
+		return opts, cerrors.New(err.Error(), http.StatusBadRequest)
+	}
+	return opts, nil
+}
+
+func parseRole(s string) (models.Role, error) {
+	if s == "" {
+		return "", nil
+	}
+	role := models.NewRole(s)
+	if role == "" {
+		return "", ErrInvalidRole
+	}
+	return role, nil
+}
+
+// parseForce reads the force parameter, which creates a book despite
+// probable duplicates.
+func parseForce(s string) (bool, error) {
+	if s == "" {
+		return false, nil
+	}
+	force, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, ErrInvalidForce
+	}
+	return force, nil
+}
+
+func parseYear(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	year, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, ErrInvalidYear
+	}
+	return year, nil
+}
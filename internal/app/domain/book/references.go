@@ -0,0 +1,140 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/literalog/library/internal/app/domain/reference"
+	"github.com/literalog/library/internal/app/domain/search"
+	"github.com/literalog/library/pkg/models"
+)
+
+type referrers struct {
+	repository Repository
+	index      search.Indexer
+	find       func(ctx context.Context, key string, opts models.ListOptions) ([]models.Book, int64, error)
+	clear      func(b *models.Book, key string)
+	replace    func(b *models.Book, key, with string)
+}
+
+func NewAuthorReferrers(r Repository, idx search.Indexer) reference.Referrers {
+	return &referrers{
+		repository: r,
+		index:      idx,
+		find: func(ctx context.Context, id string, opts models.ListOptions) ([]models.Book, int64, error) {
+			return r.GetByAuthorId(ctx, id, "", opts)
+		},
+		clear: func(b *models.Book, id string) {
+			b.RemoveContributor(id)
+		},
+		replace: func(b *models.Book, id, with string) {
+			b.ReplaceContributor(id, with)
+		},
+	}
+}
+
+func NewSeriesReferrers(r Repository, idx search.Indexer) reference.Referrers {
+	return &referrers{
+		repository: r,
+		index:      idx,
+		find:       r.GetBySeriesId,
+		clear: func(b *models.Book, _ string) {
+			b.SeriesId = ""
+			b.SeriesNo = 0
+		},
+		replace: func(b *models.Book, _, with string) {
+			b.SeriesId = with
+		},
+	}
+}
+
+func NewGenreReferrers(r Repository, idx search.Indexer) reference.Referrers {
+	return &referrers{
+		repository: r,
+		index:      idx,
+		find:       r.GetByGenre,
+		clear: func(b *models.Book, tag string) {
+			b.Genre = slices.DeleteFunc(b.Genre, func(g string) bool {
+				return g == tag
+			})
+		},
+		replace: func(b *models.Book, tag, with string) {
+			b.ReplaceGenre(tag, with)
+		},
+	}
+}
+
+func NewWorkReferrers(r Repository, idx search.Indexer) reference.Referrers {
+	return &referrers{
+		repository: r,
+		index:      idx,
+		find:       r.GetByWorkId,
+		clear: func(b *models.Book, _ string) {
+			b.WorkId = ""
+		},
+		replace: func(b *models.Book, _, with string) {
+			b.WorkId = with
+		},
+	}
+}
+
+func (r *referrers) Find(ctx context.Context, key string) ([]string, error) {
+	// A zero limit returns every referring book.
+	bb, _, err := r.find(ctx, key, models.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(bb))
+	for _, b := range bb {
+		ids = append(ids, b.Id)
+	}
+	return ids, nil
+}
+
+func (r *referrers) Delete(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		// A book deleted meanwhile no longer refers to anything.
+		if err := r.repository.Delete(ctx, id, 0); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		search.Remove(ctx, r.index, search.KindBook, id)
+	}
+	return nil
+}
+
+func (r *referrers) Clear(ctx context.Context, key string, ids []string) error {
+	for _, id := range ids {
+		b, err := r.repository.GetById(ctx, id)
+		if err != nil {
+			return err
+		}
+		r.clear(b, key)
+		if err := r.repository.Update(ctx, b); err != nil {
+			return err
+		}
+		search.Put(ctx, r.index, Document(b))
+	}
+	return nil
+}
+
+func (r *referrers) Replace(ctx context.Context, key, with string) ([]string, error) {
+	ids, err := r.Find(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		b, err := r.repository.GetById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		r.replace(b, key, with)
+		if err := r.repository.Update(ctx, b); err != nil {
+			return nil, err
+		}
+		search.Put(ctx, r.index, Document(b))
+	}
+	return ids, nil
+}
@@ -8,8 +8,20 @@ import (
 
 type Repository interface {
 	Create(ctx context.Context, b *models.Book) error
+	// Update replaces the stored book if its version is still b.Version, then
+	// moves b to the next version. It returns ErrNotFound or
+	// ErrVersionMismatch otherwise.
 	Update(ctx context.Context, b *models.Book) error
-	Delete(ctx context.Context, id string) error
+	// Delete removes the stored book if its version is still version, or
+	// whatever its version when version is 0. It returns ErrNotFound or
+	// ErrVersionMismatch otherwise.
+	Delete(ctx context.Context, id string, version int64) error
 	GetById(ctx context.Context, id string) (*models.Book, error)
+	GetByIsbn(ctx context.Context, isbn string) (*models.Book, error)
 	GetAll(ctx context.Context) ([]models.Book, error)
+	List(ctx context.Context, q Query) ([]models.Book, int64, error)
+	GetByAuthorId(ctx context.Context, authorId string, role models.Role, opts models.ListOptions) ([]models.Book, int64, error)
+	GetBySeriesId(ctx context.Context, seriesId string, opts models.ListOptions) ([]models.Book, int64, error)
+	GetByGenre(ctx context.Context, tag string, opts models.ListOptions) ([]models.Book, int64, error)
+	GetByWorkId(ctx context.Context, workId string, opts models.ListOptions) ([]models.Book, int64, error)
 }
@@ -2,20 +2,35 @@ package book
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/literalog/library/internal/app/domain/author"
 	"github.com/literalog/library/internal/app/domain/genre"
+	"github.com/literalog/library/internal/app/domain/search"
 	"github.com/literalog/library/internal/app/domain/series"
+	"github.com/literalog/library/internal/app/domain/work"
+	"github.com/literalog/library/pkg/isbn"
 	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
 )
 
 type Service interface {
-	Create(ctx context.Context, b *models.Book) error
+	// Create stores b unless it duplicates a stored book: a book with one
+	// of its ISBNs always conflicts, probable duplicates unless force is set.
+	Create(ctx context.Context, b *models.Book, force bool) error
 	Update(ctx context.Context, b *models.Book) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, version int64) error
 	GetById(ctx context.Context, id string) (*models.Book, error)
+	GetByIsbn(ctx context.Context, isbn string) (*models.Book, error)
 	GetAll(ctx context.Context) ([]models.Book, error)
+	List(ctx context.Context, q Query) (*models.List[models.Book], error)
+	GetByAuthor(ctx context.Context, authorId string, role models.Role, opts models.ListOptions) (*models.List[models.Book], error)
+	GetBySeries(ctx context.Context, seriesId string, opts models.ListOptions) (*models.List[models.Book], error)
+	GetByGenre(ctx context.Context, genreId string, opts models.ListOptions) (*models.List[models.Book], error)
+	GetByWork(ctx context.Context, workId string, opts models.ListOptions) (*models.List[models.Book], error)
+	CreateEdition(ctx context.Context, workId string, req models.EditionRequest, force bool) (*models.Book, error)
 }
 
 type service struct {
@@ -23,69 +38,176 @@ type service struct {
 	authorService author.Service
 	seriesService series.Service
 	genreService  genre.Service
-	validator     Validator
+	workService   work.Service
+	index         search.Indexer
+	matcher       *Matcher
+	validator     *Validator
 }
 
-func NewService(repo Repository) Service {
-	return &service{
-		repository: repo,
+// Dependencies are what the book service is built from. Every field is
+// required: the books refer to authors, series, genres and works, and are
+// indexed for search.
+type Dependencies struct {
+	Repository Repository
+	Authors    author.Service
+	Series     series.Service
+	Genres     genre.Service
+	Works      work.Service
+	Index      search.Indexer
+}
+
+// NewService returns the book service, or ErrMissingDependency naming the
+// fields of deps left unset.
+func NewService(deps Dependencies) (Service, error) {
+	var missing []string
+	if deps.Repository == nil {
+		missing = append(missing, "Repository")
+	}
+	if deps.Authors == nil {
+		missing = append(missing, "Authors")
+	}
+	if deps.Series == nil {
+		missing = append(missing, "Series")
+	}
+	if deps.Genres == nil {
+		missing = append(missing, "Genres")
 	}
+	if deps.Works == nil {
+		missing = append(missing, "Works")
+	}
+	if deps.Index == nil {
+		missing = append(missing, "Index")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingDependency, strings.Join(missing, ", "))
+	}
+
+	return &service{
+		repository:    deps.Repository,
+		authorService: deps.Authors,
+		seriesService: deps.Series,
+		genreService:  deps.Genres,
+		workService:   deps.Works,
+		index:         deps.Index,
+		matcher:       NewMatcher(deps.Repository),
+		validator:     NewValidator(),
+	}, nil
 }
 
-func (s *service) Create(ctx context.Context, b *models.Book) error {
-	_, err := s.authorService.GetById(ctx, b.AuthorId)
-	if err != nil {
+func (s *service) Create(ctx context.Context, b *models.Book, force bool) error {
+	if err := s.validator.Validate(b); err != nil {
+		return err
+	}
+	if err := s.checkReferences(ctx, b); err != nil {
 		return err
 	}
 
-	_, err = s.seriesService.GetById(ctx, b.SeriesId)
+	match, err := s.matcher.Match(ctx, b)
 	if err != nil {
 		return err
 	}
-
-	for _, genre := range b.Genre {
-		_, err = s.genreService.GetByName(ctx, genre)
-		if err != nil {
-			return fmt.Errorf("error getting genre %s: %w", genre, err)
-		}
+	if err := match.Err(force); err != nil {
+		return err
 	}
 
-	if err := s.validator.Validate(b); err != nil {
+	if err := s.repository.Create(ctx, b); err != nil {
 		return err
 	}
-	return s.repository.Create(ctx, b)
+
+	search.Put(ctx, s.index, Document(b))
+	return nil
 }
 
 func (s *service) Update(ctx context.Context, b *models.Book) error {
-	_, err := s.authorService.GetById(ctx, b.AuthorId)
-	if err != nil {
+	if err := s.validator.Validate(b); err != nil {
+		return err
+	}
+	if err := s.checkReferences(ctx, b); err != nil {
 		return err
 	}
 
-	_, err = s.seriesService.GetById(ctx, b.SeriesId)
-	if err != nil {
+	if err := s.repository.Update(ctx, b); err != nil {
 		return err
 	}
 
-	for _, genre := range b.Genre {
-		_, err = s.genreService.GetByName(ctx, genre)
-		if err != nil {
-			return fmt.Errorf("error getting genre %s: %w", genre, err)
+	search.Put(ctx, s.index, Document(b))
+	return nil
+}
+
+// checkReferences returns a *problem.ValidationError with every author,
+// series, work and genre b refers to that does not exist. The authors and
+// the genres are each looked up in a single call; the series and the work
+// are optional and only looked up when set.
+func (s *service) checkReferences(ctx context.Context, b *models.Book) error {
+	errs := new(problem.ValidationError)
+
+	ids := make([]string, 0, len(b.Contributors))
+	for _, c := range b.Contributors {
+		ids = append(ids, c.AuthorId)
+	}
+	aa, err := s.authorService.GetByIds(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("error getting authors: %w", err)
+	}
+	authors := make(map[string]bool, len(aa))
+	for _, a := range aa {
+		authors[a.Id] = true
+	}
+	for i, c := range b.Contributors {
+		if c.AuthorId != "" && !authors[c.AuthorId] {
+			errs.Add(fmt.Sprintf("contributors[%d].author_id", i), problem.CodeNotFound, ErrAuthorNotFound)
 		}
 	}
 
-	if err := s.validator.Validate(b); err != nil {
-		return err
+	if b.SeriesId != "" {
+		_, err := s.seriesService.GetById(ctx, b.SeriesId)
+		switch {
+		case errors.Is(err, series.ErrNotFound):
+			errs.Add("series_id", problem.CodeNotFound, ErrSeriesNotFound)
+		case err != nil:
+			return fmt.Errorf("error getting series %s: %w", b.SeriesId, err)
+		}
+	}
+
+	if b.WorkId != "" {
+		_, err := s.workService.GetById(ctx, b.WorkId)
+		switch {
+		case errors.Is(err, work.ErrNotFound):
+			errs.Add("work_id", problem.CodeNotFound, ErrWorkNotFound)
+		case err != nil:
+			return fmt.Errorf("error getting work %s: %w", b.WorkId, err)
+		}
+	}
+
+	if len(b.Genre) > 0 {
+		gg, err := s.genreService.GetByTags(ctx, b.Genre)
+		if err != nil {
+			return fmt.Errorf("error getting genres: %w", err)
+		}
+		genres := make(map[string]bool, len(gg))
+		for _, g := range gg {
+			genres[g.Tag] = true
+		}
+		for i, tag := range b.Genre {
+			if !genres[tag] {
+				errs.Add(fmt.Sprintf("genre[%d]", i), problem.CodeNotFound, ErrGenreNotFound)
+			}
+		}
 	}
 
-	return s.repository.Update(ctx, b)
+	return errs.Err()
 }
 
-func (s *service) Delete(ctx context.Context, id string) error {
+func (s *service) Delete(ctx context.Context, id string, version int64) error {
 	if id == "" {
 		return ErrEmptyId
 	}
-	return s.repository.Delete(ctx, id)
+	if err := s.repository.Delete(ctx, id, version); err != nil {
+		return err
+	}
+
+	search.Remove(ctx, s.index, search.KindBook, id)
+	return nil
 }
 
 func (s *service) GetById(ctx context.Context, id string) (*models.Book, error) {
@@ -95,6 +217,93 @@ func (s *service) GetById(ctx context.Context, id string) (*models.Book, error)
 	return s.repository.GetById(ctx, id)
 }
 
+// GetByIsbn finds the edition with the given ISBN-10 or ISBN-13.
+func (s *service) GetByIsbn(ctx context.Context, number string) (*models.Book, error) {
+	i, err := isbn.Parse(number)
+	if err != nil {
+		return nil, ErrInvalidIsbn
+	}
+	return s.repository.GetByIsbn(ctx, i.String())
+}
+
 func (s *service) GetAll(ctx context.Context) ([]models.Book, error) {
 	return s.repository.GetAll(ctx)
 }
+
+func (s *service) List(ctx context.Context, q Query) (*models.List[models.Book], error) {
+	bb, total, err := s.repository.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewList(bb, total, q.ListOptions), nil
+}
+
+func (s *service) GetByAuthor(ctx context.Context, authorId string, role models.Role, opts models.ListOptions) (*models.List[models.Book], error) {
+	if _, err := s.authorService.GetById(ctx, authorId); err != nil {
+		return nil, err
+	}
+
+	bb, total, err := s.repository.GetByAuthorId(ctx, authorId, role, opts)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewList(bb, total, opts), nil
+}
+
+// GetBySeries lists the books of a series in reading order unless another
+// order is requested.
+func (s *service) GetBySeries(ctx context.Context, seriesId string, opts models.ListOptions) (*models.List[models.Book], error) {
+	if _, err := s.seriesService.GetById(ctx, seriesId); err != nil {
+		return nil, err
+	}
+
+	if len(opts.Sort) == 0 {
+		opts.Sort = []models.SortField{{Field: "series_no"}}
+	}
+
+	bb, total, err := s.repository.GetBySeriesId(ctx, seriesId, opts)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewList(bb, total, opts), nil
+}
+
+func (s *service) GetByGenre(ctx context.Context, genreId string, opts models.ListOptions) (*models.List[models.Book], error) {
+	g, err := s.genreService.GetById(ctx, genreId)
+	if err != nil {
+		return nil, err
+	}
+
+	bb, total, err := s.repository.GetByGenre(ctx, g.Tag, opts)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewList(bb, total, opts), nil
+}
+
+func (s *service) GetByWork(ctx context.Context, workId string, opts models.ListOptions) (*models.List[models.Book], error) {
+	if _, err := s.workService.GetById(ctx, workId); err != nil {
+		return nil, err
+	}
+
+	bb, total, err := s.repository.GetByWorkId(ctx, workId, opts)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewList(bb, total, opts), nil
+}
+
+// CreateEdition creates a book under an existing work, taking everything but
+// the format specific fields from the work.
+func (s *service) CreateEdition(ctx context.Context, workId string, req models.EditionRequest, force bool) (*models.Book, error) {
+	w, err := s.workService.GetById(ctx, workId)
+	if err != nil {
+		return nil, err
+	}
+
+	b := models.NewEdition(w, req)
+	if err := s.Create(ctx, b, force); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
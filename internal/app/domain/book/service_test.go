@@ -0,0 +1,234 @@
+package book_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/author"
+	"github.com/literalog/library/internal/app/domain/book"
+	"github.com/literalog/library/internal/app/domain/genre"
+	"github.com/literalog/library/internal/app/domain/search"
+	"github.com/literalog/library/internal/app/domain/series"
+	"github.com/literalog/library/internal/app/domain/work"
+	"github.com/literalog/library/internal/app/gateways/database/memory"
+	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
+)
+
+// The fakes implement only the lookups the book service makes; any other
+// call panics on the nil embedded interface.
+
+type fakeAuthors struct {
+	author.Service
+	ids   map[string]bool
+	calls int
+}
+
+func (f *fakeAuthors) GetByIds(ctx context.Context, ids []string) ([]models.Author, error) {
+	f.calls++
+	aa := []models.Author{}
+	for _, id := range ids {
+		if f.ids[id] {
+			aa = append(aa, models.Author{Id: id})
+		}
+	}
+	return aa, nil
+}
+
+type fakeSeries struct {
+	series.Service
+	ids   map[string]bool
+	calls int
+}
+
+func (f *fakeSeries) GetById(ctx context.Context, id string) (*models.Series, error) {
+	f.calls++
+	if !f.ids[id] {
+		return nil, series.ErrNotFound
+	}
+	return &models.Series{Id: id}, nil
+}
+
+type fakeWorks struct {
+	work.Service
+	ids   map[string]bool
+	calls int
+}
+
+func (f *fakeWorks) GetById(ctx context.Context, id string) (*models.Work, error) {
+	f.calls++
+	if !f.ids[id] {
+		return nil, work.ErrNotFound
+	}
+	return &models.Work{Id: id}, nil
+}
+
+type fakeGenres struct {
+	genre.Service
+	tags  map[string]bool
+	calls int
+}
+
+func (f *fakeGenres) GetByTags(ctx context.Context, tags []string) ([]models.Genre, error) {
+	f.calls++
+	gg := []models.Genre{}
+	for _, tag := range tags {
+		if f.tags[tag] {
+			gg = append(gg, models.Genre{Tag: tag})
+		}
+	}
+	return gg, nil
+}
+
+type fakes struct {
+	authors *fakeAuthors
+	series  *fakeSeries
+	works   *fakeWorks
+	genres  *fakeGenres
+}
+
+func newFakes() fakes {
+	return fakes{
+		authors: &fakeAuthors{ids: map[string]bool{"tolkien": true, "tolkien-c": true}},
+		series:  &fakeSeries{ids: map[string]bool{"middle-earth": true}},
+		works:   &fakeWorks{ids: map[string]bool{"silmarillion": true}},
+		genres:  &fakeGenres{tags: map[string]bool{"fantasy": true, "myth": true}},
+	}
+}
+
+func newTestService(t *testing.T, f fakes) book.Service {
+	t.Helper()
+
+	s, err := book.NewService(book.Dependencies{
+		Repository: memory.NewBookRepository(),
+		Authors:    f.authors,
+		Series:     f.series,
+		Genres:     f.genres,
+		Works:      f.works,
+		Index:      search.Indexers{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func fields(err error) []string {
+	var verr *problem.ValidationError
+	if !errors.As(err, &verr) {
+		return nil
+	}
+	ff := make([]string, 0, len(verr.Errors))
+	for _, fe := range verr.Errors {
+		if fe.Code == problem.CodeNotFound {
+			ff = append(ff, fe.Field)
+		}
+	}
+	return ff
+}
+
+func TestCreateChecksReferences(t *testing.T) {
+	const (
+		missingAuthor = 1 << iota
+		missingSeries
+		missingWork
+		missingGenre
+	)
+
+	for missing := 0; missing < 1<<4; missing++ {
+		b := &models.Book{
+			Title:    "The Silmarillion",
+			Year:     1977,
+			SeriesId: "middle-earth",
+			WorkId:   "silmarillion",
+			Genre:    []string{"fantasy", "myth"},
+			Contributors: []models.Contributor{
+				{AuthorId: "tolkien", Role: models.RoleAuthor},
+				{AuthorId: "tolkien-c", Role: models.RoleEditor},
+			},
+		}
+		want := []string{}
+		if missing&missingAuthor != 0 {
+			b.Contributors[1].AuthorId = "nobody"
+			want = append(want, "contributors[1].author_id")
+		}
+		if missing&missingSeries != 0 {
+			b.SeriesId = "nowhere"
+			want = append(want, "series_id")
+		}
+		if missing&missingWork != 0 {
+			b.WorkId = "nothing"
+			want = append(want, "work_id")
+		}
+		if missing&missingGenre != 0 {
+			b.Genre[0] = "unknown"
+			want = append(want, "genre[0]")
+		}
+
+		t.Run(fmt.Sprintf("missing %v", want), func(t *testing.T) {
+			f := newFakes()
+			s := newTestService(t, f)
+
+			err := s.Create(context.Background(), b, false)
+			if len(want) == 0 {
+				if err != nil {
+					t.Fatalf("got %v, want nil", err)
+				}
+			} else if got := fields(err); !slices.Equal(got, want) {
+				t.Errorf("got not found fields %v (%v), want %v", got, err, want)
+			}
+
+			if f.authors.calls != 1 || f.series.calls != 1 || f.works.calls != 1 || f.genres.calls != 1 {
+				t.Errorf("got %d author, %d series, %d work and %d genre lookups, want one of each",
+					f.authors.calls, f.series.calls, f.works.calls, f.genres.calls)
+			}
+		})
+	}
+}
+
+func TestCreateSkipsOptionalReferences(t *testing.T) {
+	f := newFakes()
+	s := newTestService(t, f)
+
+	b := newBook("The Hobbit", "tolkien", 1937)
+	if err := s.Create(context.Background(), b, false); err != nil {
+		t.Fatal(err)
+	}
+	if f.series.calls != 0 || f.works.calls != 0 || f.genres.calls != 0 {
+		t.Errorf("got %d series, %d work and %d genre lookups, want none", f.series.calls, f.works.calls, f.genres.calls)
+	}
+}
+
+func TestUpdateChecksReferences(t *testing.T) {
+	f := newFakes()
+	s := newTestService(t, f)
+	ctx := context.Background()
+
+	b := newBook("The Hobbit", "tolkien", 1937)
+	if err := s.Create(ctx, b, false); err != nil {
+		t.Fatal(err)
+	}
+
+	b.SeriesId = "nowhere"
+	if got := fields(s.Update(ctx, b)); !slices.Equal(got, []string{"series_id"}) {
+		t.Errorf("got not found fields %v, want [series_id]", got)
+	}
+}
+
+func TestNewServiceRequiresDependencies(t *testing.T) {
+	f := newFakes()
+	_, err := book.NewService(book.Dependencies{
+		Repository: memory.NewBookRepository(),
+		Authors:    f.authors,
+		Works:      f.works,
+	})
+	if !errors.Is(err, book.ErrMissingDependency) {
+		t.Fatalf("got %v, want ErrMissingDependency", err)
+	}
+	if want := "missing book service dependency: Series, Genres, Index"; err.Error() != want {
+		t.Errorf("got %q, want %q", err, want)
+	}
+}
@@ -1,30 +1,106 @@
 package book
 
-import "github.com/literalog/library/pkg/models"
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/literalog/library/pkg/isbn"
+	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
+)
 
 type Validator struct{}
 
-func NewValidator(r Repository) *Validator {
+func NewValidator() *Validator {
 	return &Validator{}
 }
 
+// Validate returns a *problem.ValidationError with every rule b fails. It
+// also rewrites the ISBNs of b in canonical ISBN-13 form and its format in
+// canonical case.
 func (v *Validator) Validate(b *models.Book) error {
-	switch {
-	case v.validateTitle(b.Title) != nil:
-		return ErrInvalidTitle
-	default:
-		return nil
-	}
+	errs := new(problem.ValidationError)
+	v.validateTitle(errs, b.Title)
+	v.validateContributors(errs, b.Contributors)
+	v.validateIsbn(errs, b)
+	v.validateFormat(errs, b)
+	v.validateYear(errs, b.Year)
+	return errs.Err()
 }
 
-func (v *Validator) validateTitle(title string) error {
+func (v *Validator) validateTitle(errs *problem.ValidationError, title string) {
 	if title == "" {
-		return ErrEmptyTitle
+		errs.Add("title", problem.CodeRequired, ErrEmptyTitle)
+		return
 	}
 
 	if len(title) < 3 || len(title) > 50 {
-		return ErrInvalidTitleLength
+		errs.Add("title", problem.CodeLength, ErrInvalidTitleLength)
+	}
+}
+
+func (v *Validator) validateContributors(errs *problem.ValidationError, cc []models.Contributor) {
+	if len(cc) == 0 {
+		errs.Add("contributors", problem.CodeRequired, ErrNoContributors)
+		return
+	}
+
+	seen := make(map[models.Contributor]bool, len(cc))
+	for i, c := range cc {
+		field := fmt.Sprintf("contributors[%d]", i)
+		if c.AuthorId == "" {
+			errs.Add(field+".author_id", problem.CodeRequired, ErrEmptyContributor)
+		}
+		if models.NewRole(string(c.Role)) == "" {
+			errs.Add(field+".role", problem.CodeInvalid, ErrInvalidRole)
+		}
+
+		key := models.Contributor{AuthorId: c.AuthorId, Role: c.Role}
+		if seen[key] {
+			errs.Add(field, problem.CodeDuplicate, ErrDuplicateContributor)
+		}
+		seen[key] = true
+	}
+}
+
+func (v *Validator) validateIsbn(errs *problem.ValidationError, b *models.Book) {
+	valid := true
+	canonical := make([]string, 0, len(b.Isbn))
+	for i, s := range b.Isbn {
+		number, err := isbn.Parse(s)
+		if err != nil {
+			errs.Add(fmt.Sprintf("isbn[%d]", i), problem.CodeInvalid, err)
+			valid = false
+			continue
+		}
+		// The ISBN-10 and ISBN-13 of an edition share a canonical form.
+		if !slices.Contains(canonical, number.String()) {
+			canonical = append(canonical, number.String())
+		}
+	}
+
+	if valid && len(b.Isbn) > 0 {
+		b.Isbn = canonical
+	}
+}
+
+func (v *Validator) validateFormat(errs *problem.ValidationError, b *models.Book) {
+	if b.Format == "" {
+		return
 	}
 
-	return nil
+	format := models.NewFormat(string(b.Format))
+	if format == "" {
+		errs.Add("format", problem.CodeInvalid, ErrInvalidFormat)
+		return
+	}
+	b.Format = format
+}
+
+// validateYear accepts a zero year, which leaves the year unknown.
+func (v *Validator) validateYear(errs *problem.ValidationError, year int) {
+	if year < 0 || year > time.Now().Year() {
+		errs.Add("year", problem.CodeInvalid, ErrInvalidYear)
+	}
 }
@@ -0,0 +1,66 @@
+package book_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/literalog/library/internal/app/domain/book"
+	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
+)
+
+func TestValidateFormatAndYear(t *testing.T) {
+	tests := []struct {
+		name   string
+		format models.Format
+		year   int
+		want   []string
+	}{
+		{name: "unset"},
+		{name: "known", format: "paperback", year: 1977},
+		{name: "this year", format: models.Audio, year: time.Now().Year()},
+		{name: "unknown format", format: "scroll", year: 1977, want: []string{"format"}},
+		{name: "negative year", format: models.Hardcover, year: -1, want: []string{"year"}},
+		{name: "future year", year: time.Now().Year() + 1, want: []string{"year"}},
+		{name: "both", format: "scroll", year: -1, want: []string{"format", "year"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &models.Book{
+				Title:        "The Silmarillion",
+				Contributors: []models.Contributor{{AuthorId: "tolkien", Role: models.RoleAuthor}},
+				Format:       tt.format,
+				Year:         tt.year,
+			}
+			err := book.NewValidator().Validate(b)
+
+			var got []string
+			var verr *problem.ValidationError
+			if errors.As(err, &verr) {
+				for _, fe := range verr.Errors {
+					got = append(got, fe.Field)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got invalid fields %v (%v), want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCanonicalFormat(t *testing.T) {
+	b := &models.Book{
+		Title:        "The Silmarillion",
+		Contributors: []models.Contributor{{AuthorId: "tolkien", Role: models.RoleAuthor}},
+		Format:       "PAPERBACK",
+	}
+	if err := book.NewValidator().Validate(b); err != nil {
+		t.Fatal(err)
+	}
+	if b.Format != models.Paperback {
+		t.Errorf("got format %q, want %q", b.Format, models.Paperback)
+	}
+}
@@ -0,0 +1,108 @@
+package duplicate
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Member is an entity of a cluster.
+type Member struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Cluster groups entities whose names are probably the same. Score is the
+// lowest similarity linking them.
+type Cluster struct {
+	Score   float64  `json:"score"`
+	Members []Member `json:"members"`
+}
+
+// blockLength is the length of the word prefixes names are compared by:
+// only names sharing a word starting with the same letters are compared,
+// rather than every pair.
+const blockLength = 3
+
+// Find groups the members whose names have a similarity of at least
+// threshold, directly or through other members, best clusters first.
+func Find(members []Member, threshold float64) []Cluster {
+	names := make([]string, len(members))
+	blocks := make(map[string][]int)
+	for i, m := range members {
+		names[i] = Normalize(m.Name)
+		for _, w := range strings.Fields(names[i]) {
+			if utf8.RuneCountInString(w) > blockLength {
+				w = string([]rune(w)[:blockLength])
+			}
+			blocks[w] = append(blocks[w], i)
+		}
+	}
+
+	parent := make([]int, len(members))
+	score := make([]float64, len(members))
+	for i := range parent {
+		parent[i] = i
+		score[i] = 1
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+
+	type pair struct{ i, j int }
+	compared := make(map[pair]bool)
+	for _, block := range blocks {
+		for x, i := range block {
+			for _, j := range block[x+1:] {
+				p := pair{min(i, j), max(i, j)}
+				if i == j || compared[p] {
+					continue
+				}
+				compared[p] = true
+
+				s := similarity(names[i], names[j])
+				if s < threshold {
+					continue
+				}
+				ri, rj := root(i), root(j)
+				if ri != rj {
+					parent[rj] = ri
+				}
+				score[ri] = min(score[ri], score[rj], s)
+			}
+		}
+	}
+
+	groups := make(map[int][]Member)
+	for i, m := range members {
+		r := root(i)
+		groups[r] = append(groups[r], m)
+	}
+
+	var clusters []Cluster
+	for r, mm := range groups {
+		if len(mm) < 2 {
+			continue
+		}
+		slices.SortFunc(mm, func(a, b Member) int {
+			if c := strings.Compare(a.Name, b.Name); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Id, b.Id)
+		})
+		clusters = append(clusters, Cluster{Score: score[r], Members: mm})
+	}
+
+	slices.SortFunc(clusters, func(a, b Cluster) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Members[0].Name, b.Members[0].Name)
+	})
+	return clusters
+}
@@ -0,0 +1,14 @@
+package duplicate
+
+import (
+	"net/http"
+
+	"github.com/literalog/cerrors"
+)
+
+var (
+	ErrInvalidThreshold = cerrors.New("threshold must be greater than 0 and at most 1", http.StatusBadRequest)
+	ErrNoSources        = cerrors.New("source_ids must list the ids to merge", http.StatusBadRequest)
+	ErrEmptySourceId    = cerrors.New("empty source id", http.StatusBadRequest)
+	ErrMergeIntoSelf    = cerrors.New("cannot merge an entity into itself", http.StatusBadRequest)
+)
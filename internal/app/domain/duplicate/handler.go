@@ -0,0 +1,67 @@
+package duplicate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/literalog/library/internal/app/gateways/api/httpio"
+	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
+
+	"github.com/gorilla/mux"
+)
+
+// Service is what Handler needs of a service, as provided by Merger.
+type Service interface {
+	Duplicates(ctx context.Context, q Query) (*models.List[Cluster], error)
+	Merge(ctx context.Context, id string, sourceIds []string) (*Report, error)
+}
+
+// Handler serves the duplicates and merge routes of a kind of entity.
+// Authors, series and genres embed one in their handler.
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+func (h *Handler) Duplicates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	q, err := NewQuery(r.URL.Query())
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	cc, err := h.service.Duplicates(ctx, q)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cc)
+}
+
+func (h *Handler) Merge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+	req := new(MergeRequest)
+	if err := httpio.Decode(w, r, req); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	report, err := h.service.Merge(ctx, id, req.SourceIds)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
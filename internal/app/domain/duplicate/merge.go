@@ -0,0 +1,49 @@
+package duplicate
+
+import (
+	"slices"
+
+	"github.com/literalog/library/internal/app/domain/reference"
+)
+
+// MergeRequest lists the entities to merge into the target of a merge.
+type MergeRequest struct {
+	SourceIds []string `json:"source_ids"`
+}
+
+// Report tells what a merge changed: the entities merged into the target,
+// now deleted and redirecting to it, and by kind of referrer the ids of
+// what was rewritten to refer to the target.
+type Report struct {
+	TargetId string             `json:"target_id"`
+	Merged   []string           `json:"merged_ids"`
+	Replaced reference.Replaced `json:"replaced"`
+}
+
+func NewReport(targetId string, sourceIds []string) *Report {
+	return &Report{
+		TargetId: targetId,
+		Merged:   sourceIds,
+		Replaced: make(reference.Replaced),
+	}
+}
+
+// Sources validates the ids to merge into targetId, dropping repeated ones.
+func Sources(targetId string, sourceIds []string) ([]string, error) {
+	if len(sourceIds) == 0 {
+		return nil, ErrNoSources
+	}
+
+	ids := make([]string, 0, len(sourceIds))
+	for _, id := range sourceIds {
+		switch {
+		case id == "":
+			return nil, ErrEmptySourceId
+		case id == targetId:
+			return nil, ErrMergeIntoSelf
+		case !slices.Contains(ids, id):
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
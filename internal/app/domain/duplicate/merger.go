@@ -0,0 +1,87 @@
+package duplicate
+
+import (
+	"context"
+
+	"github.com/literalog/library/internal/app/domain/alias"
+	"github.com/literalog/library/internal/app/domain/reference"
+	"github.com/literalog/library/pkg/models"
+)
+
+// Merger finds and merges the duplicates of a kind of entity T. Authors,
+// series and genres embed one in their service.
+type Merger[T any] struct {
+	// Get returns the entity with the given id.
+	Get func(ctx context.Context, id string) (*T, error)
+	// All returns every entity.
+	All func(ctx context.Context) ([]T, error)
+	// Member returns the id of v and the name it is clustered by.
+	Member func(v *T) Member
+	// Key returns what referrers refer to v by, its id unless they use
+	// another key such as the tag of a genre.
+	Key func(v *T) string
+	// Delete removes a merged entity, whatever its version.
+	Delete func(ctx context.Context, id string) error
+
+	Replacers reference.Replacers
+	Aliases   alias.Repository
+}
+
+// Duplicates clusters the entities with similar names.
+func (m *Merger[T]) Duplicates(ctx context.Context, q Query) (*models.List[Cluster], error) {
+	vv, err := m.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]Member, len(vv))
+	for i := range vv {
+		members[i] = m.Member(&vv[i])
+	}
+	return List(members, q), nil
+}
+
+// Merge folds the source entities into the entity with the given id: their
+// referrers refer to it instead, then the sources are deleted and their ids
+// redirect to it.
+func (m *Merger[T]) Merge(ctx context.Context, id string, sourceIds []string) (*Report, error) {
+	sourceIds, err := Sources(id, sourceIds)
+	if err != nil {
+		return nil, err
+	}
+	target, err := m.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]*T, len(sourceIds))
+	for i, sourceId := range sourceIds {
+		if sources[i], err = m.Get(ctx, sourceId); err != nil {
+			return nil, err
+		}
+	}
+
+	report := NewReport(id, sourceIds)
+	for _, source := range sources {
+		replaced, err := m.Replacers.Replace(ctx, m.Key(source), m.Key(target))
+		if err != nil {
+			return nil, err
+		}
+		report.Replaced.Add(replaced)
+	}
+
+	// Redirect before deleting, so a merge failing halfway can be retried.
+	if err := m.Aliases.Redirect(ctx, id, sourceIds); err != nil {
+		return nil, err
+	}
+	for _, sourceId := range sourceIds {
+		if err := m.Delete(ctx, sourceId); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// Resolve returns the id of the entity a merged entity was merged into.
+func (m *Merger[T]) Resolve(ctx context.Context, id string) (string, error) {
+	return m.Aliases.Resolve(ctx, id)
+}
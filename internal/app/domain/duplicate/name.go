@@ -0,0 +1,125 @@
+// Package duplicate finds entities that probably stand for the same thing
+// and describes their merge.
+package duplicate
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize reduces a name to a canonical form, so that "J.R.R. Tolkien",
+// "JRR Tolkien" and "Tolkien, J. R. R." all become "jrr tolkien": case,
+// diacritics and punctuation are dropped, an inverted "Last, First" name is
+// turned around, runs of initials are joined and words are sorted.
+func Normalize(name string) string {
+	if last, first, ok := strings.Cut(name, ","); ok {
+		name = first + " " + last
+	}
+
+	var joined []string
+	initials := ""
+	for _, w := range words(fold(name)) {
+		if utf8.RuneCountInString(w) == 1 {
+			initials += w
+			continue
+		}
+		if initials != "" {
+			joined = append(joined, initials)
+			initials = ""
+		}
+		joined = append(joined, w)
+	}
+	if initials != "" {
+		joined = append(joined, initials)
+	}
+
+	slices.Sort(joined)
+	return strings.Join(joined, " ")
+}
+
+// articles are dropped from the start of titles.
+var articles = []string{"the", "a", "an"}
+
+// NormalizeTitle reduces a title to a canonical form, so that "The Hobbit",
+// "Hobbit, The" and "the hobbit!" all become "hobbit": case, diacritics,
+// punctuation and a leading article are dropped. Unlike names, the order of
+// the words matters.
+func NormalizeTitle(title string) string {
+	title = fold(title)
+	if rest, last, ok := cut(title); ok && slices.Contains(articles, last) {
+		title = last + " " + rest
+	}
+
+	ww := words(title)
+	if len(ww) > 1 && slices.Contains(articles, ww[0]) {
+		ww = ww[1:]
+	}
+	return strings.Join(ww, " ")
+}
+
+// cut splits s around its last comma, trimming the part after it.
+func cut(s string) (before, after string, ok bool) {
+	i := strings.LastIndex(s, ",")
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], strings.TrimSpace(s[i+1:]), true
+}
+
+// Similarity scores how alike two names are, from 0 to 1, as one minus the
+// edit distance between their normalized forms relative to the longer one.
+func Similarity(a, b string) float64 {
+	return similarity(Normalize(a), Normalize(b))
+}
+
+func similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	return 1 - float64(distance(ra, rb))/float64(longest)
+}
+
+// distance is the Levenshtein distance between a and b.
+func distance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// fold lower cases s and strips its diacritics.
+func fold(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	if folded, _, err := transform.String(t, s); err == nil {
+		s = folded
+	}
+	return strings.ToLower(s)
+}
+
+// words splits s into words of letters and digits.
+func words(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
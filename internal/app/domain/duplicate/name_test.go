@@ -0,0 +1,87 @@
+package duplicate
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"J.R.R. Tolkien", "jrr tolkien"},
+		{"JRR Tolkien", "jrr tolkien"},
+		{"Tolkien, J. R. R.", "jrr tolkien"},
+		{"Gabriel García Márquez", "gabriel garcia marquez"},
+		{"science-fiction", "fiction science"},
+		{"  Ursula K. Le Guin ", "guin k le ursula"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.name); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if s := Similarity("J.R.R. Tolkien", "Tolkien, J. R. R."); s != 1 {
+		t.Errorf("same name: got %v, want 1", s)
+	}
+	if s := Similarity("J.R.R. Tolkien", "J.R.R. Tolkein"); s < DefaultThreshold {
+		t.Errorf("typo: got %v, want at least %v", s, DefaultThreshold)
+	}
+	if s := Similarity("Tolkien", "Lewis"); s >= DefaultThreshold {
+		t.Errorf("different names: got %v, want below %v", s, DefaultThreshold)
+	}
+}
+
+func TestFind(t *testing.T) {
+	members := []Member{
+		{Id: "1", Name: "J.R.R. Tolkien"},
+		{Id: "2", Name: "C. S. Lewis"},
+		{Id: "3", Name: "Tolkien, J. R. R."},
+		{Id: "4", Name: "JRR Tolkein"},
+		{Id: "5", Name: "Clive Staples Lewis"},
+		{Id: "6", Name: "CS Lewis"},
+	}
+
+	cc := Find(members, DefaultThreshold)
+	if len(cc) != 2 {
+		t.Fatalf("got %d clusters, want 2: %+v", len(cc), cc)
+	}
+
+	// The exact Lewis duplicates score higher than the Tolkiens, linked
+	// through a typo.
+	ids := func(c Cluster) (ids []string) {
+		for _, m := range c.Members {
+			ids = append(ids, m.Id)
+		}
+		return ids
+	}
+	if got := ids(cc[0]); len(got) != 2 || got[0] != "2" || got[1] != "6" || cc[0].Score != 1 {
+		t.Errorf("first cluster: got %v scored %v, want [2 6] scored 1", got, cc[0].Score)
+	}
+	if got := ids(cc[1]); len(got) != 3 || cc[1].Score >= 1 {
+		t.Errorf("second cluster: got %v scored %v, want the three Tolkiens", got, cc[1].Score)
+	}
+}
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"The Hobbit", "hobbit"},
+		{"Hobbit, The", "hobbit"},
+		{"the hobbit!", "hobbit"},
+		{"Cien años de soledad", "cien anos de soledad"},
+		{"Dune Messiah", "dune messiah"},
+		{"A", "a"},
+		{"Harry Potter, Book 1", "harry potter book 1"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeTitle(tt.title); got != tt.want {
+			t.Errorf("NormalizeTitle(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package duplicate
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/literalog/cerrors"
+	"github.com/literalog/library/pkg/models"
+)
+
+// DefaultThreshold is the similarity above which names are reported as
+// probable duplicates, letting through a typo in a dozen letters.
+const DefaultThreshold = 0.8
+
+// Query lists the clusters of names with a similarity of at least
+// Threshold. Clusters are ranked, so they cannot be sorted.
+type Query struct {
+	models.ListOptions
+	Threshold float64
+}
+
+func NewQuery(v url.Values) (Query, error) {
+	opts, err := models.ParseListOptions(v)
+	if err != nil {
+		return Query{}, cerrors.New(err.Error(), http.StatusBadRequest)
+	}
+
+	q := Query{
+		ListOptions: opts,
+		Threshold:   DefaultThreshold,
+	}
+	if s := v.Get("threshold"); s != "" {
+		t, err := strconv.ParseFloat(s, 64)
+		if err != nil || t <= 0 || t > 1 {
+			return Query{}, ErrInvalidThreshold
+		}
+		q.Threshold = t
+	}
+	return q, nil
+}
+
+// List finds the clusters among members and returns the requested page.
+func List(members []Member, q Query) *models.List[Cluster] {
+	cc := Find(members, q.Threshold)
+	total := int64(len(cc))
+
+	page := []Cluster{}
+	if q.Offset < len(cc) {
+		page = cc[q.Offset:]
+		if q.Limit > 0 && q.Limit < len(page) {
+			page = page[:q.Limit]
+		}
+	}
+	return models.NewList(page, total, q.ListOptions)
+}
@@ -0,0 +1,18 @@
+package genre
+
+import (
+	"github.com/literalog/library/internal/app/domain/search"
+	"github.com/literalog/library/pkg/models"
+)
+
+// Document is the searchable form of a genre.
+func Document(g *models.Genre) search.Document {
+	return search.Document{
+		Kind:  search.KindGenre,
+		Id:    g.Id,
+		Title: g.Tag,
+		Fields: []search.Field{
+			{Name: "tag", Text: g.Tag, Weight: 2},
+		},
+	}
+}
@@ -0,0 +1,15 @@
+package genre
+
+import (
+	"net/http"
+
+	"github.com/literalog/cerrors"
+)
+
+var (
+	ErrNotFound        = cerrors.New("genre not found", http.StatusNotFound)
+	ErrConflict        = cerrors.New("a genre with this id already exists", http.StatusConflict)
+	ErrDuplicateTag    = cerrors.New("a genre with this tag already exists", http.StatusConflict)
+	ErrEmptyTag        = cerrors.New("empty tag", http.StatusBadRequest)
+	ErrVersionMismatch = cerrors.New("genre was modified since it was read", http.StatusPreconditionFailed)
+)
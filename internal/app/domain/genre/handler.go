@@ -2,10 +2,13 @@ package genre
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
-	"github.com/literalog/cerrors"
+	"github.com/literalog/library/internal/app/domain/duplicate"
+	"github.com/literalog/library/internal/app/gateways/api/httpio"
 	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
 
 	"github.com/gorilla/mux"
 )
@@ -13,19 +16,24 @@ import (
 type Handler interface {
 	Create(w http.ResponseWriter, r *http.Request)
 	Update(w http.ResponseWriter, r *http.Request)
+	Patch(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
 	GetById(w http.ResponseWriter, r *http.Request)
 	GetAll(w http.ResponseWriter, r *http.Request)
+	Duplicates(w http.ResponseWriter, r *http.Request)
+	Merge(w http.ResponseWriter, r *http.Request)
 	Routes() *mux.Router
 }
 
 type handler struct {
+	*duplicate.Handler
 	service Service
 	router  *mux.Router
 }
 
 func NewHandler(s Service) Handler {
 	h := &handler{
+		Handler: duplicate.NewHandler(s),
 		service: s,
 		router:  mux.NewRouter(),
 	}
@@ -37,7 +45,10 @@ func NewHandler(s Service) Handler {
 
 func (h *handler) setupRoutes() {
 	h.router.HandleFunc("/", h.Create).Methods(http.MethodPost)
-	h.router.HandleFunc("/", h.Update).Methods(http.MethodPut)
+	h.router.HandleFunc("/duplicates", h.Duplicates).Methods(http.MethodGet)
+	h.router.HandleFunc("/{id}/merge", h.Merge).Methods(http.MethodPost)
+	h.router.HandleFunc("/{id}", h.Update).Methods(http.MethodPut)
+	h.router.HandleFunc("/{id}", h.Patch).Methods(http.MethodPatch)
 	h.router.HandleFunc("/{id}", h.Delete).Methods(http.MethodDelete)
 	h.router.HandleFunc("/{id}", h.GetById).Methods(http.MethodGet)
 	h.router.HandleFunc("/", h.GetAll).Methods(http.MethodGet)
@@ -50,11 +61,14 @@ func (h handler) Routes() *mux.Router {
 func (h *handler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	req := new(models.GenreRequest)
-	json.NewDecoder(r.Body).Decode(&req)
+	if err := httpio.Decode(w, r, req); err != nil {
+		problem.Handle(err, w)
+		return
+	}
 
 	g := models.NewGenre(*req)
 	if err := h.service.Create(ctx, g); err != nil {
-		cerrors.Handle(err, w)
+		problem.Handle(err, w)
 		return
 	}
 
@@ -65,15 +79,65 @@ func (h *handler) Create(w http.ResponseWriter, r *http.Request) {
 
 func (h *handler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	id := mux.Vars(r)["id"]
 	req := new(models.GenreRequest)
-	json.NewDecoder(r.Body).Decode(&req)
+	if err := httpio.Decode(w, r, req); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	stored, err := h.service.GetById(ctx, id)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+	if !httpio.IfMatch(r, stored.Version) {
+		problem.Handle(ErrVersionMismatch, w)
+		return
+	}
 
 	g := models.NewGenre(*req)
+	g.Id = id
+	g.Meta = stored.Meta
+	if err := h.service.Update(ctx, g); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	httpio.SetETag(w, g.Version)
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(g)
+}
+
+// Patch applies a merge patch or JSON patch to the stored genre.
+func (h *handler) Patch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	g, err := h.service.GetById(ctx, id)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+	if !httpio.IfMatch(r, g.Version) {
+		problem.Handle(ErrVersionMismatch, w)
+		return
+	}
+
+	meta := g.Meta
+	if err := httpio.Patch(w, r, g); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	g.Id = id
+	g.Meta = meta
 	if err := h.service.Update(ctx, g); err != nil {
-		cerrors.Handle(err, w)
+		problem.Handle(err, w)
 		return
 	}
 
+	httpio.SetETag(w, g.Version)
 	w.Header().Add("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(g)
 }
@@ -82,8 +146,22 @@ func (h *handler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := mux.Vars(r)["id"]
 
-	if err := h.service.Delete(ctx, id); err != nil {
-		cerrors.Handle(err, w)
+	var version int64
+	if r.Header.Get("If-Match") != "" {
+		g, err := h.service.GetById(ctx, id)
+		if err != nil {
+			problem.Handle(err, w)
+			return
+		}
+		if !httpio.IfMatch(r, g.Version) {
+			problem.Handle(ErrVersionMismatch, w)
+			return
+		}
+		version = g.Version
+	}
+
+	if err := h.service.Delete(ctx, id, version); err != nil {
+		problem.Handle(err, w)
 		return
 	}
 
@@ -94,9 +172,15 @@ func (h *handler) Delete(w http.ResponseWriter, r *http.Request) {
 func (h *handler) GetAll(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	gg, err := h.service.GetAll(ctx)
+	q, err := NewQuery(r.URL.Query())
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	gg, err := h.service.List(ctx, q)
 	if err != nil {
-		cerrors.Handle(err, w)
+		problem.Handle(err, w)
 		return
 	}
 
@@ -110,10 +194,21 @@ func (h *handler) GetById(w http.ResponseWriter, r *http.Request) {
 
 	g, err := h.service.GetById(ctx, id)
 	if err != nil {
-		cerrors.Handle(err, w)
+		// Only a missing id may have been merged into another.
+		if errors.Is(err, ErrNotFound) {
+			if to, rerr := h.service.Resolve(ctx, id); rerr == nil {
+				httpio.Redirect(w, to)
+				return
+			}
+		}
+		problem.Handle(err, w)
+		return
+	}
+	if httpio.NotModified(w, r, g.Version) {
 		return
 	}
 
+	httpio.SetETag(w, g.Version)
 	w.Header().Add("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(g)
 }
@@ -8,9 +8,18 @@ import (
 
 type Repository interface {
 	Create(ctx context.Context, g *models.Genre) error
+	// Update replaces the stored genre if its version is still g.Version, then
+	// moves g to the next version. It returns ErrNotFound or
+	// ErrVersionMismatch otherwise.
 	Update(ctx context.Context, g *models.Genre) error
-	Delete(ctx context.Context, id string) error
+	// Delete removes the stored genre if its version is still version, or
+	// whatever its version when version is 0. It returns ErrNotFound or
+	// ErrVersionMismatch otherwise.
+	Delete(ctx context.Context, id string, version int64) error
 	GetById(ctx context.Context, id string) (*models.Genre, error)
 	GetByName(ctx context.Context, name string) (*models.Genre, error)
+	// GetByTags returns the stored genres among tags, in no particular order.
+	GetByTags(ctx context.Context, tags []string) ([]models.Genre, error)
 	GetAll(ctx context.Context) ([]models.Genre, error)
+	List(ctx context.Context, q Query) ([]models.Genre, int64, error)
 }
@@ -3,38 +3,116 @@ package genre
 import (
 	"context"
 
+	"github.com/literalog/library/internal/app/domain/alias"
+	"github.com/literalog/library/internal/app/domain/duplicate"
+	"github.com/literalog/library/internal/app/domain/reference"
+	"github.com/literalog/library/internal/app/domain/search"
 	"github.com/literalog/library/pkg/models"
 )
 
 type Service interface {
 	Create(ctx context.Context, g *models.Genre) error
 	Update(ctx context.Context, g *models.Genre) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, version int64) error
 	GetById(ctx context.Context, id string) (*models.Genre, error)
 	GetByName(ctx context.Context, name string) (*models.Genre, error)
+	GetByTags(ctx context.Context, tags []string) ([]models.Genre, error)
 	GetAll(ctx context.Context) ([]models.Genre, error)
+	List(ctx context.Context, q Query) (*models.List[models.Genre], error)
+	Duplicates(ctx context.Context, q duplicate.Query) (*models.List[duplicate.Cluster], error)
+	Merge(ctx context.Context, id string, sourceIds []string) (*duplicate.Report, error)
+	Resolve(ctx context.Context, id string) (string, error)
 }
 
 type service struct {
+	*duplicate.Merger[models.Genre]
 	repository Repository
+	references reference.Enforcer
+	index      search.Indexer
+	validator  Validator
 }
 
-func NewService(r Repository) Service {
-	return &service{
+func NewService(r Repository, refs reference.Enforcer, idx search.Indexer, aliases alias.Repository, replacers reference.Replacers) Service {
+	s := &service{
 		repository: r,
+		references: refs,
+		index:      idx,
 	}
+	s.Merger = &duplicate.Merger[models.Genre]{
+		Get: s.GetById,
+		All: r.GetAll,
+		Member: func(g *models.Genre) duplicate.Member {
+			return duplicate.Member{Id: g.Id, Name: g.Tag}
+		},
+		Key: func(g *models.Genre) string {
+			return g.Tag
+		},
+		Delete:    s.deleteMerged,
+		Replacers: replacers,
+		Aliases:   aliases,
+	}
+	return s
 }
 
 func (s *service) Create(ctx context.Context, g *models.Genre) error {
-	return s.repository.Create(ctx, g)
+	if err := s.validator.Validate(g); err != nil {
+		return err
+	}
+	if err := s.repository.Create(ctx, g); err != nil {
+		return err
+	}
+
+	search.Put(ctx, s.index, Document(g))
+	return nil
 }
 
+// Update stores g. Books and works refer to genres by tag, so when the tag
+// changes they are retagged the way a merge retags them.
 func (s *service) Update(ctx context.Context, g *models.Genre) error {
-	return s.repository.Update(ctx, g)
+	if err := s.validator.Validate(g); err != nil {
+		return err
+	}
+	stored, err := s.repository.GetById(ctx, g.Id)
+	if err != nil {
+		return err
+	}
+	if stored.Version != g.Version {
+		return ErrVersionMismatch
+	}
+
+	tag := stored.Tag
+	if err := s.repository.Update(ctx, g); err != nil {
+		return err
+	}
+	if g.Tag != tag {
+		if _, err := s.Replacers.Replace(ctx, tag, g.Tag); err != nil {
+			return err
+		}
+	}
+
+	search.Put(ctx, s.index, Document(g))
+	return nil
 }
 
-func (s *service) Delete(ctx context.Context, id string) error {
-	return s.repository.Delete(ctx, id)
+func (s *service) Delete(ctx context.Context, id string, version int64) error {
+	// Books refer to genres by tag rather than id.
+	g, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	// A stale request fails before the reference policies change anything.
+	if version != 0 && g.Version != version {
+		return ErrVersionMismatch
+	}
+	if err := s.references.Enforce(ctx, g.Tag); err != nil {
+		return err
+	}
+	if err := s.repository.Delete(ctx, id, version); err != nil {
+		return err
+	}
+
+	search.Remove(ctx, s.index, search.KindGenre, id)
+	return nil
 }
 
 func (s *service) GetById(ctx context.Context, id string) (*models.Genre, error) {
@@ -45,6 +123,32 @@ func (s *service) GetByName(ctx context.Context, name string) (*models.Genre, er
 	return s.repository.GetByName(ctx, name)
 }
 
+// GetByTags returns the genres among tags that exist, in a single lookup.
+func (s *service) GetByTags(ctx context.Context, tags []string) ([]models.Genre, error) {
+	if len(tags) == 0 {
+		return []models.Genre{}, nil
+	}
+	return s.repository.GetByTags(ctx, tags)
+}
+
 func (s *service) GetAll(ctx context.Context) ([]models.Genre, error) {
 	return s.repository.GetAll(ctx)
 }
+
+func (s *service) List(ctx context.Context, q Query) (*models.List[models.Genre], error) {
+	gg, total, err := s.repository.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewList(gg, total, q.ListOptions), nil
+}
+
+// deleteMerged removes a genre merged into another.
+func (s *service) deleteMerged(ctx context.Context, id string) error {
+	if err := s.repository.Delete(ctx, id, 0); err != nil {
+		return err
+	}
+
+	search.Remove(ctx, s.index, search.KindGenre, id)
+	return nil
+}
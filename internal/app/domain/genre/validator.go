@@ -0,0 +1,21 @@
+package genre
+
+import (
+	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
+)
+
+type Validator struct{}
+
+func NewValidator(r Repository) *Validator {
+	return &Validator{}
+}
+
+// Validate returns a *problem.ValidationError with every rule g fails.
+func (v *Validator) Validate(g *models.Genre) error {
+	errs := new(problem.ValidationError)
+	if g.Tag == "" {
+		errs.Add("tag", problem.CodeRequired, ErrEmptyTag)
+	}
+	return errs.Err()
+}
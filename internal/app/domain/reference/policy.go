@@ -0,0 +1,163 @@
+package reference
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/literalog/library/pkg/problem"
+)
+
+// Policy decides what happens to the books and works referring to an author,
+// series, genre or work when it is deleted.
+type Policy string
+
+const (
+	// Restrict refuses the deletion while anything refers to the entity.
+	Restrict Policy = "restrict"
+	// Cascade deletes the referrers along with the entity.
+	Cascade Policy = "cascade"
+	// Nullify clears the reference from the referrers.
+	Nullify Policy = "nullify"
+)
+
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(strings.ToLower(s)); p {
+	case Restrict, Cascade, Nullify:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid reference policy %q", s)
+	}
+}
+
+// Policies holds the policy of each relation a book has.
+type Policies struct {
+	Author Policy `yaml:"author" usage:"books and works referring to a deleted author (restrict, cascade or nullify)"`
+	Series Policy `yaml:"series" usage:"books and works referring to a deleted series (restrict, cascade or nullify)"`
+	Genre  Policy `yaml:"genre" usage:"books and works referring to a deleted genre (restrict, cascade or nullify)"`
+	Work   Policy `yaml:"work" usage:"editions of a deleted work (restrict, cascade or nullify)"`
+}
+
+// Referrers gives access to the books or works referring to an entity by
+// key, which is the entity id or, for genres, the tag.
+type Referrers interface {
+	Find(ctx context.Context, key string) ([]string, error)
+	Delete(ctx context.Context, ids []string) error
+	Clear(ctx context.Context, key string, ids []string) error
+	Replacer
+}
+
+// Replacer rewrites references from one entity to another, as when merging
+// duplicates.
+type Replacer interface {
+	// Replace makes whatever referred to key refer to with, returning the
+	// ids of what changed.
+	Replace(ctx context.Context, key, with string) ([]string, error)
+}
+
+// Replacers holds a replacer for each kind of referrer, such as books and
+// works.
+type Replacers map[string]Replacer
+
+// Replaced lists, by kind of referrer, the ids of what was rewritten.
+type Replaced map[string][]string
+
+func (rr Replacers) Replace(ctx context.Context, key, with string) (Replaced, error) {
+	replaced := make(Replaced, len(rr))
+	for kind, r := range rr {
+		ids, err := r.Replace(ctx, key, with)
+		if err != nil {
+			return nil, err
+		}
+		replaced[kind] = ids
+	}
+	return replaced, nil
+}
+
+// Add appends the ids in other.
+func (r Replaced) Add(other Replaced) {
+	for kind, ids := range other {
+		if r[kind] == nil {
+			r[kind] = []string{}
+		}
+		r[kind] = append(r[kind], ids...)
+	}
+}
+
+// ReferrersByKind holds the referrers of each kind, such as books and works.
+type ReferrersByKind map[string]Referrers
+
+type Enforcer interface {
+	// Enforce applies the policy to everything referring to key. It must be
+	// called before the referred entity is deleted.
+	Enforce(ctx context.Context, key string) error
+}
+
+type enforcer struct {
+	policy    Policy
+	referrers ReferrersByKind
+}
+
+func NewEnforcer(p Policy, rr ReferrersByKind) Enforcer {
+	return &enforcer{
+		policy:    p,
+		referrers: rr,
+	}
+}
+
+func (e *enforcer) Enforce(ctx context.Context, key string) error {
+	found := make(map[string][]string, len(e.referrers))
+	for kind, r := range e.referrers {
+		ids, err := r.Find(ctx, key)
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			found[kind] = ids
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	if e.policy != Cascade && e.policy != Nullify {
+		return ErrReferenced(found)
+	}
+	for _, kind := range kinds(found) {
+		var err error
+		if e.policy == Cascade {
+			err = e.referrers[kind].Delete(ctx, found[kind])
+		} else {
+			err = e.referrers[kind].Clear(ctx, key, found[kind])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ErrReferenced is the conflict returned by Restrict, listing the blocking
+// referrers of each kind in a member named after it, such as book_ids and
+// work_ids.
+func ErrReferenced(found map[string][]string) error {
+	kk := kinds(found)
+	p := problem.New(http.StatusConflict, "referenced by "+strings.Join(kk, " and "))
+	for _, kind := range kk {
+		p = p.With(strings.TrimSuffix(kind, "s")+"_ids", found[kind])
+	}
+	return p
+}
+
+// kinds returns the kinds of referrers in found, sorted so books are handled
+// before works.
+func kinds(found map[string][]string) []string {
+	kk := make([]string, 0, len(found))
+	for kind := range found {
+		kk = append(kk, kind)
+	}
+	slices.Sort(kk)
+	return kk
+}
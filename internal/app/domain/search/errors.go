@@ -0,0 +1,12 @@
+package search
+
+import (
+	"net/http"
+
+	"github.com/literalog/cerrors"
+)
+
+var (
+	ErrEmptyQuery  = cerrors.New("empty search query", http.StatusBadRequest)
+	ErrInvalidKind = cerrors.New("invalid kind, expected book, author, series or genre", http.StatusBadRequest)
+)
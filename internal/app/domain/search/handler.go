@@ -0,0 +1,62 @@
+package search
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/literalog/library/pkg/problem"
+
+	"github.com/gorilla/mux"
+)
+
+type Handler interface {
+	Search(w http.ResponseWriter, r *http.Request)
+	Routes() *mux.Router
+}
+
+type handler struct {
+	service Service
+	router  *mux.Router
+}
+
+func NewHandler(s Service) Handler {
+	h := &handler{
+		service: s,
+		router:  mux.NewRouter(),
+	}
+
+	h.setupRoutes()
+
+	return h
+}
+
+func (h *handler) setupRoutes() {
+	h.router.HandleFunc("/", h.Search).Methods(http.MethodGet)
+}
+
+func (h *handler) Routes() *mux.Router {
+	return h.router
+}
+
+func (h *handler) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	q, err := NewQuery(r.URL.Query())
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	hh, err := h.service.Search(ctx, q)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	// Highlights are already escaped; keep their <mark> tags readable.
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	w.Header().Add("Content-Type", "application/json")
+	enc.Encode(hh)
+}
@@ -0,0 +1,112 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// Kind is the kind of entity a document stands for.
+type Kind string
+
+const (
+	KindBook   Kind = "book"
+	KindAuthor Kind = "author"
+	KindSeries Kind = "series"
+	KindGenre  Kind = "genre"
+)
+
+// Field is searchable text. Matches in fields with a higher weight rank
+// higher.
+type Field struct {
+	Name   string
+	Text   string
+	Weight float64
+}
+
+// Ref points from a document to another one whose text also describes it,
+// such as a book to its authors.
+type Ref struct {
+	Kind Kind
+	Id   string
+}
+
+// Document is the searchable form of an entity.
+type Document struct {
+	Kind   Kind
+	Id     string
+	Title  string
+	Fields []Field
+	Refs   []Ref
+}
+
+// Hit is a document matching a search. Highlights hold, per field, a snippet
+// of HTML escaped text with the matches wrapped in <mark> elements. Matches
+// in a referenced document are highlighted under its kind.
+type Hit struct {
+	Kind       Kind              `json:"kind"`
+	Id         string            `json:"id"`
+	Title      string            `json:"title"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// Indexer keeps a view of the documents, such as an Index, up to date.
+type Indexer interface {
+	// Put adds the document or replaces the one with the same kind and id.
+	Put(ctx context.Context, doc Document) error
+	Remove(ctx context.Context, kind Kind, id string) error
+}
+
+// Index finds documents by the words in their fields and in the documents
+// they refer to. Words match regardless of case and diacritics, and with a
+// few typos.
+type Index interface {
+	Indexer
+	Search(ctx context.Context, q Query) ([]Hit, int64, error)
+}
+
+// Indexers keeps several indexers up to date as one.
+type Indexers []Indexer
+
+func (ii Indexers) Put(ctx context.Context, doc Document) error {
+	var errs []error
+	for _, idx := range ii {
+		errs = append(errs, idx.Put(ctx, doc))
+	}
+	return errors.Join(errs...)
+}
+
+func (ii Indexers) Remove(ctx context.Context, kind Kind, id string) error {
+	var errs []error
+	for _, idx := range ii {
+		errs = append(errs, idx.Remove(ctx, kind, id))
+	}
+	return errors.Join(errs...)
+}
+
+// Put indexes doc, logging rather than returning a failure: the stored
+// entity has already changed and stays the source of truth.
+func Put(ctx context.Context, idx Indexer, doc Document) {
+	if err := idx.Put(ctx, doc); err != nil {
+		slog.Error("error indexing document", "kind", doc.Kind, "id", doc.Id, "err", err)
+	}
+}
+
+// Remove drops a document from idx, logging any failure.
+func Remove(ctx context.Context, idx Indexer, kind Kind, id string) {
+	if err := idx.Remove(ctx, kind, id); err != nil {
+		slog.Error("error removing document", "kind", kind, "id", id, "err", err)
+	}
+}
+
+// Rebuild indexes every given document, as when the server starts with an
+// empty index over existing data.
+func Rebuild[T any](ctx context.Context, idx Indexer, items []T, document func(*T) Document) error {
+	for i := range items {
+		if err := idx.Put(ctx, document(&items[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package search
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/literalog/cerrors"
+	"github.com/literalog/library/pkg/models"
+)
+
+// Query searches for the words of Text in the documents of the given kinds,
+// or of every kind. Results are ranked, so they cannot be sorted.
+type Query struct {
+	models.ListOptions
+	Text  string
+	Kinds []Kind
+}
+
+func NewQuery(v url.Values) (Query, error) {
+	opts, err := models.ParseListOptions(v)
+	if err != nil {
+		return Query{}, cerrors.New(err.Error(), http.StatusBadRequest)
+	}
+
+	q := Query{
+		ListOptions: opts,
+		Text:        strings.TrimSpace(v.Get("q")),
+	}
+	if q.Text == "" {
+		return Query{}, ErrEmptyQuery
+	}
+
+	if s := v.Get("kind"); s != "" {
+		for _, k := range strings.Split(s, ",") {
+			switch kind := Kind(strings.TrimSpace(k)); kind {
+			case KindBook, KindAuthor, KindSeries, KindGenre:
+				q.Kinds = append(q.Kinds, kind)
+			default:
+				return Query{}, ErrInvalidKind
+			}
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,29 @@
+package search
+
+import (
+	"context"
+
+	"github.com/literalog/library/pkg/models"
+)
+
+type Service interface {
+	Search(ctx context.Context, q Query) (*models.List[Hit], error)
+}
+
+type service struct {
+	index Index
+}
+
+func NewService(idx Index) Service {
+	return &service{
+		index: idx,
+	}
+}
+
+func (s *service) Search(ctx context.Context, q Query) (*models.List[Hit], error) {
+	hh, total, err := s.index.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewList(hh, total, q.ListOptions), nil
+}
@@ -0,0 +1,18 @@
+package series
+
+import (
+	"github.com/literalog/library/internal/app/domain/search"
+	"github.com/literalog/library/pkg/models"
+)
+
+// Document is the searchable form of a series.
+func Document(s *models.Series) search.Document {
+	return search.Document{
+		Kind:  search.KindSeries,
+		Id:    s.Id,
+		Title: s.Name,
+		Fields: []search.Field{
+			{Name: "name", Text: s.Name, Weight: 3},
+		},
+	}
+}
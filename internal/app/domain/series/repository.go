@@ -8,8 +8,15 @@ import (
 
 type Repository interface {
 	Create(ctx context.Context, s *models.Series) error
+	// Update replaces the stored series if its version is still s.Version, then
+	// moves s to the next version. It returns ErrNotFound or
+	// ErrVersionMismatch otherwise.
 	Update(ctx context.Context, s *models.Series) error
-	Delete(ctx context.Context, id string) error
+	// Delete removes the stored series if its version is still version, or
+	// whatever its version when version is 0. It returns ErrNotFound or
+	// ErrVersionMismatch otherwise.
+	Delete(ctx context.Context, id string, version int64) error
 	GetById(ctx context.Context, id string) (*models.Series, error)
 	GetAll(ctx context.Context) ([]models.Series, error)
+	List(ctx context.Context, q Query) ([]models.Series, int64, error)
 }
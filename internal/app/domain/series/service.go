@@ -3,40 +3,102 @@ package series
 import (
 	"context"
 
+	"github.com/literalog/library/internal/app/domain/alias"
+	"github.com/literalog/library/internal/app/domain/duplicate"
+	"github.com/literalog/library/internal/app/domain/reference"
+	"github.com/literalog/library/internal/app/domain/search"
 	"github.com/literalog/library/pkg/models"
 )
 
 type Service interface {
 	Create(ctx context.Context, s *models.Series) error
 	Update(ctx context.Context, s *models.Series) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, version int64) error
 	GetById(ctx context.Context, id string) (*models.Series, error)
 	GetAll(ctx context.Context) ([]models.Series, error)
+	List(ctx context.Context, q Query) (*models.List[models.Series], error)
+	Duplicates(ctx context.Context, q duplicate.Query) (*models.List[duplicate.Cluster], error)
+	Merge(ctx context.Context, id string, sourceIds []string) (*duplicate.Report, error)
+	Resolve(ctx context.Context, id string) (string, error)
 }
 
 type service struct {
+	*duplicate.Merger[models.Series]
 	repository Repository
+	references reference.Enforcer
+	index      search.Indexer
+	validator  Validator
 }
 
-func NewService(repo Repository) Service {
-	return &service{
+func NewService(repo Repository, refs reference.Enforcer, idx search.Indexer, aliases alias.Repository, replacers reference.Replacers) Service {
+	s := &service{
 		repository: repo,
+		references: refs,
+		index:      idx,
 	}
+	s.Merger = &duplicate.Merger[models.Series]{
+		Get: s.GetById,
+		All: repo.GetAll,
+		Member: func(s *models.Series) duplicate.Member {
+			return duplicate.Member{Id: s.Id, Name: s.Name}
+		},
+		Key: func(s *models.Series) string {
+			return s.Id
+		},
+		Delete:    s.deleteMerged,
+		Replacers: replacers,
+		Aliases:   aliases,
+	}
+	return s
 }
 
 func (s *service) Create(ctx context.Context, series *models.Series) error {
-	return s.repository.Create(ctx, series)
+	if err := s.validator.Validate(series); err != nil {
+		return err
+	}
+	if err := s.repository.Create(ctx, series); err != nil {
+		return err
+	}
+
+	search.Put(ctx, s.index, Document(series))
+	return nil
 }
 
 func (s *service) Update(ctx context.Context, series *models.Series) error {
-	return s.repository.Update(ctx, series)
+	if err := s.validator.Validate(series); err != nil {
+		return err
+	}
+	if err := s.repository.Update(ctx, series); err != nil {
+		return err
+	}
+
+	search.Put(ctx, s.index, Document(series))
+	return nil
 }
 
-func (s *service) Delete(ctx context.Context, id string) error {
+func (s *service) Delete(ctx context.Context, id string, version int64) error {
 	if id == "" {
 		return ErrEmptyId
 	}
-	return s.repository.Delete(ctx, id)
+	// A stale request fails before the reference policies change anything.
+	if version != 0 {
+		stored, err := s.repository.GetById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if stored.Version != version {
+			return ErrVersionMismatch
+		}
+	}
+	if err := s.references.Enforce(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repository.Delete(ctx, id, version); err != nil {
+		return err
+	}
+
+	search.Remove(ctx, s.index, search.KindSeries, id)
+	return nil
 }
 
 func (s *service) GetById(ctx context.Context, id string) (*models.Series, error) {
@@ -49,3 +111,21 @@ func (s *service) GetById(ctx context.Context, id string) (*models.Series, error
 func (s *service) GetAll(ctx context.Context) ([]models.Series, error) {
 	return s.repository.GetAll(ctx)
 }
+
+func (s *service) List(ctx context.Context, q Query) (*models.List[models.Series], error) {
+	ss, total, err := s.repository.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewList(ss, total, q.ListOptions), nil
+}
+
+// deleteMerged removes a series merged into another.
+func (s *service) deleteMerged(ctx context.Context, id string) error {
+	if err := s.repository.Delete(ctx, id, 0); err != nil {
+		return err
+	}
+
+	search.Remove(ctx, s.index, search.KindSeries, id)
+	return nil
+}
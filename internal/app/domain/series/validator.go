@@ -0,0 +1,21 @@
+package series
+
+import (
+	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
+)
+
+type Validator struct{}
+
+func NewValidator(r Repository) *Validator {
+	return &Validator{}
+}
+
+// Validate returns a *problem.ValidationError with every rule s fails.
+func (v *Validator) Validate(s *models.Series) error {
+	errs := new(problem.ValidationError)
+	if s.Name == "" {
+		errs.Add("name", problem.CodeRequired, ErrEmptyName)
+	}
+	return errs.Err()
+}
@@ -0,0 +1,14 @@
+package suggest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/literalog/cerrors"
+)
+
+var (
+	ErrEmptyPrefix  = cerrors.New("empty prefix", http.StatusBadRequest)
+	ErrInvalidType  = cerrors.New("invalid type, expected author, series or genre", http.StatusBadRequest)
+	ErrInvalidLimit = cerrors.New(fmt.Sprintf("limit must be between 1 and %d", MaxLimit), http.StatusBadRequest)
+)
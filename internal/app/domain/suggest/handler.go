@@ -0,0 +1,58 @@
+package suggest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/literalog/library/pkg/problem"
+
+	"github.com/gorilla/mux"
+)
+
+type Handler interface {
+	Suggest(w http.ResponseWriter, r *http.Request)
+	Routes() *mux.Router
+}
+
+type handler struct {
+	service Service
+	router  *mux.Router
+}
+
+func NewHandler(s Service) Handler {
+	h := &handler{
+		service: s,
+		router:  mux.NewRouter(),
+	}
+
+	h.setupRoutes()
+
+	return h
+}
+
+func (h *handler) setupRoutes() {
+	h.router.HandleFunc("/", h.Suggest).Methods(http.MethodGet)
+}
+
+func (h *handler) Routes() *mux.Router {
+	return h.router
+}
+
+func (h *handler) Suggest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	q, err := NewQuery(r.URL.Query())
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	ss, err := h.service.Suggest(ctx, q)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ss)
+}
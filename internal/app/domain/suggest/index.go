@@ -0,0 +1,25 @@
+package suggest
+
+import (
+	"context"
+
+	"github.com/literalog/library/internal/app/domain/search"
+)
+
+// Suggestion is an existing entity whose name starts like the typed prefix.
+// Books counts the books referring to it.
+type Suggestion struct {
+	Type  search.Kind `json:"type"`
+	Id    string      `json:"id"`
+	Name  string      `json:"name"`
+	Books int         `json:"books"`
+	Score float64     `json:"score"`
+}
+
+// Index finds authors, series and genres by the beginning of the words of
+// their names, regardless of case and diacritics and with a few typos. It is
+// kept up to date as a search.Indexer.
+type Index interface {
+	search.Indexer
+	Suggest(ctx context.Context, q Query) ([]Suggestion, error)
+}
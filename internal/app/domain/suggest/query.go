@@ -0,0 +1,48 @@
+package suggest
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/literalog/library/internal/app/domain/search"
+)
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 50
+)
+
+// Query asks for the Limit best entities of Type, or of every type, whose
+// names start like Prefix.
+type Query struct {
+	Type   search.Kind
+	Prefix string
+	Limit  int
+}
+
+func NewQuery(v url.Values) (Query, error) {
+	q := Query{
+		Prefix: strings.TrimSpace(v.Get("prefix")),
+		Limit:  DefaultLimit,
+	}
+	if q.Prefix == "" {
+		return Query{}, ErrEmptyPrefix
+	}
+
+	switch kind := search.Kind(v.Get("type")); kind {
+	case "", search.KindAuthor, search.KindSeries, search.KindGenre:
+		q.Type = kind
+	default:
+		return Query{}, ErrInvalidType
+	}
+
+	if s := v.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return Query{}, ErrInvalidLimit
+		}
+		q.Limit = limit
+	}
+	return q, nil
+}
@@ -0,0 +1,29 @@
+package suggest
+
+import (
+	"context"
+
+	"github.com/literalog/library/pkg/models"
+)
+
+type Service interface {
+	Suggest(ctx context.Context, q Query) (*models.List[Suggestion], error)
+}
+
+type service struct {
+	index Index
+}
+
+func NewService(idx Index) Service {
+	return &service{
+		index: idx,
+	}
+}
+
+func (s *service) Suggest(ctx context.Context, q Query) (*models.List[Suggestion], error) {
+	ss, err := s.index.Suggest(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewList(ss, int64(len(ss)), models.ListOptions{Limit: q.Limit}), nil
+}
@@ -0,0 +1,18 @@
+package work
+
+import (
+	"net/http"
+
+	"github.com/literalog/cerrors"
+)
+
+var (
+	ErrEmptyId         = cerrors.New("empty id", http.StatusBadRequest)
+	ErrEmptyTitle      = cerrors.New("empty title", http.StatusBadRequest)
+	ErrAuthorNotFound  = cerrors.New("author not found", http.StatusUnprocessableEntity)
+	ErrSeriesNotFound  = cerrors.New("series not found", http.StatusUnprocessableEntity)
+	ErrGenreNotFound   = cerrors.New("genre not found", http.StatusUnprocessableEntity)
+	ErrNotFound        = cerrors.New("work not found", http.StatusNotFound)
+	ErrConflict        = cerrors.New("a work with this id already exists", http.StatusConflict)
+	ErrVersionMismatch = cerrors.New("work was modified since it was read", http.StatusPreconditionFailed)
+)
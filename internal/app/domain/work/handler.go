@@ -0,0 +1,199 @@
+package work
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/literalog/library/internal/app/gateways/api/httpio"
+	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
+
+	"github.com/gorilla/mux"
+)
+
+type Handler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Patch(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	GetById(w http.ResponseWriter, r *http.Request)
+	GetAll(w http.ResponseWriter, r *http.Request)
+	Routes() *mux.Router
+}
+
+type handler struct {
+	service Service
+	router  *mux.Router
+}
+
+func NewHandler(s Service) Handler {
+	h := &handler{
+		service: s,
+		router:  mux.NewRouter(),
+	}
+
+	h.setupRoutes()
+
+	return h
+}
+
+func (h *handler) setupRoutes() {
+	h.router.HandleFunc("/", h.Create).Methods(http.MethodPost)
+	h.router.HandleFunc("/{id}", h.Update).Methods(http.MethodPut)
+	h.router.HandleFunc("/{id}", h.Patch).Methods(http.MethodPatch)
+	h.router.HandleFunc("/{id}", h.Delete).Methods(http.MethodDelete)
+	h.router.HandleFunc("/{id}", h.GetById).Methods(http.MethodGet)
+	h.router.HandleFunc("/", h.GetAll).Methods(http.MethodGet)
+}
+
+func (h *handler) Routes() *mux.Router {
+	return h.router
+}
+
+func (h *handler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := new(models.WorkRequest)
+	if err := httpio.Decode(w, r, req); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	wk := models.NewWork(*req)
+	if err := h.service.Create(ctx, wk); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(wk)
+}
+
+func (h *handler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+	req := new(models.WorkRequest)
+	if err := httpio.Decode(w, r, req); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	stored, err := h.service.GetById(ctx, id)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+	if !httpio.IfMatch(r, stored.Version) {
+		problem.Handle(ErrVersionMismatch, w)
+		return
+	}
+
+	wk := models.NewWork(*req)
+	wk.Id = id
+	wk.Meta = stored.Meta
+	if err := h.service.Update(ctx, wk); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	httpio.SetETag(w, wk.Version)
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wk)
+}
+
+// Patch applies a merge patch or JSON patch to the stored work.
+func (h *handler) Patch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	wk, err := h.service.GetById(ctx, id)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+	if !httpio.IfMatch(r, wk.Version) {
+		problem.Handle(ErrVersionMismatch, w)
+		return
+	}
+
+	meta := wk.Meta
+	if err := httpio.Patch(w, r, wk); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	wk.Id = id
+	wk.Meta = meta
+	if err := h.service.Update(ctx, wk); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	httpio.SetETag(w, wk.Version)
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wk)
+}
+
+func (h *handler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	var version int64
+	if r.Header.Get("If-Match") != "" {
+		wk, err := h.service.GetById(ctx, id)
+		if err != nil {
+			problem.Handle(err, w)
+			return
+		}
+		if !httpio.IfMatch(r, wk.Version) {
+			problem.Handle(ErrVersionMismatch, w)
+			return
+		}
+		version = wk.Version
+	}
+
+	if err := h.service.Delete(ctx, id, version); err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	q, err := NewQuery(r.URL.Query())
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	ww, err := h.service.List(ctx, q)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ww)
+}
+
+func (h *handler) GetById(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	wk, err := h.service.GetById(ctx, id)
+	if err != nil {
+		problem.Handle(err, w)
+		return
+	}
+	if httpio.NotModified(w, r, wk.Version) {
+		return
+	}
+
+	httpio.SetETag(w, wk.Version)
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wk)
+}
@@ -0,0 +1,30 @@
+package work
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/literalog/cerrors"
+	"github.com/literalog/library/pkg/models"
+)
+
+var sortable = []string{"title", "series_no"}
+
+type Query struct {
+	models.ListOptions
+	Title    string
+	SeriesId string
+}
+
+func NewQuery(v url.Values) (Query, error) {
+	opts, err := models.ParseListOptions(v, sortable...)
+	if err != nil {
+		return Query{}, cerrors.New(err.Error(), http.StatusBadRequest)
+	}
+
+	return Query{
+		ListOptions: opts,
+		Title:       v.Get("title"),
+		SeriesId:    v.Get("series_id"),
+	}, nil
+}
@@ -0,0 +1,134 @@
+package work
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/literalog/library/internal/app/domain/reference"
+	"github.com/literalog/library/pkg/models"
+)
+
+type referrers struct {
+	repository Repository
+	refers     func(w *models.Work, key string) bool
+	clear      func(w *models.Work, key string)
+	replace    func(w *models.Work, key, with string)
+}
+
+func NewAuthorReferrers(r Repository) reference.Referrers {
+	return &referrers{
+		repository: r,
+		refers: func(w *models.Work, id string) bool {
+			return slices.ContainsFunc(w.Contributors, func(c models.Contributor) bool {
+				return c.AuthorId == id
+			})
+		},
+		clear: func(w *models.Work, id string) {
+			w.RemoveContributor(id)
+		},
+		replace: func(w *models.Work, id, with string) {
+			w.ReplaceContributor(id, with)
+		},
+	}
+}
+
+func NewSeriesReferrers(r Repository) reference.Referrers {
+	return &referrers{
+		repository: r,
+		refers: func(w *models.Work, id string) bool {
+			return w.SeriesId == id
+		},
+		clear: func(w *models.Work, _ string) {
+			w.SeriesId = ""
+			w.SeriesNo = 0
+		},
+		replace: func(w *models.Work, _, with string) {
+			w.SeriesId = with
+		},
+	}
+}
+
+func NewGenreReferrers(r Repository) reference.Referrers {
+	return &referrers{
+		repository: r,
+		refers: func(w *models.Work, tag string) bool {
+			return slices.Contains(w.Genre, tag)
+		},
+		clear: func(w *models.Work, tag string) {
+			w.Genre = slices.DeleteFunc(w.Genre, func(g string) bool {
+				return g == tag
+			})
+		},
+		replace: func(w *models.Work, tag, with string) {
+			w.ReplaceGenre(tag, with)
+		},
+	}
+}
+
+// find scans every work: works are few next to books and are not looked up
+// by author or genre.
+func (r *referrers) find(ctx context.Context, key string) ([]models.Work, error) {
+	ww, err := r.repository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(ww, func(w models.Work) bool {
+		return !r.refers(&w, key)
+	}), nil
+}
+
+func (r *referrers) Find(ctx context.Context, key string) ([]string, error) {
+	ww, err := r.find(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(ww))
+	for _, w := range ww {
+		ids = append(ids, w.Id)
+	}
+	return ids, nil
+}
+
+func (r *referrers) Delete(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		// A work deleted meanwhile no longer refers to anything.
+		if err := r.repository.Delete(ctx, id, 0); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *referrers) Clear(ctx context.Context, key string, ids []string) error {
+	for _, id := range ids {
+		w, err := r.repository.GetById(ctx, id)
+		if err != nil {
+			return err
+		}
+		r.clear(w, key)
+		if err := r.repository.Update(ctx, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *referrers) Replace(ctx context.Context, key, with string) ([]string, error) {
+	ww, err := r.find(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for i := range ww {
+		w := &ww[i]
+		r.replace(w, key, with)
+		if err := r.repository.Update(ctx, w); err != nil {
+			return nil, err
+		}
+		ids = append(ids, w.Id)
+	}
+	return ids, nil
+}
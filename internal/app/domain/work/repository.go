@@ -0,0 +1,22 @@
+package work
+
+import (
+	"context"
+
+	"github.com/literalog/library/pkg/models"
+)
+
+type Repository interface {
+	Create(ctx context.Context, w *models.Work) error
+	// Update replaces the stored work if its version is still w.Version, then
+	// moves w to the next version. It returns ErrNotFound or
+	// ErrVersionMismatch otherwise.
+	Update(ctx context.Context, w *models.Work) error
+	// Delete removes the stored work if its version is still version, or
+	// whatever its version when version is 0. It returns ErrNotFound or
+	// ErrVersionMismatch otherwise.
+	Delete(ctx context.Context, id string, version int64) error
+	GetById(ctx context.Context, id string) (*models.Work, error)
+	GetAll(ctx context.Context) ([]models.Work, error)
+	List(ctx context.Context, q Query) ([]models.Work, int64, error)
+}
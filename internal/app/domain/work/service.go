@@ -0,0 +1,167 @@
+package work
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/literalog/library/internal/app/domain/author"
+	"github.com/literalog/library/internal/app/domain/genre"
+	"github.com/literalog/library/internal/app/domain/reference"
+	"github.com/literalog/library/internal/app/domain/series"
+	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
+)
+
+type Service interface {
+	Create(ctx context.Context, w *models.Work) error
+	Update(ctx context.Context, w *models.Work) error
+	Delete(ctx context.Context, id string, version int64) error
+	GetById(ctx context.Context, id string) (*models.Work, error)
+	GetAll(ctx context.Context) ([]models.Work, error)
+	List(ctx context.Context, q Query) (*models.List[models.Work], error)
+}
+
+// Editions keeps the books that are editions of a work in step with it.
+type Editions interface {
+	// Sync copies the fields editions share with w to every edition of w.
+	Sync(ctx context.Context, w *models.Work) error
+}
+
+type service struct {
+	repository    Repository
+	references    reference.Enforcer
+	editions      Editions
+	authorService author.Service
+	seriesService series.Service
+	genreService  genre.Service
+	validator     Validator
+}
+
+func NewService(repo Repository, refs reference.Enforcer, editions Editions, as author.Service, ss series.Service, gs genre.Service) Service {
+	return &service{
+		repository:    repo,
+		references:    refs,
+		editions:      editions,
+		authorService: as,
+		seriesService: ss,
+		genreService:  gs,
+	}
+}
+
+func (s *service) Create(ctx context.Context, w *models.Work) error {
+	if err := s.validate(ctx, w); err != nil {
+		return err
+	}
+	return s.repository.Create(ctx, w)
+}
+
+// Update stores w, then copies the fields its editions share with it to
+// each of them.
+func (s *service) Update(ctx context.Context, w *models.Work) error {
+	if err := s.validate(ctx, w); err != nil {
+		return err
+	}
+	if err := s.repository.Update(ctx, w); err != nil {
+		return err
+	}
+	return s.editions.Sync(ctx, w)
+}
+
+func (s *service) validate(ctx context.Context, w *models.Work) error {
+	if err := s.validator.Validate(w); err != nil {
+		return err
+	}
+	return s.checkReferences(ctx, w)
+}
+
+// checkReferences returns a *problem.ValidationError with every author,
+// series and genre w refers to that does not exist.
+func (s *service) checkReferences(ctx context.Context, w *models.Work) error {
+	errs := new(problem.ValidationError)
+
+	ids := make([]string, 0, len(w.Contributors))
+	for _, c := range w.Contributors {
+		ids = append(ids, c.AuthorId)
+	}
+	aa, err := s.authorService.GetByIds(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("error getting authors: %w", err)
+	}
+	authors := make(map[string]bool, len(aa))
+	for _, a := range aa {
+		authors[a.Id] = true
+	}
+	for i, c := range w.Contributors {
+		if !authors[c.AuthorId] {
+			errs.Add(fmt.Sprintf("contributors[%d].author_id", i), problem.CodeNotFound, ErrAuthorNotFound)
+		}
+	}
+
+	if w.SeriesId != "" {
+		_, err := s.seriesService.GetById(ctx, w.SeriesId)
+		switch {
+		case errors.Is(err, series.ErrNotFound):
+			errs.Add("series_id", problem.CodeNotFound, ErrSeriesNotFound)
+		case err != nil:
+			return fmt.Errorf("error getting series %s: %w", w.SeriesId, err)
+		}
+	}
+
+	if len(w.Genre) > 0 {
+		gg, err := s.genreService.GetByTags(ctx, w.Genre)
+		if err != nil {
+			return fmt.Errorf("error getting genres: %w", err)
+		}
+		genres := make(map[string]bool, len(gg))
+		for _, g := range gg {
+			genres[g.Tag] = true
+		}
+		for i, tag := range w.Genre {
+			if !genres[tag] {
+				errs.Add(fmt.Sprintf("genre[%d]", i), problem.CodeNotFound, ErrGenreNotFound)
+			}
+		}
+	}
+
+	return errs.Err()
+}
+
+func (s *service) Delete(ctx context.Context, id string, version int64) error {
+	if id == "" {
+		return ErrEmptyId
+	}
+	// A stale request fails before the reference policies change anything.
+	if version != 0 {
+		stored, err := s.repository.GetById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if stored.Version != version {
+			return ErrVersionMismatch
+		}
+	}
+	if err := s.references.Enforce(ctx, id); err != nil {
+		return err
+	}
+	return s.repository.Delete(ctx, id, version)
+}
+
+func (s *service) GetById(ctx context.Context, id string) (*models.Work, error) {
+	if id == "" {
+		return nil, ErrEmptyId
+	}
+	return s.repository.GetById(ctx, id)
+}
+
+func (s *service) GetAll(ctx context.Context) ([]models.Work, error) {
+	return s.repository.GetAll(ctx)
+}
+
+func (s *service) List(ctx context.Context, q Query) (*models.List[models.Work], error) {
+	ww, total, err := s.repository.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewList(ww, total, q.ListOptions), nil
+}
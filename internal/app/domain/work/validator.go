@@ -0,0 +1,21 @@
+package work
+
+import (
+	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
+)
+
+type Validator struct{}
+
+func NewValidator(r Repository) *Validator {
+	return &Validator{}
+}
+
+// Validate returns a *problem.ValidationError with every rule w fails.
+func (v *Validator) Validate(w *models.Work) error {
+	errs := new(problem.ValidationError)
+	if w.Title == "" {
+		errs.Add("title", problem.CodeRequired, ErrEmptyTitle)
+	}
+	return errs.Err()
+}
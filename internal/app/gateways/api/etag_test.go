@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/literalog/library/internal/app/gateways/api/httpio"
+	"github.com/literalog/library/pkg/models"
+)
+
+// serve serves a request without a body carrying the given header.
+func serve(s *Server, method, path, header, value string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, nil)
+	r.Header.Set(header, value)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+	return w
+}
+
+func TestIsbnETag(t *testing.T) {
+	s := newTestServer(t)
+
+	var a models.Author
+	call(t, s, http.MethodPost, "/authors", models.AuthorRequest{Name: "Ursula K. Le Guin"}, &a)
+	var b models.Book
+	call(t, s, http.MethodPost, "/books", models.BookRequest{
+		Title:    "A Wizard of Earthsea",
+		AuthorId: a.Id,
+		Isbn:     []string{"9780547928227"},
+	}, &b)
+
+	w := serve(s, http.MethodGet, "/books/isbn/054792822X", "If-None-Match", "")
+	if got := w.Header().Get("ETag"); w.Code != http.StatusOK || got != httpio.ETag(b.Version) {
+		t.Fatalf("get by isbn: got %d with ETag %q, want 200 with %q", w.Code, got, httpio.ETag(b.Version))
+	}
+	if w := serve(s, http.MethodGet, "/books/isbn/054792822X", "If-None-Match", httpio.ETag(b.Version)); w.Code != http.StatusNotModified {
+		t.Errorf("get by isbn if none match: got %d, want 304", w.Code)
+	}
+}
+
+func TestDeleteIfMatch(t *testing.T) {
+	s := newTestServer(t)
+
+	var a models.Author
+	call(t, s, http.MethodPost, "/authors", models.AuthorRequest{Name: "Ursula K. Le Guin"}, &a)
+	call(t, s, http.MethodPut, "/authors/"+a.Id, models.AuthorRequest{Name: "Ursula Le Guin"}, nil)
+
+	if w := serve(s, http.MethodDelete, "/authors/"+a.Id, "If-Match", httpio.ETag(1)); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("delete stale: got %d, want 412", w.Code)
+	}
+	if w := serve(s, http.MethodDelete, "/authors/"+a.Id, "If-Match", httpio.ETag(2)); w.Code != http.StatusNoContent {
+		t.Errorf("delete current: got %d: %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const readinessTimeout = 2 * time.Second
+
+type check struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type readiness struct {
+	Status string           `json:"status"`
+	Checks map[string]check `json:"checks"`
+}
+
+// healthz reports that the process is alive. It does not look at any
+// dependency, so a storage outage does not get the process restarted.
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(check{Status: "ok"})
+}
+
+// readyz reports whether the server can take traffic: it is not shutting
+// down and every dependency answers.
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	res := readiness{Status: "ok", Checks: make(map[string]check)}
+
+	if s.draining.Load() {
+		res.Status = "unavailable"
+		res.Checks["server"] = check{Status: "unavailable", Error: "shutting down"}
+	} else {
+		res.Checks["server"] = check{Status: "ok"}
+	}
+
+	if err := s.storage.Ping(ctx); err != nil {
+		res.Status = "unavailable"
+		res.Checks[s.storage.Name()] = check{Status: "unavailable", Error: err.Error()}
+	} else {
+		res.Checks[s.storage.Name()] = check{Status: "ok"}
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	if res.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeStorage struct {
+	pingErr error
+	closed  bool
+}
+
+func (f *fakeStorage) Name() string                    { return "fake" }
+func (f *fakeStorage) Ping(ctx context.Context) error  { return f.pingErr }
+func (f *fakeStorage) Close(ctx context.Context) error { f.closed = true; return nil }
+
+func getReadiness(t *testing.T, s *Server) (int, readiness) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var res readiness
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	return w.Code, res
+}
+
+func TestHealthz(t *testing.T) {
+	s := newTestServer(t)
+	s.storage = &fakeStorage{pingErr: errors.New("down")}
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("healthz with storage down: got %d, want 200", w.Code)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	s := newTestServer(t)
+
+	code, res := getReadiness(t, s)
+	if code != http.StatusOK || res.Checks["memory"].Status != "ok" {
+		t.Errorf("got %d %+v", code, res)
+	}
+
+	s.storage = &fakeStorage{pingErr: errors.New("no reachable servers")}
+	code, res = getReadiness(t, s)
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("got %d, want 503", code)
+	}
+	if c := res.Checks["fake"]; c.Status != "unavailable" || c.Error != "no reachable servers" {
+		t.Errorf("unexpected storage check %+v", c)
+	}
+}
+
+func TestRunDrainsRequests(t *testing.T) {
+	s := newTestServer(t)
+	storage := &fakeStorage{}
+	s.storage = storage
+	s.drainTimeout = 300 * time.Millisecond
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.http.Addr = l.Addr().String()
+	l.Close()
+
+	started := make(chan struct{})
+	s.router.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		if c, err := net.Dial("tcp", s.http.Addr); err == nil {
+			c.Close()
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	reqDone := make(chan error, 1)
+	go func() {
+		var err error
+		resp, err = http.Get("http://" + s.http.Addr + "/slow")
+		reqDone <- err
+	}()
+
+	<-started
+	cancel()
+
+	// While draining, the server still accepts connections but is not ready.
+	code := http.StatusOK
+	for i := 0; i < 20 && code == http.StatusOK; i++ {
+		r, err := http.Get("http://" + s.http.Addr + "/readyz")
+		if err != nil {
+			t.Fatalf("readiness while draining: %v", err)
+		}
+		r.Body.Close()
+		code = r.StatusCode
+		time.Sleep(5 * time.Millisecond)
+	}
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("readiness while draining: got %d, want 503", code)
+	}
+
+	if err := <-reqDone; err != nil {
+		t.Fatalf("in-flight request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("in-flight request: got %d", resp.StatusCode)
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("Run: %v", err)
+	}
+	if !storage.closed {
+		t.Error("storage was not closed")
+	}
+	if code, _ := getReadiness(t, s); code != http.StatusServiceUnavailable {
+		t.Errorf("readiness after shutdown: got %d, want 503", code)
+	}
+}
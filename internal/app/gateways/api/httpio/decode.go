@@ -0,0 +1,62 @@
+// Package httpio holds the request decoding shared by the domain handlers.
+package httpio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/literalog/library/pkg/problem"
+)
+
+// MaxBodySize is the largest request body Decode accepts.
+const MaxBodySize = 1 << 20
+
+// Decode strictly decodes a single JSON value from the request body into v.
+// The request must be application/json, at most MaxBodySize bytes, and may
+// not contain fields v does not have. Failures are returned as problems.
+func Decode(w http.ResponseWriter, r *http.Request, v any) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return problem.New(http.StatusUnsupportedMediaType, "content type must be application/json")
+	}
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return decodeProblem(err)
+	}
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return problem.New(http.StatusBadRequest, "body must contain a single JSON value")
+	}
+	return nil
+}
+
+func decodeProblem(err error) *problem.Problem {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		sizeErr   *http.MaxBytesError
+	)
+	switch {
+	case errors.Is(err, io.EOF):
+		return problem.New(http.StatusBadRequest, "body must not be empty")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return problem.New(http.StatusBadRequest, "body contains malformed JSON")
+	case errors.As(err, &syntaxErr):
+		return problem.New(http.StatusBadRequest, fmt.Sprintf("body contains malformed JSON at offset %d", syntaxErr.Offset))
+	case errors.As(err, &typeErr):
+		return problem.New(http.StatusBadRequest, fmt.Sprintf("field %q must be a %s", typeErr.Field, typeErr.Type))
+	case errors.As(err, &sizeErr):
+		return problem.New(http.StatusRequestEntityTooLarge, fmt.Sprintf("body must not exceed %d bytes", sizeErr.Limit))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return problem.New(http.StatusBadRequest, "body contains "+strings.TrimPrefix(err.Error(), "json: "))
+	default:
+		return problem.New(http.StatusBadRequest, err.Error())
+	}
+}
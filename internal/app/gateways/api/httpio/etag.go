@@ -0,0 +1,58 @@
+package httpio
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ETag formats an entity version as a strong entity tag.
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// SetETag sets the ETag header for the given version.
+func SetETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", ETag(version))
+}
+
+// IfMatch reports whether the If-Match header of r, if any, matches version.
+func IfMatch(r *http.Request, version int64) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return true
+	}
+	return matchETag(header, version, false)
+}
+
+// NotModified writes a 304 response and reports true when the If-None-Match
+// header of r matches version.
+func NotModified(w http.ResponseWriter, r *http.Request, version int64) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" || !matchETag(header, version, true) {
+		return false
+	}
+
+	SetETag(w, version)
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// matchETag reports whether any tag in the comma separated header matches
+// version. Weak tags only match when weak comparison is allowed.
+func matchETag(header string, version int64, weak bool) bool {
+	want := ETag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if weak {
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package httpio
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/literalog/library/pkg/problem"
+)
+
+const (
+	MergePatch = "application/merge-patch+json"
+	JSONPatch  = "application/json-patch+json"
+)
+
+// Patch applies the JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) in
+// the request body to the JSON form of v, then strictly decodes the patched
+// document into a zero value replacing v, so fields the patch removes are
+// cleared. v must be a pointer. Failures are returned as problems.
+func Patch(w http.ResponseWriter, r *http.Request, v any) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != MergePatch && mediaType != JSONPatch {
+		return problem.New(http.StatusUnsupportedMediaType, "content type must be "+MergePatch+" or "+JSONPatch)
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	if err != nil {
+		return decodeProblem(err)
+	}
+
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	patched, err := apply(mediaType, doc, body)
+	if err != nil {
+		return err
+	}
+
+	dst := reflect.ValueOf(v).Elem()
+	fresh := reflect.New(dst.Type())
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(fresh.Interface()); err != nil {
+		p := decodeProblem(err)
+		p.Status, p.Title = http.StatusUnprocessableEntity, http.StatusText(http.StatusUnprocessableEntity)
+		p.Detail = "patched document is invalid: " + p.Detail
+		return p
+	}
+	dst.Set(fresh.Elem())
+	return nil
+}
+
+func apply(mediaType string, doc, body []byte) ([]byte, error) {
+	if mediaType == MergePatch {
+		patched, err := jsonpatch.MergePatch(doc, body)
+		if err != nil {
+			return nil, problem.New(http.StatusBadRequest, "body is not a valid merge patch")
+		}
+		return patched, nil
+	}
+
+	patch, err := jsonpatch.DecodePatch(body)
+	if err != nil {
+		return nil, problem.New(http.StatusBadRequest, "body is not a valid JSON patch")
+	}
+
+	patched, err := patch.Apply(doc)
+	switch {
+	case errors.Is(err, jsonpatch.ErrTestFailed):
+		return nil, problem.New(http.StatusConflict, "JSON patch test operation failed")
+	case err != nil:
+		return nil, problem.New(http.StatusUnprocessableEntity, "JSON patch cannot be applied: "+err.Error())
+	default:
+		return patched, nil
+	}
+}
@@ -0,0 +1,15 @@
+package httpio
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Redirect permanently redirects a request for an entity to the entity with
+// the given id in the same collection, as when the requested one was merged
+// into it. The Location is relative, since the collection prefix is stripped
+// from the request path.
+func Redirect(w http.ResponseWriter, id string) {
+	w.Header().Set("Location", url.PathEscape(id))
+	w.WriteHeader(http.StatusPermanentRedirect)
+}
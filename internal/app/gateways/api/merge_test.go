@@ -0,0 +1,145 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/duplicate"
+	"github.com/literalog/library/pkg/models"
+)
+
+// call serves a JSON request and decodes the response into out, if given.
+func call(t *testing.T, s *Server, method, path string, in, out any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := httptest.NewRequest(method, path, &body)
+	r.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+	if out != nil && w.Code < 300 {
+		if err := json.NewDecoder(w.Body).Decode(out); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return w
+}
+
+func TestMergeAuthors(t *testing.T) {
+	s := newTestServer(t)
+
+	var tolkien, jrr, inverted, other models.Author
+	call(t, s, http.MethodPost, "/authors", models.AuthorRequest{Name: "J.R.R. Tolkien"}, &tolkien)
+	call(t, s, http.MethodPost, "/authors", models.AuthorRequest{Name: "JRR Tolkien"}, &jrr)
+	call(t, s, http.MethodPost, "/authors", models.AuthorRequest{Name: "Tolkien, J. R. R."}, &inverted)
+	call(t, s, http.MethodPost, "/authors", models.AuthorRequest{Name: "C. S. Lewis"}, &other)
+
+	var series models.Series
+	call(t, s, http.MethodPost, "/series", models.SeriesRequest{Name: "Middle-earth"}, &series)
+
+	var hobbit, letters models.Book
+	call(t, s, http.MethodPost, "/books", models.BookRequest{Title: "The Hobbit", AuthorId: jrr.Id, SeriesId: series.Id}, &hobbit)
+	call(t, s, http.MethodPost, "/books", models.BookRequest{Title: "Letters", SeriesId: series.Id, Contributors: []models.Contributor{
+		{AuthorId: tolkien.Id, Role: models.RoleAuthor},
+		{AuthorId: inverted.Id, Role: models.RoleAuthor, Order: 1},
+		{AuthorId: other.Id, Role: models.RoleEditor},
+	}}, &letters)
+
+	var clusters models.List[duplicate.Cluster]
+	call(t, s, http.MethodGet, "/authors/duplicates", nil, &clusters)
+	if len(clusters.Items) != 1 || len(clusters.Items[0].Members) != 3 || clusters.Items[0].Score != 1 {
+		t.Fatalf("duplicates: got %+v, want one cluster of the three Tolkiens", clusters.Items)
+	}
+
+	var report duplicate.Report
+	w := call(t, s, http.MethodPost, "/authors/"+tolkien.Id+"/merge", duplicate.MergeRequest{SourceIds: []string{jrr.Id, inverted.Id}}, &report)
+	if w.Code != http.StatusOK {
+		t.Fatalf("merge: got %d: %s", w.Code, w.Body)
+	}
+	books := report.Replaced["books"]
+	slices.Sort(books)
+	want := []string{hobbit.Id, letters.Id}
+	slices.Sort(want)
+	if !slices.Equal(books, want) || len(report.Merged) != 2 {
+		t.Errorf("report: got %+v, want books %v", report, want)
+	}
+
+	var b models.Book
+	call(t, s, http.MethodGet, "/books/"+letters.Id, nil, &b)
+	if len(b.Contributors) != 2 || b.AuthorId != tolkien.Id || !b.HasContributor(other.Id, models.RoleEditor) {
+		t.Errorf("letters contributors: got %+v", b.Contributors)
+	}
+	call(t, s, http.MethodGet, "/books/"+hobbit.Id, nil, &b)
+	if b.AuthorId != tolkien.Id {
+		t.Errorf("hobbit author: got %s, want %s", b.AuthorId, tolkien.Id)
+	}
+
+	w = call(t, s, http.MethodGet, "/authors/"+jrr.Id, nil, nil)
+	if w.Code != http.StatusPermanentRedirect || w.Header().Get("Location") != tolkien.Id {
+		t.Errorf("merged author: got %d to %q, want 308 to %s", w.Code, w.Header().Get("Location"), tolkien.Id)
+	}
+
+	// Merging the target in turn redirects the older aliases too.
+	call(t, s, http.MethodPost, "/authors/"+other.Id+"/merge", duplicate.MergeRequest{SourceIds: []string{tolkien.Id}}, nil)
+	w = call(t, s, http.MethodGet, "/authors/"+jrr.Id, nil, nil)
+	if w.Header().Get("Location") != other.Id {
+		t.Errorf("chained alias: got %q, want %s", w.Header().Get("Location"), other.Id)
+	}
+}
+
+func TestMergeGenres(t *testing.T) {
+	s := newTestServer(t)
+
+	var scifi, dashed models.Genre
+	call(t, s, http.MethodPost, "/genres", models.GenreRequest{Tag: "Science Fiction"}, &scifi)
+	call(t, s, http.MethodPost, "/genres", models.GenreRequest{Tag: "science-fiction"}, &dashed)
+
+	var w models.Work
+	call(t, s, http.MethodPost, "/works", models.WorkRequest{Title: "Dune", Genre: []string{"science-fiction", "Science Fiction"}}, &w)
+
+	var report duplicate.Report
+	call(t, s, http.MethodPost, "/genres/"+scifi.Id+"/merge", duplicate.MergeRequest{SourceIds: []string{dashed.Id}}, &report)
+	if !slices.Equal(report.Replaced["works"], []string{w.Id}) {
+		t.Errorf("report: got %+v, want work %s", report, w.Id)
+	}
+
+	call(t, s, http.MethodGet, "/works/"+w.Id, nil, &w)
+	if !slices.Equal(w.Genre, []string{"Science Fiction"}) {
+		t.Errorf("work genres: got %v", w.Genre)
+	}
+}
+
+func TestMergeErrors(t *testing.T) {
+	s := newTestServer(t)
+
+	var a models.Author
+	call(t, s, http.MethodPost, "/authors", models.AuthorRequest{Name: "Ursula K. Le Guin"}, &a)
+
+	tests := []struct {
+		name    string
+		sources []string
+		want    int
+	}{
+		{"no sources", nil, http.StatusBadRequest},
+		{"into itself", []string{a.Id}, http.StatusBadRequest},
+		{"missing source", []string{"missing"}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := call(t, s, http.MethodPost, "/authors/"+a.Id+"/merge", duplicate.MergeRequest{SourceIds: tt.sources}, nil)
+			if w.Code != tt.want {
+				t.Errorf("got %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
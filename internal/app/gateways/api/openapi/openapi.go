@@ -0,0 +1,26 @@
+// Package openapi serves the OpenAPI description of the library API and an
+// interactive page to browse it.
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+//go:embed openapi.json
+var Spec []byte
+
+//go:embed docs.html
+var docs []byte
+
+// ServeSpec writes the OpenAPI document.
+func ServeSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(Spec)
+}
+
+// ServeDocs writes a Swagger UI page for the OpenAPI document.
+func ServeDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "text/html; charset=utf-8")
+	w.Write(docs)
+}
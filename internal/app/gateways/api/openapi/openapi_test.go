@@ -0,0 +1,104 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/duplicate"
+	"github.com/literalog/library/internal/app/domain/search"
+	"github.com/literalog/library/internal/app/domain/suggest"
+	"github.com/literalog/library/pkg/models"
+)
+
+// jsonFields lists the JSON member names of a struct type, following
+// embedded structs.
+func jsonFields(t reflect.Type) []string {
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && name == "" {
+			fields = append(fields, jsonFields(f.Type)...)
+			continue
+		}
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
+
+func TestSchemasMatchModels(t *testing.T) {
+	var doc struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+				Enum       []string                   `json:"enum"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(Spec, &doc); err != nil {
+		t.Fatalf("invalid openapi document: %v", err)
+	}
+
+	types := map[string]any{
+		"Author":         models.Author{},
+		"AuthorRequest":  models.AuthorRequest{},
+		"Series":         models.Series{},
+		"SeriesRequest":  models.SeriesRequest{},
+		"Genre":          models.Genre{},
+		"GenreRequest":   models.GenreRequest{},
+		"Work":           models.Work{},
+		"WorkRequest":    models.WorkRequest{},
+		"EditionRequest": models.EditionRequest{},
+		"Book":           models.Book{},
+		"BookRequest":    models.BookRequest{},
+		"Contributor":    models.Contributor{},
+		"Hit":            search.Hit{},
+		"Suggestion":     suggest.Suggestion{},
+		"Cluster":        duplicate.Cluster{},
+		"Member":         duplicate.Member{},
+		"MergeRequest":   duplicate.MergeRequest{},
+		"MergeReport":    duplicate.Report{},
+	}
+	for name, model := range types {
+		schema, ok := doc.Components.Schemas[name]
+		if !ok {
+			t.Errorf("schema %s is missing", name)
+			continue
+		}
+
+		want := jsonFields(reflect.TypeOf(model))
+		var got []string
+		for p := range schema.Properties {
+			got = append(got, p)
+		}
+		sort.Strings(want)
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("schema %s has properties %v, model has %v", name, got, want)
+		}
+	}
+
+	formats := doc.Components.Schemas["Format"].Enum
+	for _, f := range []models.Format{models.Hardcover, models.Paperback, models.Digital, models.Audio} {
+		if !slices.Contains(formats, string(f)) {
+			t.Errorf("format %q is missing from the Format enum", f)
+		}
+	}
+
+	roles := doc.Components.Schemas["Role"].Enum
+	for _, r := range []models.Role{models.RoleAuthor, models.RoleTranslator, models.RoleIllustrator, models.RoleNarrator, models.RoleEditor} {
+		if !slices.Contains(roles, string(r)) {
+			t.Errorf("role %q is missing from the Role enum", r)
+		}
+	}
+}
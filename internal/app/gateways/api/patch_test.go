@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/literalog/library/internal/app/gateways/api/httpio"
+	"github.com/literalog/library/pkg/models"
+)
+
+// patch serves a PATCH of path with the given patch document and returns the
+// patched book.
+func patch(t *testing.T, s *Server, path, contentType, body string) models.Book {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodPatch, path, strings.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("patch %s: got %d: %s", body, w.Code, w.Body)
+	}
+
+	var b models.Book
+	if err := json.NewDecoder(w.Body).Decode(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestPatchClearsFields(t *testing.T) {
+	s := newTestServer(t)
+
+	var a models.Author
+	call(t, s, http.MethodPost, "/authors", models.AuthorRequest{Name: "Ursula K. Le Guin"}, &a)
+	call(t, s, http.MethodPost, "/genres", models.GenreRequest{Tag: "fantasy"}, nil)
+	var b models.Book
+	call(t, s, http.MethodPost, "/books", models.BookRequest{
+		Title:    "A Wizard of Earthsea",
+		AuthorId: a.Id,
+		Genre:    []string{"fantasy"},
+		Blurb:    "old",
+		Year:     1968,
+	}, &b)
+
+	got := patch(t, s, "/books/"+b.Id, httpio.MergePatch, `{"blurb": null}`)
+	if got.Blurb != "" || got.Year != 1968 || len(got.Genre) != 1 {
+		t.Errorf("merge patch with null: got blurb %q, year %d, genre %v, want only the blurb cleared", got.Blurb, got.Year, got.Genre)
+	}
+
+	got = patch(t, s, "/books/"+b.Id, httpio.JSONPatch, `[{"op": "remove", "path": "/genre"}]`)
+	if len(got.Genre) != 0 || got.Year != 1968 || got.Title != b.Title {
+		t.Errorf("JSON patch remove: got genre %v, year %d, title %q, want only the genre cleared", got.Genre, got.Year, got.Title)
+	}
+
+	var stored models.Book
+	call(t, s, http.MethodGet, "/books/"+b.Id, nil, &stored)
+	if stored.Blurb != "" || len(stored.Genre) != 0 || stored.Version != 3 {
+		t.Errorf("stored book: got blurb %q, genre %v, version %d", stored.Blurb, stored.Genre, stored.Version)
+	}
+}
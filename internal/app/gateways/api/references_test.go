@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/literalog/library/internal/app/config"
+	"github.com/literalog/library/internal/app/domain/reference"
+	"github.com/literalog/library/pkg/models"
+)
+
+// referencedWork stores an author, a series and a genre, and a work
+// referring to all three.
+func referencedWork(t *testing.T, s *Server) (models.Author, models.Series, models.Genre, models.Work) {
+	t.Helper()
+
+	var a models.Author
+	var sr models.Series
+	var g models.Genre
+	var wk models.Work
+	call(t, s, http.MethodPost, "/authors", models.AuthorRequest{Name: "Ursula K. Le Guin"}, &a)
+	call(t, s, http.MethodPost, "/series", models.SeriesRequest{Name: "Earthsea"}, &sr)
+	call(t, s, http.MethodPost, "/genres", models.GenreRequest{Tag: "fantasy"}, &g)
+	w := call(t, s, http.MethodPost, "/works", models.WorkRequest{
+		Title:        "A Wizard of Earthsea",
+		Contributors: []models.Contributor{{AuthorId: a.Id, Role: models.RoleAuthor}},
+		SeriesId:     sr.Id,
+		Genre:        []string{g.Tag},
+	}, &wk)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create work: got %d: %s", w.Code, w.Body)
+	}
+	return a, sr, g, wk
+}
+
+func TestDeleteRestrictedByWorks(t *testing.T) {
+	s := newTestServer(t)
+	a, sr, g, wk := referencedWork(t, s)
+
+	for _, path := range []string{"/authors/" + a.Id, "/series/" + sr.Id, "/genres/" + g.Id} {
+		w := call(t, s, http.MethodDelete, path, nil, nil)
+		var body struct {
+			WorkIds []string `json:"work_ids"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != http.StatusConflict || !slices.Equal(body.WorkIds, []string{wk.Id}) {
+			t.Errorf("delete %s: got %d with work_ids %v, want 409 with %s", path, w.Code, body.WorkIds, wk.Id)
+		}
+	}
+}
+
+func TestDeleteNullifiesWorks(t *testing.T) {
+	cfg := config.Default()
+	cfg.Storage = config.StorageMemory
+	cfg.OnDelete = reference.Policies{Author: reference.Nullify, Series: reference.Nullify, Genre: reference.Nullify, Work: reference.Restrict}
+	s, err := NewServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, sr, g, wk := referencedWork(t, s)
+
+	for _, path := range []string{"/authors/" + a.Id, "/series/" + sr.Id, "/genres/" + g.Id} {
+		if w := call(t, s, http.MethodDelete, path, nil, nil); w.Code != http.StatusNoContent {
+			t.Fatalf("delete %s: got %d: %s", path, w.Code, w.Body)
+		}
+	}
+
+	call(t, s, http.MethodGet, "/works/"+wk.Id, nil, &wk)
+	if len(wk.Contributors) != 0 || wk.SeriesId != "" || len(wk.Genre) != 0 {
+		t.Errorf("work after the deletes: got %+v, want no references", wk)
+	}
+}
+
+func TestWorkReferences(t *testing.T) {
+	s := newTestServer(t)
+	a, _, _, _ := referencedWork(t, s)
+
+	w := call(t, s, http.MethodPost, "/works", models.WorkRequest{
+		Title:        "The Dispossessed",
+		Contributors: []models.Contributor{{AuthorId: a.Id, Role: models.RoleAuthor}},
+		SeriesId:     "hainish",
+		Genre:        []string{"science-fiction"},
+	}, nil)
+	var body struct {
+		Errors []struct {
+			Field string `json:"field"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	var fields []string
+	for _, e := range body.Errors {
+		fields = append(fields, e.Field)
+	}
+	if w.Code != http.StatusUnprocessableEntity || !slices.Equal(fields, []string{"series_id", "genre[0]"}) {
+		t.Errorf("create: got %d with %v, want 422 on series_id and genre[0]", w.Code, fields)
+	}
+}
+
+func TestWorkUpdateSyncsEditions(t *testing.T) {
+	s := newTestServer(t)
+	a, _, _, wk := referencedWork(t, s)
+
+	var edition models.Book
+	w := call(t, s, http.MethodPost, "/works/"+wk.Id+"/editions", models.EditionRequest{Year: 1968, Format: models.Hardcover}, &edition)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create edition: got %d: %s", w.Code, w.Body)
+	}
+
+	req := models.WorkRequest{
+		Title:        "A Wizard of Earthsea (50th anniversary)",
+		Contributors: []models.Contributor{{AuthorId: a.Id, Role: models.RoleAuthor}},
+		Blurb:        "Ged unleashes a shadow.",
+	}
+	if w := call(t, s, http.MethodPut, "/works/"+wk.Id, req, nil); w.Code != http.StatusOK {
+		t.Fatalf("update work: got %d: %s", w.Code, w.Body)
+	}
+
+	var got models.Book
+	call(t, s, http.MethodGet, "/books/"+edition.Id, nil, &got)
+	if got.Title != req.Title || got.Blurb != req.Blurb || got.SeriesId != "" || len(got.Genre) != 0 || got.Year != 1968 {
+		t.Errorf("edition after the update: got %+v", got)
+	}
+}
+
+func TestGenreRenameRetagsReferrers(t *testing.T) {
+	s := newTestServer(t)
+	a, _, g, wk := referencedWork(t, s)
+
+	var b models.Book
+	call(t, s, http.MethodPost, "/books", models.BookRequest{Title: "A Wizard of Earthsea", AuthorId: a.Id, Genre: []string{g.Tag}}, &b)
+
+	if w := call(t, s, http.MethodPut, "/genres/"+g.Id, models.GenreRequest{Tag: "high-fantasy"}, nil); w.Code != http.StatusOK {
+		t.Fatalf("rename genre: got %d: %s", w.Code, w.Body)
+	}
+
+	call(t, s, http.MethodGet, "/books/"+b.Id, nil, &b)
+	call(t, s, http.MethodGet, "/works/"+wk.Id, nil, &wk)
+	if !slices.Equal(b.Genre, []string{"high-fantasy"}) || !slices.Equal(wk.Genre, []string{"high-fantasy"}) {
+		t.Errorf("after the rename: got book genre %v and work genre %v, want [high-fantasy]", b.Genre, wk.Genre)
+	}
+}
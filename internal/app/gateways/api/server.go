@@ -1,63 +1,347 @@
 package api
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"sync/atomic"
+	"time"
 
+	"github.com/literalog/library/internal/app/config"
+	"github.com/literalog/library/internal/app/domain/alias"
 	"github.com/literalog/library/internal/app/domain/author"
 	"github.com/literalog/library/internal/app/domain/book"
 	"github.com/literalog/library/internal/app/domain/genre"
+	"github.com/literalog/library/internal/app/domain/reference"
+	"github.com/literalog/library/internal/app/domain/search"
 	"github.com/literalog/library/internal/app/domain/series"
+	"github.com/literalog/library/internal/app/domain/suggest"
+	"github.com/literalog/library/internal/app/domain/work"
+	"github.com/literalog/library/internal/app/gateways/api/openapi"
+	"github.com/literalog/library/internal/app/gateways/database/memory"
 	"github.com/literalog/library/internal/app/gateways/database/mongodb"
+	"github.com/literalog/library/internal/app/gateways/database/postgres"
+	"github.com/literalog/library/internal/app/gateways/database/sqlite"
+	searchindex "github.com/literalog/library/internal/app/gateways/search/memory"
 
 	"github.com/gorilla/mux"
 )
 
 type Server struct {
-	port     string
-	logLevel int
-	router   *mux.Router
+	http            *http.Server
+	router          *mux.Router
+	mounts          map[string]*mux.Router
+	storage         Storage
+	drainTimeout    time.Duration
+	shutdownTimeout time.Duration
+	draining        atomic.Bool
 }
 
-func NewServer(port string) Server {
-	s := Server{
-		port:     port,
-		logLevel: 1,
-		router:   mux.NewRouter(),
+// Storage is the backend behind the repositories.
+type Storage interface {
+	Name() string
+	Ping(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+type Repositories struct {
+	Author        author.Repository
+	Series        series.Repository
+	Genre         genre.Repository
+	Work          work.Repository
+	Book          book.Repository
+	AuthorAliases alias.Repository
+	SeriesAliases alias.Repository
+	GenreAliases  alias.Repository
+	Storage       Storage
+}
+
+func NewServer(cfg config.Config) (*Server, error) {
+	s := &Server{
+		router:          mux.NewRouter(),
+		mounts:          make(map[string]*mux.Router),
+		drainTimeout:    cfg.Timeouts.Drain,
+		shutdownTimeout: cfg.Timeouts.Shutdown,
+	}
+	s.http = &http.Server{
+		Addr:         cfg.Addr,
+		Handler:      s.router,
+		ReadTimeout:  cfg.Timeouts.Read,
+		WriteTimeout: cfg.Timeouts.Write,
+		IdleTimeout:  cfg.Timeouts.Idle,
 	}
 
-	storage, err := mongodb.NewMongoStorage()
+	repos, err := NewRepositories(cfg)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
+	s.storage = repos.Storage
 
-	db := storage.Client.Database("library")
+	searchIndex := searchindex.NewIndex()
+	suggester := searchindex.NewSuggester()
+	index := search.Indexers{searchIndex, suggester}
+	if err := rebuildIndex(index, repos, cfg.Timeouts.Connect); err != nil {
+		repos.Storage.Close(context.Background())
+		return nil, err
+	}
+	searchHandler := search.NewHandler(search.NewService(searchIndex))
+	suggestHandler := suggest.NewHandler(suggest.NewService(suggester))
 
-	authorRepository := mongodb.NewAuthorRepository(db.Collection("authors"))
-	authorService := author.NewService(authorRepository)
+	authorReferrers := reference.ReferrersByKind{"books": book.NewAuthorReferrers(repos.Book, index), "works": work.NewAuthorReferrers(repos.Work)}
+	authorReferences := reference.NewEnforcer(cfg.OnDelete.Author, authorReferrers)
+	authorReplacers := reference.Replacers{"books": authorReferrers["books"], "works": authorReferrers["works"]}
+	authorService := author.NewService(repos.Author, authorReferences, index, repos.AuthorAliases, authorReplacers)
 	authorHandler := author.NewHandler(authorService)
 
-	seriesRepository := mongodb.NewSeriesRepository(db.Collection("series"))
-	seriesService := series.NewService(seriesRepository)
+	seriesReferrers := reference.ReferrersByKind{"books": book.NewSeriesReferrers(repos.Book, index), "works": work.NewSeriesReferrers(repos.Work)}
+	seriesReferences := reference.NewEnforcer(cfg.OnDelete.Series, seriesReferrers)
+	seriesReplacers := reference.Replacers{"books": seriesReferrers["books"], "works": seriesReferrers["works"]}
+	seriesService := series.NewService(repos.Series, seriesReferences, index, repos.SeriesAliases, seriesReplacers)
 	seriesHandler := series.NewHandler(seriesService)
 
-	genreRepository := mongodb.NewGenreRepository(db.Collection("genre"))
-	genreService := genre.NewService(genreRepository)
+	genreReferrers := reference.ReferrersByKind{"books": book.NewGenreReferrers(repos.Book, index), "works": work.NewGenreReferrers(repos.Work)}
+	genreReferences := reference.NewEnforcer(cfg.OnDelete.Genre, genreReferrers)
+	genreReplacers := reference.Replacers{"books": genreReferrers["books"], "works": genreReferrers["works"]}
+	genreService := genre.NewService(repos.Genre, genreReferences, index, repos.GenreAliases, genreReplacers)
 	genreHandler := genre.NewHandler(genreService)
 
-	bookRepository := mongodb.NewBookRepository(db.Collection("books"))
-	bookService := book.NewService(bookRepository)
+	workReferences := reference.NewEnforcer(cfg.OnDelete.Work, reference.ReferrersByKind{"books": book.NewWorkReferrers(repos.Book, index)})
+	workService := work.NewService(repos.Work, workReferences, book.NewEditions(repos.Book, index), authorService, seriesService, genreService)
+	workHandler := work.NewHandler(workService)
+
+	bookService, err := book.NewService(book.Dependencies{
+		Repository: repos.Book,
+		Authors:    authorService,
+		Series:     seriesService,
+		Genres:     genreService,
+		Works:      workService,
+		Index:      index,
+	})
+	if err != nil {
+		repos.Storage.Close(context.Background())
+		return nil, err
+	}
 	bookHandler := book.NewHandler(bookService)
 
-	s.router.PathPrefix("/authors").Handler(authorHandler.Routes())
-	s.router.PathPrefix("/series").Handler(seriesHandler.Routes())
-	s.router.PathPrefix("/genres").Handler(genreHandler.Routes())
-	s.router.PathPrefix("/books").Handler(bookHandler.Routes())
+	s.router.HandleFunc("/authors/{id}/books", bookHandler.GetByAuthor).Methods(http.MethodGet)
+	s.router.HandleFunc("/series/{id}/books", bookHandler.GetBySeries).Methods(http.MethodGet)
+	s.router.HandleFunc("/genres/{id}/books", bookHandler.GetByGenre).Methods(http.MethodGet)
+	s.router.HandleFunc("/works/{id}/editions", bookHandler.GetByWork).Methods(http.MethodGet)
+	s.router.HandleFunc("/works/{id}/editions", bookHandler.CreateEdition).Methods(http.MethodPost)
+
+	s.router.HandleFunc("/healthz", s.healthz).Methods(http.MethodGet)
+	s.router.HandleFunc("/readyz", s.readyz).Methods(http.MethodGet)
+
+	if cfg.Features.Docs {
+		s.router.HandleFunc("/openapi.json", openapi.ServeSpec).Methods(http.MethodGet)
+		s.router.HandleFunc("/docs", openapi.ServeDocs).Methods(http.MethodGet)
+	}
+
+	s.mount("/authors", authorHandler.Routes())
+	s.mount("/series", seriesHandler.Routes())
+	s.mount("/genres", genreHandler.Routes())
+	s.mount("/works", workHandler.Routes())
+	s.mount("/books", bookHandler.Routes())
+	s.mount("/search", searchHandler.Routes())
+	s.mount("/suggest", suggestHandler.Routes())
+
+	return s, nil
+}
+
+// mount serves a domain router under prefix. The bare prefix is served as
+// the domain's "/" route rather than redirected.
+func (s *Server) mount(prefix string, routes *mux.Router) {
+	s.mounts[prefix] = routes
+	s.router.PathPrefix(prefix).Handler(http.StripPrefix(prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "" {
+			r.URL.Path = "/"
+		}
+		routes.ServeHTTP(w, r)
+	})))
+}
+
+// rebuildIndex indexes the stored authors, series, genres and books, so
+// search and suggestions cover what was written before the server started.
+func rebuildIndex(index search.Indexer, repos *Repositories, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	aa, err := repos.Author.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	if err := search.Rebuild(ctx, index, aa, author.Document); err != nil {
+		return err
+	}
+
+	ss, err := repos.Series.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	if err := search.Rebuild(ctx, index, ss, series.Document); err != nil {
+		return err
+	}
+
+	gg, err := repos.Genre.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	if err := search.Rebuild(ctx, index, gg, genre.Document); err != nil {
+		return err
+	}
 
-	return s
+	bb, err := repos.Book.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	return search.Rebuild(ctx, index, bb, book.Document)
 }
 
-func (s *Server) ServeHttp() error {
-	log.Println("Server listening on", s.port)
-	return http.ListenAndServe(s.port, s.router)
+func NewRepositories(cfg config.Config) (*Repositories, error) {
+	switch cfg.Storage {
+	case config.StorageMemory:
+		return &Repositories{
+			Author:        memory.NewAuthorRepository(),
+			Series:        memory.NewSeriesRepository(),
+			Genre:         memory.NewGenreRepository(),
+			Work:          memory.NewWorkRepository(),
+			Book:          memory.NewBookRepository(),
+			AuthorAliases: memory.NewAliasRepository(),
+			SeriesAliases: memory.NewAliasRepository(),
+			GenreAliases:  memory.NewAliasRepository(),
+			Storage:       memory.Storage{},
+		}, nil
+	case config.StorageMongo, "":
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeouts.Connect)
+		defer cancel()
+
+		mongoStorage, err := mongodb.NewMongoStorage(ctx, cfg.Mongo.URI)
+		if err != nil {
+			return nil, err
+		}
+
+		db := mongoStorage.Client.Database(cfg.Mongo.Database)
+
+		report, err := mongodb.Bootstrap(ctx, db)
+		if err != nil {
+			mongoStorage.Close(ctx)
+			return nil, err
+		}
+		for _, d := range report.DuplicateTags {
+			slog.Warn("genre tag shared by several genres, merge them with POST /genres/{id}/merge to make tags unique", "tag", d.Tag, "ids", d.Ids)
+		}
+
+		return &Repositories{
+			Author:        mongodb.NewAuthorRepository(db.Collection("authors")),
+			Series:        mongodb.NewSeriesRepository(db.Collection("series")),
+			Genre:         mongodb.NewGenreRepository(db.Collection("genre")),
+			Work:          mongodb.NewWorkRepository(db.Collection("works")),
+			Book:          mongodb.NewBookRepository(db.Collection("books")),
+			AuthorAliases: mongodb.NewAliasRepository(db.Collection("author_aliases")),
+			SeriesAliases: mongodb.NewAliasRepository(db.Collection("series_aliases")),
+			GenreAliases:  mongodb.NewAliasRepository(db.Collection("genre_aliases")),
+			Storage:       mongoStorage,
+		}, nil
+	case config.StoragePostgres:
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeouts.Connect)
+		defer cancel()
+
+		pgStorage, err := postgres.NewStorage(ctx, cfg.Postgres.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		migrator, err := postgres.NewMigrator(pgStorage.Pool)
+		if err != nil {
+			pgStorage.Close(ctx)
+			return nil, err
+		}
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			pgStorage.Close(ctx)
+			return nil, err
+		}
+		if len(pending) > 0 {
+			pgStorage.Close(ctx)
+			return nil, fmt.Errorf("%d postgres migrations are pending, run library migrate up", len(pending))
+		}
+
+		pool := pgStorage.Pool
+		return &Repositories{
+			Author:        postgres.NewAuthorRepository(pool),
+			Series:        postgres.NewSeriesRepository(pool),
+			Genre:         postgres.NewGenreRepository(pool),
+			Work:          postgres.NewWorkRepository(pool),
+			Book:          postgres.NewBookRepository(pool),
+			AuthorAliases: postgres.NewAliasRepository(pool, "author_aliases"),
+			SeriesAliases: postgres.NewAliasRepository(pool, "series_aliases"),
+			GenreAliases:  postgres.NewAliasRepository(pool, "genre_aliases"),
+			Storage:       pgStorage,
+		}, nil
+	case config.StorageSQLite:
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeouts.Connect)
+		defer cancel()
+
+		sqliteStorage, err := sqlite.NewStorage(ctx, cfg.SQLite.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		db := sqliteStorage.DB
+		return &Repositories{
+			Author:        sqlite.NewAuthorRepository(db),
+			Series:        sqlite.NewSeriesRepository(db),
+			Genre:         sqlite.NewGenreRepository(db),
+			Work:          sqlite.NewWorkRepository(db),
+			Book:          sqlite.NewBookRepository(db),
+			AuthorAliases: sqlite.NewAliasRepository(db, "author_aliases"),
+			SeriesAliases: sqlite.NewAliasRepository(db, "series_aliases"),
+			GenreAliases:  sqlite.NewAliasRepository(db, "genre_aliases"),
+			Storage:       sqliteStorage,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage %q", cfg.Storage)
+	}
+}
+
+// Handler returns the handler serving the whole API.
+func (s *Server) Handler() http.Handler {
+	return s.router
+}
+
+// Run serves the API until ctx is done. It then reports not ready for the
+// drain timeout, so that load balancers stop routing to it, and stops
+// accepting connections, waits for in-flight requests and closes the
+// storage, all within the shutdown timeout.
+func (s *Server) Run(ctx context.Context) error {
+	errc := make(chan error, 1)
+	go func() {
+		slog.Info("server listening", "addr", s.http.Addr)
+		errc <- s.http.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		s.storage.Close(context.Background())
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down", "drain", s.drainTimeout, "timeout", s.shutdownTimeout)
+	s.draining.Store(true)
+	time.Sleep(s.drainTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	err := s.http.Shutdown(shutdownCtx)
+	if err != nil {
+		err = fmt.Errorf("error draining requests: %w", err)
+	}
+	if cerr := s.storage.Close(shutdownCtx); cerr != nil {
+		err = errors.Join(err, fmt.Errorf("error closing %s: %w", s.storage.Name(), cerr))
+	}
+	return err
 }
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/literalog/library/internal/app/config"
+	"github.com/literalog/library/internal/app/gateways/api/openapi"
+)
+
+// routes lists every "METHOD /path" served by s, including the routes of the
+// mounted domain routers.
+func (s *Server) routes(t *testing.T) []string {
+	t.Helper()
+
+	var routes []string
+	collect := func(prefix string, router *mux.Router) {
+		err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+			tmpl, err := route.GetPathTemplate()
+			if err != nil {
+				return err
+			}
+			methods, err := route.GetMethods()
+			if err != nil {
+				// Mounts have no methods; their routes are collected separately.
+				return nil
+			}
+
+			path := prefix + tmpl
+			if prefix != "" && tmpl == "/" {
+				path = prefix
+			}
+			for _, m := range methods {
+				routes = append(routes, m+" "+path)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collect("", s.router)
+	for prefix, router := range s.mounts {
+		collect(prefix, router)
+	}
+
+	sort.Strings(routes)
+	return routes
+}
+
+// operations lists every "METHOD /path" described by the OpenAPI document.
+func operations(t *testing.T) []string {
+	t.Helper()
+
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openapi.Spec, &doc); err != nil {
+		t.Fatalf("invalid openapi document: %v", err)
+	}
+
+	var ops []string
+	for path, item := range doc.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+			ops = append(ops, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	sort.Strings(ops)
+	return ops
+}
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Storage = config.StorageMemory
+	s, err := NewServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	s := newTestServer(t)
+
+	routes := s.routes(t)
+	ops := operations(t)
+
+	documented := make(map[string]bool, len(ops))
+	for _, op := range ops {
+		documented[op] = true
+	}
+	served := make(map[string]bool, len(routes))
+	for _, r := range routes {
+		served[r] = true
+		if !documented[r] {
+			t.Errorf("route %s is missing from openapi.json", r)
+		}
+	}
+	for _, op := range ops {
+		if !served[op] {
+			t.Errorf("openapi.json describes %s, which is not routed", op)
+		}
+	}
+}
+
+func TestOpenAPIServed(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, path := range []string{"/openapi.json", "/docs"} {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s: got status %d", path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/literalog/library/internal/app/domain/alias"
+)
+
+type AliasRepository struct {
+	mu      sync.RWMutex
+	aliases map[string]string
+}
+
+func NewAliasRepository() alias.Repository {
+	return &AliasRepository{
+		aliases: make(map[string]string),
+	}
+}
+
+func (r *AliasRepository) Redirect(ctx context.Context, id string, from []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for a, to := range r.aliases {
+		if slices.Contains(from, to) {
+			r.aliases[a] = id
+		}
+	}
+	for _, a := range from {
+		r.aliases[a] = id
+	}
+	return nil
+}
+
+func (r *AliasRepository) Resolve(ctx context.Context, id string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	to, ok := r.aliases[id]
+	if !ok {
+		return "", alias.ErrNotFound
+	}
+	return to, nil
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"sync"
+
+	"github.com/literalog/library/internal/app/domain/author"
+	"github.com/literalog/library/pkg/models"
+)
+
+type AuthorRepository struct {
+	mu      sync.RWMutex
+	ids     []string
+	authors map[string]models.Author
+}
+
+func NewAuthorRepository() author.Repository {
+	return &AuthorRepository{
+		authors: make(map[string]models.Author),
+	}
+}
+
+func (r *AuthorRepository) Create(ctx context.Context, a *models.Author) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.authors[a.Id]; ok {
+		return author.ErrConflict
+	}
+	r.authors[a.Id] = *a
+	r.ids = append(r.ids, a.Id)
+	return nil
+}
+
+func (r *AuthorRepository) Update(ctx context.Context, a *models.Author) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.authors[a.Id]
+	if !ok {
+		return author.ErrNotFound
+	}
+	if stored.Version != a.Version {
+		return author.ErrVersionMismatch
+	}
+
+	a.Meta = a.Meta.Next()
+	r.authors[a.Id] = *a
+	return nil
+}
+
+func (r *AuthorRepository) Delete(ctx context.Context, id string, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.authors[id]
+	if !ok {
+		return author.ErrNotFound
+	}
+	if version != 0 && stored.Version != version {
+		return author.ErrVersionMismatch
+	}
+	delete(r.authors, id)
+	r.ids = removeId(r.ids, id)
+	return nil
+}
+
+func (r *AuthorRepository) GetById(ctx context.Context, id string) (*models.Author, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a, ok := r.authors[id]
+	if !ok {
+		return nil, author.ErrNotFound
+	}
+	return &a, nil
+}
+
+func (r *AuthorRepository) GetByIds(ctx context.Context, ids []string) ([]models.Author, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	aa := make([]models.Author, 0, len(ids))
+	for _, id := range ids {
+		if a, ok := r.authors[id]; ok {
+			aa = append(aa, a)
+		}
+	}
+	return aa, nil
+}
+
+func (r *AuthorRepository) GetAll(ctx context.Context) ([]models.Author, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	aa := make([]models.Author, 0, len(r.ids))
+	for _, id := range r.ids {
+		aa = append(aa, r.authors[id])
+	}
+	return aa, nil
+}
+
+func (r *AuthorRepository) List(ctx context.Context, q author.Query) ([]models.Author, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	aa := make([]models.Author, 0)
+	for _, id := range r.ids {
+		a := r.authors[id]
+		if q.Name != "" && !containsFold(a.Name, q.Name) {
+			continue
+		}
+		aa = append(aa, a)
+	}
+
+	aa, total := page(aa, q.ListOptions, compareAuthor)
+	return aa, total, nil
+}
+
+func compareAuthor(a, b *models.Author, field string) int {
+	switch field {
+	case "name":
+		return cmp.Compare(a.Name, b.Name)
+	}
+	return 0
+}
@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/literalog/library/internal/app/domain/book"
+	"github.com/literalog/library/pkg/models"
+)
+
+type BookRepository struct {
+	mu    sync.RWMutex
+	ids   []string
+	books map[string]models.Book
+}
+
+func NewBookRepository() book.Repository {
+	return &BookRepository{
+		books: make(map[string]models.Book),
+	}
+}
+
+func (r *BookRepository) Create(ctx context.Context, b *models.Book) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.books[b.Id]; ok {
+		return book.ErrConflict
+	}
+	if r.isbnTaken(b) {
+		return book.ErrDuplicateIsbn
+	}
+	r.books[b.Id] = cloneBook(*b)
+	r.ids = append(r.ids, b.Id)
+	return nil
+}
+
+func (r *BookRepository) Update(ctx context.Context, b *models.Book) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.books[b.Id]
+	if !ok {
+		return book.ErrNotFound
+	}
+	if stored.Version != b.Version {
+		return book.ErrVersionMismatch
+	}
+	if r.isbnTaken(b) {
+		return book.ErrDuplicateIsbn
+	}
+
+	b.Meta = b.Meta.Next()
+	r.books[b.Id] = cloneBook(*b)
+	return nil
+}
+
+func (r *BookRepository) Delete(ctx context.Context, id string, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.books[id]
+	if !ok {
+		return book.ErrNotFound
+	}
+	if version != 0 && stored.Version != version {
+		return book.ErrVersionMismatch
+	}
+	delete(r.books, id)
+	r.ids = removeId(r.ids, id)
+	return nil
+}
+
+func (r *BookRepository) GetById(ctx context.Context, id string) (*models.Book, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	b, ok := r.books[id]
+	if !ok {
+		return nil, book.ErrNotFound
+	}
+	b = cloneBook(b)
+	return &b, nil
+}
+
+func (r *BookRepository) GetByIsbn(ctx context.Context, isbn string) (*models.Book, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, id := range r.ids {
+		if b := r.books[id]; slices.Contains(b.Isbn, isbn) {
+			b = cloneBook(b)
+			return &b, nil
+		}
+	}
+	return nil, book.ErrNotFound
+}
+
+func (r *BookRepository) GetAll(ctx context.Context) ([]models.Book, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bb := make([]models.Book, 0, len(r.ids))
+	for _, id := range r.ids {
+		bb = append(bb, cloneBook(r.books[id]))
+	}
+	return bb, nil
+}
+
+// isbnTaken reports whether another book has one of the ISBNs of b, which
+// the unique index rejects in Mongo.
+func (r *BookRepository) isbnTaken(b *models.Book) bool {
+	for _, id := range r.ids {
+		other := r.books[id]
+		if other.Id == b.Id {
+			continue
+		}
+		for _, isbn := range b.Isbn {
+			if slices.Contains(other.Isbn, isbn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cloneBook copies the slice fields so callers can't mutate stored books.
+func cloneBook(b models.Book) models.Book {
+	b.Contributors = slices.Clone(b.Contributors)
+	b.Isbn = slices.Clone(b.Isbn)
+	b.Genre = slices.Clone(b.Genre)
+	return b
+}
+
+func (r *BookRepository) List(ctx context.Context, q book.Query) ([]models.Book, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bb := make([]models.Book, 0)
+	for _, id := range r.ids {
+		b := cloneBook(r.books[id])
+		if !matchBook(&b, q) {
+			continue
+		}
+		bb = append(bb, b)
+	}
+
+	bb, total := page(bb, q.ListOptions, compareBook)
+	return bb, total, nil
+}
+
+func compareBook(a, b *models.Book, field string) int {
+	switch field {
+	case "title":
+		return cmp.Compare(a.Title, b.Title)
+	case "year":
+		return cmp.Compare(a.Year, b.Year)
+	case "series_no":
+		return cmp.Compare(a.SeriesNo, b.SeriesNo)
+	case "language":
+		return cmp.Compare(a.Language, b.Language)
+	case "format":
+		return cmp.Compare(a.Format, b.Format)
+	case "pages_no":
+		return cmp.Compare(a.PagesNo, b.PagesNo)
+	case "hours_no":
+		return cmp.Compare(a.HoursNo, b.HoursNo)
+	}
+	return 0
+}
+
+func matchBook(b *models.Book, q book.Query) bool {
+	switch {
+	case q.WorkId != "" && b.WorkId != q.WorkId:
+		return false
+	case q.Isbn != "" && !slices.Contains(b.Isbn, q.Isbn):
+		return false
+	case q.AuthorId != "" && !b.HasContributor(q.AuthorId, q.Role):
+		return false
+	case q.SeriesId != "" && b.SeriesId != q.SeriesId:
+		return false
+	case q.Genre != "" && !slices.Contains(b.Genre, q.Genre):
+		return false
+	case q.Language != "" && b.Language != q.Language:
+		return false
+	case q.Format != "" && b.Format != q.Format:
+		return false
+	case q.YearFrom != 0 && b.Year < q.YearFrom:
+		return false
+	case q.YearTo != 0 && b.Year > q.YearTo:
+		return false
+	case q.Text != "" && !matchText(b, q.Text):
+		return false
+	default:
+		return true
+	}
+}
+
+// matchText reports whether every word of text is a word of the title or
+// blurb of b, regardless of case.
+func matchText(b *models.Book, text string) bool {
+	have := textWords(b.Title + " " + b.Blurb)
+	for _, w := range textWords(text) {
+		if !slices.Contains(have, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func textWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func (r *BookRepository) GetByAuthorId(ctx context.Context, authorId string, role models.Role, opts models.ListOptions) ([]models.Book, int64, error) {
+	return r.List(ctx, book.Query{ListOptions: opts, AuthorId: authorId, Role: role})
+}
+
+func (r *BookRepository) GetBySeriesId(ctx context.Context, seriesId string, opts models.ListOptions) ([]models.Book, int64, error) {
+	return r.List(ctx, book.Query{ListOptions: opts, SeriesId: seriesId})
+}
+
+func (r *BookRepository) GetByGenre(ctx context.Context, tag string, opts models.ListOptions) ([]models.Book, int64, error) {
+	return r.List(ctx, book.Query{ListOptions: opts, Genre: tag})
+}
+
+func (r *BookRepository) GetByWorkId(ctx context.Context, workId string, opts models.ListOptions) ([]models.Book, int64, error) {
+	return r.List(ctx, book.Query{ListOptions: opts, WorkId: workId})
+}
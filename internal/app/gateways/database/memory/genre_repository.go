@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/literalog/library/internal/app/domain/genre"
+	"github.com/literalog/library/pkg/models"
+)
+
+type GenreRepository struct {
+	mu     sync.RWMutex
+	ids    []string
+	genres map[string]models.Genre
+}
+
+func NewGenreRepository() genre.Repository {
+	return &GenreRepository{
+		genres: make(map[string]models.Genre),
+	}
+}
+
+func (r *GenreRepository) Create(ctx context.Context, g *models.Genre) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.genres[g.Id]; ok {
+		return genre.ErrConflict
+	}
+	if r.tagTaken(g) {
+		return genre.ErrDuplicateTag
+	}
+	r.genres[g.Id] = *g
+	r.ids = append(r.ids, g.Id)
+	return nil
+}
+
+func (r *GenreRepository) Update(ctx context.Context, g *models.Genre) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.genres[g.Id]
+	if !ok {
+		return genre.ErrNotFound
+	}
+	if stored.Version != g.Version {
+		return genre.ErrVersionMismatch
+	}
+	if r.tagTaken(g) {
+		return genre.ErrDuplicateTag
+	}
+
+	g.Meta = g.Meta.Next()
+	r.genres[g.Id] = *g
+	return nil
+}
+
+// tagTaken reports whether another genre than g has its tag.
+func (r *GenreRepository) tagTaken(g *models.Genre) bool {
+	for _, id := range r.ids {
+		if other := r.genres[id]; other.Id != g.Id && other.Tag == g.Tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *GenreRepository) Delete(ctx context.Context, id string, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.genres[id]
+	if !ok {
+		return genre.ErrNotFound
+	}
+	if version != 0 && stored.Version != version {
+		return genre.ErrVersionMismatch
+	}
+	delete(r.genres, id)
+	r.ids = removeId(r.ids, id)
+	return nil
+}
+
+func (r *GenreRepository) GetById(ctx context.Context, id string) (*models.Genre, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	g, ok := r.genres[id]
+	if !ok {
+		return nil, genre.ErrNotFound
+	}
+	return &g, nil
+}
+
+func (r *GenreRepository) GetByName(ctx context.Context, name string) (*models.Genre, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, id := range r.ids {
+		if g := r.genres[id]; g.Tag == name {
+			return &g, nil
+		}
+	}
+	return nil, genre.ErrNotFound
+}
+
+func (r *GenreRepository) GetByTags(ctx context.Context, tags []string) ([]models.Genre, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	gg := make([]models.Genre, 0, len(tags))
+	for _, id := range r.ids {
+		if g := r.genres[id]; slices.Contains(tags, g.Tag) {
+			gg = append(gg, g)
+		}
+	}
+	return gg, nil
+}
+
+func (r *GenreRepository) GetAll(ctx context.Context) ([]models.Genre, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	gg := make([]models.Genre, 0, len(r.ids))
+	for _, id := range r.ids {
+		gg = append(gg, r.genres[id])
+	}
+	return gg, nil
+}
+
+func (r *GenreRepository) List(ctx context.Context, q genre.Query) ([]models.Genre, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	gg := make([]models.Genre, 0)
+	for _, id := range r.ids {
+		g := r.genres[id]
+		if q.Tag != "" && !containsFold(g.Tag, q.Tag) {
+			continue
+		}
+		gg = append(gg, g)
+	}
+
+	gg, total := page(gg, q.ListOptions, compareGenre)
+	return gg, total, nil
+}
+
+func compareGenre(a, b *models.Genre, field string) int {
+	switch field {
+	case "tag":
+		return cmp.Compare(a.Tag, b.Tag)
+	}
+	return 0
+}
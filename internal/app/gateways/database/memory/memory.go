@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/literalog/library/pkg/models"
+)
+
+// Storage stands for the process memory holding the repositories, which is
+// always available.
+type Storage struct{}
+
+func (Storage) Name() string {
+	return "memory"
+}
+
+func (Storage) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (Storage) Close(ctx context.Context) error {
+	return nil
+}
+
+func removeId(ids []string, id string) []string {
+	if i := slices.Index(ids, id); i >= 0 {
+		return slices.Delete(ids, i, i+1)
+	}
+	return ids
+}
+
+// page sorts the matching items and slices out the requested page, returning
+// it along with the number of matching items. The sort is stable so items
+// that compare equal keep their insertion order.
+func page[T any](items []T, opts models.ListOptions, compare func(a, b *T, field string) int) ([]T, int64) {
+	if len(opts.Sort) > 0 {
+		slices.SortStableFunc(items, func(a, b T) int {
+			for _, f := range opts.Sort {
+				c := compare(&a, &b, f.Field)
+				if f.Desc {
+					c = -c
+				}
+				if c != 0 {
+					return c
+				}
+			}
+			return 0
+		})
+	}
+
+	total := int64(len(items))
+	if opts.Offset >= len(items) {
+		return items[:0], total
+	}
+	items = items[opts.Offset:]
+	if opts.Limit > 0 && opts.Limit < len(items) {
+		items = items[:opts.Limit]
+	}
+	return items, total
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
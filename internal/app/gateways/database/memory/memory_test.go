@@ -0,0 +1,23 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/author"
+	"github.com/literalog/library/internal/app/domain/book"
+	"github.com/literalog/library/internal/app/domain/genre"
+	"github.com/literalog/library/internal/app/domain/series"
+	"github.com/literalog/library/internal/app/domain/work"
+	"github.com/literalog/library/internal/app/gateways/database/memory"
+	"github.com/literalog/library/internal/app/gateways/database/repotest"
+)
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, repotest.Factories{
+		Authors: func(t *testing.T) author.Repository { return memory.NewAuthorRepository() },
+		Series:  func(t *testing.T) series.Repository { return memory.NewSeriesRepository() },
+		Genres:  func(t *testing.T) genre.Repository { return memory.NewGenreRepository() },
+		Works:   func(t *testing.T) work.Repository { return memory.NewWorkRepository() },
+		Books:   func(t *testing.T) book.Repository { return memory.NewBookRepository() },
+	})
+}
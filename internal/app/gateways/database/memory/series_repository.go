@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"sync"
+
+	"github.com/literalog/library/internal/app/domain/series"
+	"github.com/literalog/library/pkg/models"
+)
+
+type SeriesRepository struct {
+	mu     sync.RWMutex
+	ids    []string
+	series map[string]models.Series
+}
+
+func NewSeriesRepository() series.Repository {
+	return &SeriesRepository{
+		series: make(map[string]models.Series),
+	}
+}
+
+func (r *SeriesRepository) Create(ctx context.Context, s *models.Series) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.series[s.Id]; ok {
+		return series.ErrConflict
+	}
+	r.series[s.Id] = *s
+	r.ids = append(r.ids, s.Id)
+	return nil
+}
+
+func (r *SeriesRepository) Update(ctx context.Context, s *models.Series) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.series[s.Id]
+	if !ok {
+		return series.ErrNotFound
+	}
+	if stored.Version != s.Version {
+		return series.ErrVersionMismatch
+	}
+
+	s.Meta = s.Meta.Next()
+	r.series[s.Id] = *s
+	return nil
+}
+
+func (r *SeriesRepository) Delete(ctx context.Context, id string, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.series[id]
+	if !ok {
+		return series.ErrNotFound
+	}
+	if version != 0 && stored.Version != version {
+		return series.ErrVersionMismatch
+	}
+	delete(r.series, id)
+	r.ids = removeId(r.ids, id)
+	return nil
+}
+
+func (r *SeriesRepository) GetById(ctx context.Context, id string) (*models.Series, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.series[id]
+	if !ok {
+		return nil, series.ErrNotFound
+	}
+	return &s, nil
+}
+
+func (r *SeriesRepository) GetAll(ctx context.Context) ([]models.Series, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ss := make([]models.Series, 0, len(r.ids))
+	for _, id := range r.ids {
+		ss = append(ss, r.series[id])
+	}
+	return ss, nil
+}
+
+func (r *SeriesRepository) List(ctx context.Context, q series.Query) ([]models.Series, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ss := make([]models.Series, 0)
+	for _, id := range r.ids {
+		s := r.series[id]
+		if q.Name != "" && !containsFold(s.Name, q.Name) {
+			continue
+		}
+		ss = append(ss, s)
+	}
+
+	ss, total := page(ss, q.ListOptions, compareSeries)
+	return ss, total, nil
+}
+
+func compareSeries(a, b *models.Series, field string) int {
+	switch field {
+	case "name":
+		return cmp.Compare(a.Name, b.Name)
+	}
+	return 0
+}
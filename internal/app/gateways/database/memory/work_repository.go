@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/literalog/library/internal/app/domain/work"
+	"github.com/literalog/library/pkg/models"
+)
+
+type WorkRepository struct {
+	mu    sync.RWMutex
+	ids   []string
+	works map[string]models.Work
+}
+
+func NewWorkRepository() work.Repository {
+	return &WorkRepository{
+		works: make(map[string]models.Work),
+	}
+}
+
+func (r *WorkRepository) Create(ctx context.Context, w *models.Work) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.works[w.Id]; ok {
+		return work.ErrConflict
+	}
+	r.works[w.Id] = cloneWork(*w)
+	r.ids = append(r.ids, w.Id)
+	return nil
+}
+
+func (r *WorkRepository) Update(ctx context.Context, w *models.Work) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.works[w.Id]
+	if !ok {
+		return work.ErrNotFound
+	}
+	if stored.Version != w.Version {
+		return work.ErrVersionMismatch
+	}
+
+	w.Meta = w.Meta.Next()
+	r.works[w.Id] = cloneWork(*w)
+	return nil
+}
+
+func (r *WorkRepository) Delete(ctx context.Context, id string, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.works[id]
+	if !ok {
+		return work.ErrNotFound
+	}
+	if version != 0 && stored.Version != version {
+		return work.ErrVersionMismatch
+	}
+	delete(r.works, id)
+	r.ids = removeId(r.ids, id)
+	return nil
+}
+
+func (r *WorkRepository) GetById(ctx context.Context, id string) (*models.Work, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	w, ok := r.works[id]
+	if !ok {
+		return nil, work.ErrNotFound
+	}
+	w = cloneWork(w)
+	return &w, nil
+}
+
+func (r *WorkRepository) GetAll(ctx context.Context) ([]models.Work, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ww := make([]models.Work, 0, len(r.ids))
+	for _, id := range r.ids {
+		ww = append(ww, cloneWork(r.works[id]))
+	}
+	return ww, nil
+}
+
+func (r *WorkRepository) List(ctx context.Context, q work.Query) ([]models.Work, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ww := make([]models.Work, 0)
+	for _, id := range r.ids {
+		w := cloneWork(r.works[id])
+		if q.Title != "" && !containsFold(w.Title, q.Title) {
+			continue
+		}
+		if q.SeriesId != "" && w.SeriesId != q.SeriesId {
+			continue
+		}
+		ww = append(ww, w)
+	}
+
+	ww, total := page(ww, q.ListOptions, compareWork)
+	return ww, total, nil
+}
+
+func compareWork(a, b *models.Work, field string) int {
+	switch field {
+	case "title":
+		return cmp.Compare(a.Title, b.Title)
+	case "series_no":
+		return cmp.Compare(a.SeriesNo, b.SeriesNo)
+	}
+	return 0
+}
+
+func cloneWork(w models.Work) models.Work {
+	w.Contributors = slices.Clone(w.Contributors)
+	w.Genre = slices.Clone(w.Genre)
+	return w
+}
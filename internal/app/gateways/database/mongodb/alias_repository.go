@@ -0,0 +1,56 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/literalog/library/internal/app/domain/alias"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AliasRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAliasRepository(collection *mongo.Collection) alias.Repository {
+	return &AliasRepository{
+		collection: collection,
+	}
+}
+
+type aliasDocument struct {
+	Id string `bson:"_id"`
+	To string `bson:"to"`
+}
+
+func (r *AliasRepository) Redirect(ctx context.Context, id string, from []string) error {
+	filter := bson.M{"to": bson.M{"$in": from}}
+	update := bson.M{"$set": bson.M{"to": id}}
+	if _, err := r.collection.UpdateMany(ctx, filter, update); err != nil {
+		return fmt.Errorf("error redirecting aliases: %w", err)
+	}
+
+	for _, a := range from {
+		doc := aliasDocument{Id: a, To: id}
+		if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": a}, doc, options.Replace().SetUpsert(true)); err != nil {
+			return fmt.Errorf("error creating alias: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *AliasRepository) Resolve(ctx context.Context, id string) (string, error) {
+	doc := new(aliasDocument)
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(doc)
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return "", alias.ErrNotFound
+	case err != nil:
+		return "", fmt.Errorf("error resolving alias: %w", err)
+	}
+	return doc.To, nil
+}
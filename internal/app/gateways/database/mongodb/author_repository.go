@@ -2,6 +2,7 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/literalog/library/internal/app/domain/author"
@@ -23,6 +24,9 @@ func NewAuthorRepository(collection *mongo.Collection) author.Repository {
 
 func (r *AuthorRepository) Create(ctx context.Context, a *models.Author) error {
 	_, err := r.collection.InsertOne(ctx, a)
+	if mongo.IsDuplicateKeyError(err) {
+		return author.ErrConflict
+	}
 	if err != nil {
 		return fmt.Errorf("error creating author: %w", err)
 	}
@@ -30,18 +34,32 @@ func (r *AuthorRepository) Create(ctx context.Context, a *models.Author) error {
 }
 
 func (r *AuthorRepository) Update(ctx context.Context, a *models.Author) error {
-	filter := bson.M{"_id": a.Id}
-	update := bson.M{"$set": a}
-	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+	next := *a
+	next.Meta = a.Meta.Next()
+
+	replaced, exists, err := replaceVersion(ctx, r.collection, a.Id, a.Version, &next)
+	switch {
+	case err != nil:
 		return fmt.Errorf("error updating author: %w", err)
+	case !exists:
+		return author.ErrNotFound
+	case !replaced:
+		return author.ErrVersionMismatch
 	}
+
+	a.Meta = next.Meta
 	return nil
 }
 
-func (r *AuthorRepository) Delete(ctx context.Context, id string) error {
-	filter := bson.M{"_id": id}
-	if _, err := r.collection.DeleteOne(ctx, filter); err != nil {
+func (r *AuthorRepository) Delete(ctx context.Context, id string, version int64) error {
+	deleted, exists, err := deleteVersion(ctx, r.collection, id, version)
+	switch {
+	case err != nil:
 		return fmt.Errorf("error deleting author: %w", err)
+	case !exists:
+		return author.ErrNotFound
+	case !deleted:
+		return author.ErrVersionMismatch
 	}
 	return nil
 }
@@ -49,12 +67,31 @@ func (r *AuthorRepository) Delete(ctx context.Context, id string) error {
 func (r *AuthorRepository) GetById(ctx context.Context, id string) (*models.Author, error) {
 	filter := bson.M{"_id": id}
 	a := new(models.Author)
-	if err := r.collection.FindOne(ctx, filter).Decode(a); err != nil {
+	err := r.collection.FindOne(ctx, filter).Decode(a)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, author.ErrNotFound
+	}
+	if err != nil {
 		return nil, fmt.Errorf("error getting author: %w", err)
 	}
 	return a, nil
 }
 
+func (r *AuthorRepository) GetByIds(ctx context.Context, ids []string) ([]models.Author, error) {
+	aa := make([]models.Author, 0, len(ids))
+	cur, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("error getting authors: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &aa); err != nil {
+		return nil, fmt.Errorf("error getting authors: %w", err)
+	}
+
+	return aa, nil
+}
+
 func (r *AuthorRepository) GetAll(ctx context.Context) ([]models.Author, error) {
 	aa := make([]models.Author, 0)
 	cur, err := r.collection.Find(ctx, bson.D{})
@@ -69,3 +106,28 @@ func (r *AuthorRepository) GetAll(ctx context.Context) ([]models.Author, error)
 
 	return aa, nil
 }
+
+func (r *AuthorRepository) List(ctx context.Context, q author.Query) ([]models.Author, int64, error) {
+	filter := bson.M{}
+	if q.Name != "" {
+		filter["name"] = containsFold(q.Name)
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting authors: %w", err)
+	}
+
+	aa := make([]models.Author, 0)
+	cur, err := r.collection.Find(ctx, filter, findOptions(q.ListOptions))
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting authors: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &aa); err != nil {
+		return nil, 0, fmt.Errorf("error getting authors: %w", err)
+	}
+
+	return aa, total, nil
+}
@@ -2,7 +2,9 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/literalog/library/internal/app/domain/book"
 	"github.com/literalog/library/pkg/models"
@@ -23,6 +25,12 @@ func NewBookRepository(collection *mongo.Collection) book.Repository {
 
 func (r *BookRepository) Create(ctx context.Context, b *models.Book) error {
 	_, err := r.collection.InsertOne(ctx, b)
+	if isDuplicateId(err) {
+		return book.ErrConflict
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return book.ErrDuplicateIsbn
+	}
 	if err != nil {
 		return fmt.Errorf("error creating book: %w", err)
 	}
@@ -30,18 +38,34 @@ func (r *BookRepository) Create(ctx context.Context, b *models.Book) error {
 }
 
 func (r *BookRepository) Update(ctx context.Context, b *models.Book) error {
-	filter := bson.M{"_id": b.Id}
-	update := bson.M{"$set": b}
-	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+	next := *b
+	next.Meta = b.Meta.Next()
+
+	replaced, exists, err := replaceVersion(ctx, r.collection, b.Id, b.Version, &next)
+	switch {
+	case mongo.IsDuplicateKeyError(err):
+		return book.ErrDuplicateIsbn
+	case err != nil:
 		return fmt.Errorf("error updating book: %w", err)
+	case !exists:
+		return book.ErrNotFound
+	case !replaced:
+		return book.ErrVersionMismatch
 	}
+
+	b.Meta = next.Meta
 	return nil
 }
 
-func (r *BookRepository) Delete(ctx context.Context, id string) error {
-	filter := bson.M{"_id": id}
-	if _, err := r.collection.DeleteOne(ctx, filter); err != nil {
+func (r *BookRepository) Delete(ctx context.Context, id string, version int64) error {
+	deleted, exists, err := deleteVersion(ctx, r.collection, id, version)
+	switch {
+	case err != nil:
 		return fmt.Errorf("error deleting book: %w", err)
+	case !exists:
+		return book.ErrNotFound
+	case !deleted:
+		return book.ErrVersionMismatch
 	}
 	return nil
 }
@@ -49,7 +73,24 @@ func (r *BookRepository) Delete(ctx context.Context, id string) error {
 func (r *BookRepository) GetById(ctx context.Context, id string) (*models.Book, error) {
 	filter := bson.M{"_id": id}
 	b := new(models.Book)
-	if err := r.collection.FindOne(ctx, filter).Decode(b); err != nil {
+	err := r.collection.FindOne(ctx, filter).Decode(b)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, book.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting book: %w", err)
+	}
+	return b, nil
+}
+
+func (r *BookRepository) GetByIsbn(ctx context.Context, isbn string) (*models.Book, error) {
+	filter := bson.M{"isbn": isbn}
+	b := new(models.Book)
+	err := r.collection.FindOne(ctx, filter).Decode(b)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, book.ErrNotFound
+	}
+	if err != nil {
 		return nil, fmt.Errorf("error getting book: %w", err)
 	}
 	return b, nil
@@ -69,3 +110,104 @@ func (r *BookRepository) GetAll(ctx context.Context) ([]models.Book, error) {
 
 	return bb, nil
 }
+
+func (r *BookRepository) List(ctx context.Context, q book.Query) ([]models.Book, int64, error) {
+	filter := bookFilter(q)
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting books: %w", err)
+	}
+
+	bb := make([]models.Book, 0)
+	cur, err := r.collection.Find(ctx, filter, findOptions(q.ListOptions))
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting books: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &bb); err != nil {
+		return nil, 0, fmt.Errorf("error getting books: %w", err)
+	}
+
+	return bb, total, nil
+}
+
+func bookFilter(q book.Query) bson.M {
+	filter := bson.M{}
+	if q.WorkId != "" {
+		filter["work_id"] = q.WorkId
+	}
+	if q.Isbn != "" {
+		filter["isbn"] = q.Isbn
+	}
+	if q.AuthorId != "" {
+		filter["$or"] = contributorFilter(q.AuthorId, q.Role)
+	}
+	if q.SeriesId != "" {
+		filter["series_id"] = q.SeriesId
+	}
+	if q.Genre != "" {
+		filter["genre"] = q.Genre
+	}
+	if q.Language != "" {
+		filter["language"] = q.Language
+	}
+	if q.Format != "" {
+		filter["format"] = q.Format
+	}
+
+	year := bson.M{}
+	if q.YearFrom != 0 {
+		year["$gte"] = q.YearFrom
+	}
+	if q.YearTo != 0 {
+		year["$lte"] = q.YearTo
+	}
+	if len(year) > 0 {
+		filter["year"] = year
+	}
+
+	// Each word is quoted as a phrase for the text index to require all
+	// of them rather than any.
+	if words := strings.Fields(q.Text); len(words) > 0 {
+		for i, w := range words {
+			words[i] = `"` + strings.ReplaceAll(w, `"`, ``) + `"`
+		}
+		filter["$text"] = bson.M{"$search": strings.Join(words, " ")}
+	}
+
+	return filter
+}
+
+func (r *BookRepository) GetByAuthorId(ctx context.Context, authorId string, role models.Role, opts models.ListOptions) ([]models.Book, int64, error) {
+	return r.List(ctx, book.Query{ListOptions: opts, AuthorId: authorId, Role: role})
+}
+
+func (r *BookRepository) GetBySeriesId(ctx context.Context, seriesId string, opts models.ListOptions) ([]models.Book, int64, error) {
+	return r.List(ctx, book.Query{ListOptions: opts, SeriesId: seriesId})
+}
+
+func (r *BookRepository) GetByGenre(ctx context.Context, tag string, opts models.ListOptions) ([]models.Book, int64, error) {
+	return r.List(ctx, book.Query{ListOptions: opts, Genre: tag})
+}
+
+// contributorFilter matches the books the author contributed to in the given
+// role. author_id holds the primary author, so it also matches books stored
+// before contributors existed.
+func contributorFilter(authorId string, role models.Role) bson.A {
+	contributor := bson.M{"author_id": authorId}
+	if role != "" {
+		contributor["role"] = role
+	}
+
+	or := bson.A{bson.M{"contributors": bson.M{"$elemMatch": contributor}}}
+	if role == "" || role == models.RoleAuthor {
+		or = append(or, bson.M{"author_id": authorId})
+	}
+	return or
+}
+
+func (r *BookRepository) GetByWorkId(ctx context.Context, workId string, opts models.ListOptions) ([]models.Book, int64, error) {
+	return r.List(ctx, book.Query{ListOptions: opts, WorkId: workId})
+}
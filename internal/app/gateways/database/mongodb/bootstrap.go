@@ -0,0 +1,225 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SchemaVersion is the version of the collections, validators and indexes
+// Bootstrap installs. Version 1 stores authors under their id as _id.
+const SchemaVersion = 1
+
+// schemaCollection holds a single document recording the schema version of
+// the database.
+const schemaCollection = "schema"
+
+const schemaDocId = "library"
+
+// BootstrapReport tells what Bootstrap did. DuplicateTags lists the tags
+// shared by several genres, which must be merged for the tags to be made
+// unique.
+type BootstrapReport struct {
+	SchemaVersion   int            `json:"schema_version"`
+	Previous        int            `json:"previous"`
+	AuthorsMigrated int64          `json:"authors_migrated"`
+	DuplicateTags   []DuplicateTag `json:"duplicate_tags,omitempty"`
+}
+
+// Bootstrap brings db to SchemaVersion: it migrates the documents stored by
+// earlier versions, installs the collection validators and creates the
+// indexes, then records the version. Every step is idempotent, so it runs on
+// each start. A database of a newer version than this binary is refused.
+func Bootstrap(ctx context.Context, db *mongo.Database) (*BootstrapReport, error) {
+	previous, err := schemaVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if previous > SchemaVersion {
+		return nil, fmt.Errorf("mongo schema version %d is newer than the supported %d", previous, SchemaVersion)
+	}
+
+	report := &BootstrapReport{SchemaVersion: SchemaVersion, Previous: previous}
+	if previous < 1 {
+		if report.AuthorsMigrated, err = migrateAuthorIds(ctx, db.Collection("authors")); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ensureValidators(ctx, db); err != nil {
+		return nil, err
+	}
+	if report.DuplicateTags, err = EnsureIndexes(ctx, db); err != nil {
+		return nil, err
+	}
+
+	doc := bson.M{"version": SchemaVersion, "updated_at": time.Now().UTC()}
+	_, err = db.Collection(schemaCollection).ReplaceOne(ctx, bson.M{"_id": schemaDocId}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return nil, fmt.Errorf("error recording the schema version: %w", err)
+	}
+	return report, nil
+}
+
+// schemaVersion returns the recorded schema version of db, 0 when none is.
+func schemaVersion(ctx context.Context, db *mongo.Database) (int, error) {
+	var doc struct {
+		Version int `bson:"version"`
+	}
+	err := db.Collection(schemaCollection).FindOne(ctx, bson.M{"_id": schemaDocId}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error reading the schema version: %w", err)
+	}
+	return doc.Version, nil
+}
+
+// migrateAuthorIds moves authors stored before version 1, which kept their
+// id in an id field next to a generated ObjectId, to documents whose _id is
+// their id. An author whose copy was stored by an interrupted run is only
+// removed.
+func migrateAuthorIds(ctx context.Context, authors *mongo.Collection) (int64, error) {
+	cursor, err := authors.Find(ctx, bson.M{"id": bson.M{"$type": "string"}})
+	if err != nil {
+		return 0, fmt.Errorf("error migrating authors: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var migrated int64
+	for cursor.Next(ctx) {
+		var old bson.D
+		if err := cursor.Decode(&old); err != nil {
+			return migrated, fmt.Errorf("error migrating authors: %w", err)
+		}
+
+		var oldId, id any
+		doc := bson.D{{Key: "_id"}}
+		for _, e := range old {
+			switch e.Key {
+			case "_id":
+				oldId = e.Value
+			case "id":
+				id = e.Value
+			default:
+				doc = append(doc, e)
+			}
+		}
+		doc[0].Value = id
+
+		if _, err := authors.InsertOne(ctx, doc); err != nil && !isDuplicateId(err) {
+			return migrated, fmt.Errorf("error migrating author %v: %w", id, err)
+		}
+		if _, err := authors.DeleteOne(ctx, bson.M{"_id": oldId}); err != nil {
+			return migrated, fmt.Errorf("error migrating author %v: %w", id, err)
+		}
+		migrated++
+	}
+	if err := cursor.Err(); err != nil {
+		return migrated, fmt.Errorf("error migrating authors: %w", err)
+	}
+	return migrated, nil
+}
+
+// Validation is moderate: documents already invalid when a validator is
+// installed can still be updated, but every insert and every update of a
+// valid document must keep to the schema.
+const validationLevel = "moderate"
+
+// namespaceExists is the MongoDB error code of creating a collection that
+// already exists.
+const namespaceExists = 48
+
+// ensureValidators installs the $jsonSchema validator of every collection,
+// creating the collections that are missing.
+func ensureValidators(ctx context.Context, db *mongo.Database) error {
+	for name, schema := range validators() {
+		validator := bson.M{"$jsonSchema": schema}
+		opts := options.CreateCollection().SetValidator(validator).SetValidationLevel(validationLevel)
+		err := db.CreateCollection(ctx, name, opts)
+
+		var se mongo.ServerError
+		if errors.As(err, &se) && se.HasErrorCode(namespaceExists) {
+			err = db.RunCommand(ctx, bson.D{
+				{Key: "collMod", Value: name},
+				{Key: "validator", Value: validator},
+				{Key: "validationLevel", Value: validationLevel},
+			}).Err()
+		}
+		if err != nil {
+			return fmt.Errorf("error installing the %s validator: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validators returns the $jsonSchema of each collection. Only the fields
+// the repositories rely on are checked; others are allowed.
+func validators() map[string]bson.M {
+	str := bson.M{"bsonType": "string"}
+	nonEmpty := bson.M{"bsonType": "string", "minLength": 1}
+	integer := bson.M{"bsonType": bson.A{"int", "long"}}
+	tags := bson.M{"bsonType": "array", "items": str}
+	contributors := bson.M{
+		"bsonType": "array",
+		"items": bson.M{
+			"bsonType": "object",
+			"required": bson.A{"author_id", "role"},
+			"properties": bson.M{
+				"author_id": nonEmpty,
+				"role":      str,
+				"order":     integer,
+			},
+		},
+	}
+
+	entity := func(required string, props bson.M) bson.M {
+		props["_id"] = nonEmpty
+		props["version"] = integer
+		props["created_at"] = bson.M{"bsonType": "date"}
+		props["updated_at"] = bson.M{"bsonType": "date"}
+		props[required] = nonEmpty
+		return bson.M{
+			"bsonType":   "object",
+			"required":   bson.A{"_id", required},
+			"properties": props,
+		}
+	}
+
+	return map[string]bson.M{
+		"authors": entity("name", bson.M{}),
+		"series":  entity("name", bson.M{}),
+		"genre":   entity("tag", bson.M{}),
+		"works": entity("title", bson.M{
+			"contributors": contributors,
+			"series_id":    str,
+			"series_no":    integer,
+			"genre":        tags,
+			"blurb":        str,
+		}),
+		"books": entity("title", bson.M{
+			"work_id":      str,
+			"contributors": contributors,
+			"author_id":    str,
+			"isbn":         tags,
+			"series_id":    str,
+			"series_no":    integer,
+			"year":         integer,
+			"publisher":    str,
+			"language":     str,
+			"format":       bson.M{"enum": bson.A{"Hardcover", "Paperback", "Digital", "Audio"}},
+			"pages_no":     integer,
+			"hours_no":     integer,
+			"genre":        tags,
+			"blurb":        str,
+			"cover":        str,
+			"not_a_book":   bson.M{"bsonType": "bool"},
+		}),
+	}
+}
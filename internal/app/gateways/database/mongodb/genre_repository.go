@@ -2,6 +2,7 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/literalog/library/internal/app/domain/genre"
@@ -23,6 +24,12 @@ func NewGenreRepository(collection *mongo.Collection) genre.Repository {
 
 func (r *GenreRepository) Create(ctx context.Context, g *models.Genre) error {
 	_, err := r.collection.InsertOne(ctx, g)
+	if isDuplicateId(err) {
+		return genre.ErrConflict
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return genre.ErrDuplicateTag
+	}
 	if err != nil {
 		return fmt.Errorf("error creating genre: %w", err)
 	}
@@ -30,18 +37,34 @@ func (r *GenreRepository) Create(ctx context.Context, g *models.Genre) error {
 }
 
 func (r *GenreRepository) Update(ctx context.Context, g *models.Genre) error {
-	filter := bson.M{"_id": g.Id}
-	update := bson.M{"$set": g}
-	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+	next := *g
+	next.Meta = g.Meta.Next()
+
+	replaced, exists, err := replaceVersion(ctx, r.collection, g.Id, g.Version, &next)
+	switch {
+	case mongo.IsDuplicateKeyError(err):
+		return genre.ErrDuplicateTag
+	case err != nil:
 		return fmt.Errorf("error updating genre: %w", err)
+	case !exists:
+		return genre.ErrNotFound
+	case !replaced:
+		return genre.ErrVersionMismatch
 	}
+
+	g.Meta = next.Meta
 	return nil
 }
 
-func (r *GenreRepository) Delete(ctx context.Context, id string) error {
-	filter := bson.M{"_id": id}
-	if _, err := r.collection.DeleteOne(ctx, filter); err != nil {
+func (r *GenreRepository) Delete(ctx context.Context, id string, version int64) error {
+	deleted, exists, err := deleteVersion(ctx, r.collection, id, version)
+	switch {
+	case err != nil:
 		return fmt.Errorf("error deleting genre: %w", err)
+	case !exists:
+		return genre.ErrNotFound
+	case !deleted:
+		return genre.ErrVersionMismatch
 	}
 	return nil
 }
@@ -49,21 +72,44 @@ func (r *GenreRepository) Delete(ctx context.Context, id string) error {
 func (r *GenreRepository) GetById(ctx context.Context, id string) (*models.Genre, error) {
 	filter := bson.M{"_id": id}
 	g := new(models.Genre)
-	if err := r.collection.FindOne(ctx, filter).Decode(g); err != nil {
+	err := r.collection.FindOne(ctx, filter).Decode(g)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, genre.ErrNotFound
+	}
+	if err != nil {
 		return nil, fmt.Errorf("error getting genre: %w", err)
 	}
 	return g, nil
 }
 
 func (r *GenreRepository) GetByName(ctx context.Context, name string) (*models.Genre, error) {
-	filter := bson.M{"name": name}
+	filter := bson.M{"tag": name}
 	g := new(models.Genre)
-	if err := r.collection.FindOne(ctx, filter).Decode(g); err != nil {
+	err := r.collection.FindOne(ctx, filter).Decode(g)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, genre.ErrNotFound
+	}
+	if err != nil {
 		return nil, fmt.Errorf("error getting genre: %w", err)
 	}
 	return g, nil
 }
 
+func (r *GenreRepository) GetByTags(ctx context.Context, tags []string) ([]models.Genre, error) {
+	gg := make([]models.Genre, 0, len(tags))
+	cur, err := r.collection.Find(ctx, bson.M{"tag": bson.M{"$in": tags}})
+	if err != nil {
+		return nil, fmt.Errorf("error getting genres: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &gg); err != nil {
+		return nil, fmt.Errorf("error getting genres: %w", err)
+	}
+
+	return gg, nil
+}
+
 func (r *GenreRepository) GetAll(ctx context.Context) ([]models.Genre, error) {
 	gg := make([]models.Genre, 0)
 	cur, err := r.collection.Find(ctx, bson.D{})
@@ -78,3 +124,28 @@ func (r *GenreRepository) GetAll(ctx context.Context) ([]models.Genre, error) {
 
 	return gg, nil
 }
+
+func (r *GenreRepository) List(ctx context.Context, q genre.Query) ([]models.Genre, int64, error) {
+	filter := bson.M{}
+	if q.Tag != "" {
+		filter["tag"] = containsFold(q.Tag)
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting genres: %w", err)
+	}
+
+	gg := make([]models.Genre, 0)
+	cur, err := r.collection.Find(ctx, filter, findOptions(q.ListOptions))
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting genres: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &gg); err != nil {
+		return nil, 0, fmt.Errorf("error getting genres: %w", err)
+	}
+
+	return gg, total, nil
+}
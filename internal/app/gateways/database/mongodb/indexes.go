@@ -0,0 +1,83 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DuplicateTag is a tag shared by several genres, which keeps the genre tags
+// from being made unique until the genres are merged.
+type DuplicateTag struct {
+	Tag string   `json:"tag" bson:"_id"`
+	Ids []string `json:"ids" bson:"ids"`
+}
+
+// EnsureIndexes creates the indexes backing the book lookups by ISBN, author,
+// contributor, series, genre and work, the book text search, the unique
+// genre tags and the alias lookups by target. Creating an index that already
+// exists is a no-op. While genres share a tag, the unique tag index is left
+// out and the shared tags are returned.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) ([]DuplicateTag, error) {
+	books := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "isbn", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+		{Keys: bson.D{{Key: "author_id", Value: 1}}},
+		{Keys: bson.D{{Key: "contributors.author_id", Value: 1}, {Key: "contributors.role", Value: 1}}},
+		{Keys: bson.D{{Key: "series_id", Value: 1}, {Key: "series_no", Value: 1}}},
+		{Keys: bson.D{{Key: "genre", Value: 1}}},
+		{Keys: bson.D{{Key: "work_id", Value: 1}}},
+		// Books have a language field of their own, which MongoDB would
+		// otherwise read as the stemming language of each document.
+		{
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "blurb", Value: "text"}},
+			Options: options.Index().
+				SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "blurb", Value: 1}}).
+				SetDefaultLanguage("none").
+				SetLanguageOverride("text_language"),
+		},
+	}
+	if _, err := db.Collection("books").Indexes().CreateMany(ctx, books); err != nil {
+		return nil, fmt.Errorf("error creating book indexes: %w", err)
+	}
+
+	for _, name := range []string{"author_aliases", "series_aliases", "genre_aliases"} {
+		to := mongo.IndexModel{Keys: bson.D{{Key: "to", Value: 1}}}
+		if _, err := db.Collection(name).Indexes().CreateOne(ctx, to); err != nil {
+			return nil, fmt.Errorf("error creating %s indexes: %w", name, err)
+		}
+	}
+
+	genres := db.Collection("genre")
+	dups, err := duplicateTags(ctx, genres)
+	if err != nil || len(dups) > 0 {
+		return dups, err
+	}
+	tag := mongo.IndexModel{Keys: bson.D{{Key: "tag", Value: 1}}, Options: options.Index().SetUnique(true)}
+	if _, err := genres.Indexes().CreateOne(ctx, tag); err != nil {
+		return nil, fmt.Errorf("error creating genre indexes: %w", err)
+	}
+	return nil, nil
+}
+
+// duplicateTags returns the tags shared by several genres, by tag.
+func duplicateTags(ctx context.Context, genres *mongo.Collection) ([]DuplicateTag, error) {
+	cursor, err := genres.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+		{{Key: "$group", Value: bson.M{"_id": "$tag", "ids": bson.M{"$push": "$_id"}, "n": bson.M{"$sum": 1}}}},
+		{{Key: "$match", Value: bson.M{"n": bson.M{"$gt": 1}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error looking for duplicate genre tags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	dups := []DuplicateTag{}
+	if err := cursor.All(ctx, &dups); err != nil {
+		return nil, fmt.Errorf("error looking for duplicate genre tags: %w", err)
+	}
+	return dups, nil
+}
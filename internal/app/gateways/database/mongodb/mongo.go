@@ -2,28 +2,25 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
-	"time"
+	"regexp"
 
+	"github.com/literalog/library/pkg/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 type MongoStorage struct {
 	Client *mongo.Client
 }
 
-func NewMongoStorage() (*MongoStorage, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	mongoUri := os.Getenv("MONGO_URI")
-	if mongoUri == "" {
-		mongoUri = "mongodb://localhost:27017"
-	}
-
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoUri))
+func NewMongoStorage(ctx context.Context, uri string) (*MongoStorage, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to mongo: %w", err)
 	}
@@ -37,3 +34,104 @@ func NewMongoStorage() (*MongoStorage, error) {
 		Client: client,
 	}, nil
 }
+
+// findOptions translates the pagination and ordering of a list query. Without
+// a sort, documents come in creation order like in the other backends. _id is
+// always appended as a tie breaker, as skip and limit over an undefined order
+// could repeat or skip documents between pages.
+func findOptions(opts models.ListOptions) *options.FindOptions {
+	sort := bson.D{}
+	for _, f := range opts.Sort {
+		dir := 1
+		if f.Desc {
+			dir = -1
+		}
+		sort = append(sort, bson.E{Key: f.Field, Value: dir})
+	}
+	if len(sort) == 0 {
+		sort = append(sort, bson.E{Key: "created_at", Value: 1})
+	}
+
+	return options.Find().
+		SetSort(append(sort, bson.E{Key: "_id", Value: 1})).
+		SetSkip(int64(opts.Offset)).
+		SetLimit(int64(opts.Limit))
+}
+
+func containsFold(s string) primitive.Regex {
+	return primitive.Regex{Pattern: regexp.QuoteMeta(s), Options: "i"}
+}
+
+// replaceVersion replaces the document with the given id if its version is
+// still version. When nothing is replaced, exists tells a missing document
+// from a stale version.
+func replaceVersion(ctx context.Context, collection *mongo.Collection, id string, version int64, doc any) (replaced, exists bool, err error) {
+	filter := bson.M{"_id": id, "version": version}
+	if version == 0 {
+		// Documents stored before versioning have no version field.
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	res, err := collection.ReplaceOne(ctx, filter, doc)
+	if err != nil {
+		return false, false, err
+	}
+	if res.MatchedCount > 0 {
+		return true, true, nil
+	}
+
+	n, err := collection.CountDocuments(ctx, bson.M{"_id": id}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, false, err
+	}
+	return false, n > 0, nil
+}
+
+// deleteVersion deletes the document with the given id if its version is
+// still version, or whatever its version when version is 0. When nothing is
+// deleted, exists tells a missing document from a stale version.
+func deleteVersion(ctx context.Context, collection *mongo.Collection, id string, version int64) (deleted, exists bool, err error) {
+	filter := bson.M{"_id": id}
+	if version != 0 {
+		filter["version"] = version
+	}
+
+	res, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return false, false, err
+	}
+	if res.DeletedCount > 0 {
+		return true, true, nil
+	}
+	if version == 0 {
+		return false, false, nil
+	}
+
+	n, err := collection.CountDocuments(ctx, bson.M{"_id": id}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, false, err
+	}
+	return false, n > 0, nil
+}
+
+// isDuplicateId reports whether err is a duplicate key error on _id rather
+// than on another unique index.
+func isDuplicateId(err error) bool {
+	var se mongo.ServerError
+	return errors.As(err, &se) && se.HasErrorCodeWithMessage(11000, "index: _id_ ")
+}
+
+func (s *MongoStorage) Name() string {
+	return "mongo"
+}
+
+// Ping checks that the primary is reachable.
+func (s *MongoStorage) Ping(ctx context.Context) error {
+	return s.Client.Ping(ctx, readpref.Primary())
+}
+
+// Close disconnects the client, waiting for in-use connections until ctx is
+// done.
+func (s *MongoStorage) Close(ctx context.Context) error {
+	return s.Client.Disconnect(ctx)
+}
@@ -0,0 +1,168 @@
+package mongodb_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/literalog/library/internal/app/domain/author"
+	"github.com/literalog/library/internal/app/domain/book"
+	"github.com/literalog/library/internal/app/domain/genre"
+	"github.com/literalog/library/internal/app/domain/series"
+	"github.com/literalog/library/internal/app/domain/work"
+	"github.com/literalog/library/internal/app/gateways/database/mongodb"
+	"github.com/literalog/library/internal/app/gateways/database/repotest"
+	"github.com/literalog/library/pkg/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// connect returns a client of the mongod at MONGO_URI, e.g.
+// MONGO_URI=mongodb://localhost:27017 go test ./..., skipping the test when
+// it is not set.
+func connect(t *testing.T) *mongo.Client {
+	t.Helper()
+	uri := os.Getenv("MONGO_URI")
+	if uri == "" {
+		t.Skip("MONGO_URI is not set")
+	}
+
+	ctx := context.Background()
+	storage, err := mongodb.NewMongoStorage(ctx, uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { storage.Close(ctx) })
+	return storage.Client
+}
+
+// empty returns a database of its own, dropped once the test is done.
+func empty(t *testing.T, client *mongo.Client) *mongo.Database {
+	ctx := context.Background()
+	db := client.Database("library_test_" + uuid.NewString()[:8])
+	t.Cleanup(func() { db.Drop(ctx) })
+	return db
+}
+
+// TestRepositories runs the conformance suite against bootstrapped
+// databases.
+func TestRepositories(t *testing.T) {
+	client := connect(t)
+
+	database := func(t *testing.T) *mongo.Database {
+		db := empty(t, client)
+		if _, err := mongodb.Bootstrap(context.Background(), db); err != nil {
+			t.Fatal(err)
+		}
+		return db
+	}
+
+	repotest.Run(t, repotest.Factories{
+		Authors: func(t *testing.T) author.Repository {
+			return mongodb.NewAuthorRepository(database(t).Collection("authors"))
+		},
+		Series: func(t *testing.T) series.Repository {
+			return mongodb.NewSeriesRepository(database(t).Collection("series"))
+		},
+		Genres: func(t *testing.T) genre.Repository {
+			return mongodb.NewGenreRepository(database(t).Collection("genre"))
+		},
+		Works: func(t *testing.T) work.Repository {
+			return mongodb.NewWorkRepository(database(t).Collection("works"))
+		},
+		Books: func(t *testing.T) book.Repository {
+			return mongodb.NewBookRepository(database(t).Collection("books"))
+		},
+	})
+}
+
+func TestBootstrap(t *testing.T) {
+	ctx := context.Background()
+	db := empty(t, connect(t))
+
+	// Authors stored before version 1 kept their id next to a generated _id.
+	created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	legacy := bson.M{"id": "le-guin", "version": 1, "created_at": created, "updated_at": created, "name": "Ursula K. Le Guin"}
+	if _, err := db.Collection("authors").InsertOne(ctx, legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := mongodb.Bootstrap(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*report, mongodb.BootstrapReport{SchemaVersion: mongodb.SchemaVersion, AuthorsMigrated: 1}) {
+		t.Errorf("first bootstrap: got %+v", *report)
+	}
+
+	authors := mongodb.NewAuthorRepository(db.Collection("authors"))
+	a, err := authors.GetById(ctx, "le-guin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Name != "Ursula K. Le Guin" || !a.CreatedAt.Equal(created) {
+		t.Errorf("migrated author: got %+v", a)
+	}
+
+	report, err = mongodb.Bootstrap(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Previous != mongodb.SchemaVersion || report.AuthorsMigrated != 0 {
+		t.Errorf("second bootstrap: got %+v", *report)
+	}
+
+	// The validators reject documents the repositories would not store.
+	_, err = db.Collection("authors").InsertOne(ctx, bson.M{"_id": "nameless"})
+	if err == nil {
+		t.Error("inserting an author without a name: got no error")
+	}
+
+	if _, err := db.Collection("schema").UpdateByID(ctx, "library", bson.M{"$set": bson.M{"version": mongodb.SchemaVersion + 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mongodb.Bootstrap(ctx, db); err == nil {
+		t.Error("bootstrapping a newer schema: got no error")
+	}
+}
+
+func TestBootstrapDuplicateTags(t *testing.T) {
+	ctx := context.Background()
+	db := empty(t, connect(t))
+
+	created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, id := range []string{"b", "a", "c"} {
+		g := bson.M{"_id": id, "version": 1, "created_at": created, "updated_at": created, "tag": "fantasy"}
+		if id == "c" {
+			g["tag"] = "horror"
+		}
+		if _, err := db.Collection("genre").InsertOne(ctx, g); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := mongodb.Bootstrap(ctx, db)
+	if err != nil {
+		t.Fatalf("bootstrap with a shared tag: %v", err)
+	}
+	want := []mongodb.DuplicateTag{{Tag: "fantasy", Ids: []string{"a", "b"}}}
+	if !reflect.DeepEqual(report.DuplicateTags, want) {
+		t.Errorf("duplicate tags: got %+v, want %+v", report.DuplicateTags, want)
+	}
+
+	if _, err := db.Collection("genre").DeleteOne(ctx, bson.M{"_id": "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if report, err = mongodb.Bootstrap(ctx, db); err != nil || len(report.DuplicateTags) != 0 {
+		t.Fatalf("bootstrap after the merge: got %+v, %v", report, err)
+	}
+	again := models.NewGenre(models.GenreRequest{Tag: "fantasy"})
+	if err := mongodb.NewGenreRepository(db.Collection("genre")).Create(ctx, again); !errors.Is(err, genre.ErrDuplicateTag) {
+		t.Errorf("creating a taken tag: got %v, want %v", err, genre.ErrDuplicateTag)
+	}
+}
@@ -2,6 +2,7 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/literalog/library/internal/app/domain/series"
@@ -23,6 +24,9 @@ func NewSeriesRepository(collection *mongo.Collection) series.Repository {
 
 func (r *SeriesRepository) Create(ctx context.Context, s *models.Series) error {
 	_, err := r.collection.InsertOne(ctx, s)
+	if mongo.IsDuplicateKeyError(err) {
+		return series.ErrConflict
+	}
 	if err != nil {
 		return fmt.Errorf("error creating series: %w", err)
 	}
@@ -30,18 +34,32 @@ func (r *SeriesRepository) Create(ctx context.Context, s *models.Series) error {
 }
 
 func (r *SeriesRepository) Update(ctx context.Context, s *models.Series) error {
-	filter := bson.M{"_id": s.Id}
-	update := bson.M{"$set": s}
-	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+	next := *s
+	next.Meta = s.Meta.Next()
+
+	replaced, exists, err := replaceVersion(ctx, r.collection, s.Id, s.Version, &next)
+	switch {
+	case err != nil:
 		return fmt.Errorf("error updating series: %w", err)
+	case !exists:
+		return series.ErrNotFound
+	case !replaced:
+		return series.ErrVersionMismatch
 	}
+
+	s.Meta = next.Meta
 	return nil
 }
 
-func (r *SeriesRepository) Delete(ctx context.Context, id string) error {
-	filter := bson.M{"_id": id}
-	if _, err := r.collection.DeleteOne(ctx, filter); err != nil {
+func (r *SeriesRepository) Delete(ctx context.Context, id string, version int64) error {
+	deleted, exists, err := deleteVersion(ctx, r.collection, id, version)
+	switch {
+	case err != nil:
 		return fmt.Errorf("error deleting series: %w", err)
+	case !exists:
+		return series.ErrNotFound
+	case !deleted:
+		return series.ErrVersionMismatch
 	}
 	return nil
 }
@@ -49,7 +67,11 @@ func (r *SeriesRepository) Delete(ctx context.Context, id string) error {
 func (r *SeriesRepository) GetById(ctx context.Context, id string) (*models.Series, error) {
 	filter := bson.M{"_id": id}
 	s := new(models.Series)
-	if err := r.collection.FindOne(ctx, filter).Decode(s); err != nil {
+	err := r.collection.FindOne(ctx, filter).Decode(s)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, series.ErrNotFound
+	}
+	if err != nil {
 		return nil, fmt.Errorf("error getting series: %w", err)
 	}
 	return s, nil
@@ -69,3 +91,28 @@ func (r *SeriesRepository) GetAll(ctx context.Context) ([]models.Series, error)
 
 	return ss, nil
 }
+
+func (r *SeriesRepository) List(ctx context.Context, q series.Query) ([]models.Series, int64, error) {
+	filter := bson.M{}
+	if q.Name != "" {
+		filter["name"] = containsFold(q.Name)
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting series: %w", err)
+	}
+
+	ss := make([]models.Series, 0)
+	cur, err := r.collection.Find(ctx, filter, findOptions(q.ListOptions))
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting series: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &ss); err != nil {
+		return nil, 0, fmt.Errorf("error getting series: %w", err)
+	}
+
+	return ss, total, nil
+}
@@ -0,0 +1,121 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/literalog/library/internal/app/domain/work"
+	"github.com/literalog/library/pkg/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type WorkRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWorkRepository(collection *mongo.Collection) work.Repository {
+	return &WorkRepository{
+		collection: collection,
+	}
+}
+
+func (r *WorkRepository) Create(ctx context.Context, w *models.Work) error {
+	_, err := r.collection.InsertOne(ctx, w)
+	if mongo.IsDuplicateKeyError(err) {
+		return work.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error creating work: %w", err)
+	}
+	return nil
+}
+
+func (r *WorkRepository) Update(ctx context.Context, w *models.Work) error {
+	next := *w
+	next.Meta = w.Meta.Next()
+
+	replaced, exists, err := replaceVersion(ctx, r.collection, w.Id, w.Version, &next)
+	switch {
+	case err != nil:
+		return fmt.Errorf("error updating work: %w", err)
+	case !exists:
+		return work.ErrNotFound
+	case !replaced:
+		return work.ErrVersionMismatch
+	}
+
+	w.Meta = next.Meta
+	return nil
+}
+
+func (r *WorkRepository) Delete(ctx context.Context, id string, version int64) error {
+	deleted, exists, err := deleteVersion(ctx, r.collection, id, version)
+	switch {
+	case err != nil:
+		return fmt.Errorf("error deleting work: %w", err)
+	case !exists:
+		return work.ErrNotFound
+	case !deleted:
+		return work.ErrVersionMismatch
+	}
+	return nil
+}
+
+func (r *WorkRepository) GetById(ctx context.Context, id string) (*models.Work, error) {
+	filter := bson.M{"_id": id}
+	w := new(models.Work)
+	err := r.collection.FindOne(ctx, filter).Decode(w)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, work.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting work: %w", err)
+	}
+	return w, nil
+}
+
+func (r *WorkRepository) GetAll(ctx context.Context) ([]models.Work, error) {
+	ww := make([]models.Work, 0)
+	cur, err := r.collection.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting works: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &ww); err != nil {
+		return nil, fmt.Errorf("error getting works: %w", err)
+	}
+
+	return ww, nil
+}
+
+func (r *WorkRepository) List(ctx context.Context, q work.Query) ([]models.Work, int64, error) {
+	filter := bson.M{}
+	if q.Title != "" {
+		filter["title"] = containsFold(q.Title)
+	}
+	if q.SeriesId != "" {
+		filter["series_id"] = q.SeriesId
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting works: %w", err)
+	}
+
+	ww := make([]models.Work, 0)
+	cur, err := r.collection.Find(ctx, filter, findOptions(q.ListOptions))
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting works: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &ww); err != nil {
+		return nil, 0, fmt.Errorf("error getting works: %w", err)
+	}
+
+	return ww, total, nil
+}
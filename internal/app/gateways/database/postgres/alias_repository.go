@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/literalog/library/internal/app/domain/alias"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AliasRepository struct {
+	pool  *pgxpool.Pool
+	table string
+}
+
+// NewAliasRepository keeps aliases in table, one of the *_aliases tables
+// created by the migrations.
+func NewAliasRepository(pool *pgxpool.Pool, table string) alias.Repository {
+	return &AliasRepository{
+		pool:  pool,
+		table: table,
+	}
+}
+
+func (r *AliasRepository) Redirect(ctx context.Context, id string, from []string) error {
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "UPDATE "+r.table+" SET target = $1 WHERE target = ANY($2)", id, from); err != nil {
+			return err
+		}
+		for _, a := range from {
+			_, err := tx.Exec(ctx, "INSERT INTO "+r.table+" (id, target) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET target = excluded.target", a, id)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error redirecting aliases: %w", err)
+	}
+	return nil
+}
+
+func (r *AliasRepository) Resolve(ctx context.Context, id string) (string, error) {
+	var to string
+	err := r.pool.QueryRow(ctx, "SELECT target FROM "+r.table+" WHERE id = $1", id).Scan(&to)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", alias.ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("error resolving alias: %w", err)
+	}
+	return to, nil
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/literalog/library/internal/app/domain/author"
+	"github.com/literalog/library/pkg/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const authorColumns = "id, version, created_at, updated_at, name"
+
+var authorSort = map[string]string{"name": "name"}
+
+type AuthorRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuthorRepository(pool *pgxpool.Pool) author.Repository {
+	return &AuthorRepository{
+		pool: pool,
+	}
+}
+
+func scanAuthor(row pgx.Row) (models.Author, error) {
+	var a models.Author
+	err := row.Scan(&a.Id, &a.Version, &a.CreatedAt, &a.UpdatedAt, &a.Name)
+	utc(&a.Meta)
+	return a, err
+}
+
+func (r *AuthorRepository) Create(ctx context.Context, a *models.Author) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO authors ("+authorColumns+") VALUES ($1, $2, $3, $4, $5)",
+		a.Id, a.Version, a.CreatedAt, a.UpdatedAt, a.Name)
+	if _, ok := violates(err, uniqueViolation); ok {
+		return author.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error creating author: %w", err)
+	}
+	return nil
+}
+
+func (r *AuthorRepository) Update(ctx context.Context, a *models.Author) error {
+	next := a.Meta.Next()
+	tag, err := r.pool.Exec(ctx, "UPDATE authors SET version = $3, updated_at = $4, name = $5 WHERE id = $1 AND version = $2",
+		a.Id, a.Version, next.Version, next.UpdatedAt, a.Name)
+	if err != nil {
+		return fmt.Errorf("error updating author: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return stale(ctx, r.pool, "authors", a.Id, author.ErrNotFound, author.ErrVersionMismatch)
+	}
+
+	a.Meta = next
+	return nil
+}
+
+func (r *AuthorRepository) Delete(ctx context.Context, id string, version int64) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM authors WHERE id = $1 AND ($2::bigint = 0 OR version = $2)", id, version)
+	if _, ok := violates(err, foreignKeyViolation); ok {
+		return author.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting author: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return stale(ctx, r.pool, "authors", id, author.ErrNotFound, author.ErrVersionMismatch)
+	}
+	return nil
+}
+
+func (r *AuthorRepository) GetById(ctx context.Context, id string) (*models.Author, error) {
+	a, err := scanAuthor(r.pool.QueryRow(ctx, "SELECT "+authorColumns+" FROM authors WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, author.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting author: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *AuthorRepository) GetByIds(ctx context.Context, ids []string) ([]models.Author, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+authorColumns+" FROM authors WHERE id = ANY($1)", ids)
+	if err != nil {
+		return nil, fmt.Errorf("error getting authors: %w", err)
+	}
+	aa, err := collect(rows, scanAuthor)
+	if err != nil {
+		return nil, fmt.Errorf("error getting authors: %w", err)
+	}
+	return aa, nil
+}
+
+func (r *AuthorRepository) GetAll(ctx context.Context) ([]models.Author, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+authorColumns+" FROM authors ORDER BY seq")
+	if err != nil {
+		return nil, fmt.Errorf("error getting authors: %w", err)
+	}
+	aa, err := collect(rows, scanAuthor)
+	if err != nil {
+		return nil, fmt.Errorf("error getting authors: %w", err)
+	}
+	return aa, nil
+}
+
+func (r *AuthorRepository) List(ctx context.Context, q author.Query) ([]models.Author, int64, error) {
+	w := new(where)
+	if q.Name != "" {
+		w.containsFold("name", q.Name)
+	}
+	return list(ctx, r.pool, "authors", authorColumns, w, q.ListOptions, authorSort, scanAuthor)
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/literalog/library/internal/app/domain/genre"
+	"github.com/literalog/library/pkg/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const genreColumns = "id, version, created_at, updated_at, tag"
+
+var genreSort = map[string]string{"tag": "tag"}
+
+type GenreRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewGenreRepository(pool *pgxpool.Pool) genre.Repository {
+	return &GenreRepository{
+		pool: pool,
+	}
+}
+
+func scanGenre(row pgx.Row) (models.Genre, error) {
+	var g models.Genre
+	err := row.Scan(&g.Id, &g.Version, &g.CreatedAt, &g.UpdatedAt, &g.Tag)
+	utc(&g.Meta)
+	return g, err
+}
+
+func (r *GenreRepository) Create(ctx context.Context, g *models.Genre) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO genres ("+genreColumns+") VALUES ($1, $2, $3, $4, $5)",
+		g.Id, g.Version, g.CreatedAt, g.UpdatedAt, g.Tag)
+	if constraint, ok := violates(err, uniqueViolation); ok {
+		if constraint == "genres_tag" {
+			return genre.ErrDuplicateTag
+		}
+		return genre.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error creating genre: %w", err)
+	}
+	return nil
+}
+
+func (r *GenreRepository) Update(ctx context.Context, g *models.Genre) error {
+	next := g.Meta.Next()
+	tag, err := r.pool.Exec(ctx, "UPDATE genres SET version = $3, updated_at = $4, tag = $5 WHERE id = $1 AND version = $2",
+		g.Id, g.Version, next.Version, next.UpdatedAt, g.Tag)
+	if _, ok := violates(err, uniqueViolation); ok {
+		return genre.ErrDuplicateTag
+	}
+	if err != nil {
+		return fmt.Errorf("error updating genre: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return stale(ctx, r.pool, "genres", g.Id, genre.ErrNotFound, genre.ErrVersionMismatch)
+	}
+
+	g.Meta = next
+	return nil
+}
+
+func (r *GenreRepository) Delete(ctx context.Context, id string, version int64) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM genres WHERE id = $1 AND ($2::bigint = 0 OR version = $2)", id, version)
+	if _, ok := violates(err, foreignKeyViolation); ok {
+		return genre.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting genre: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return stale(ctx, r.pool, "genres", id, genre.ErrNotFound, genre.ErrVersionMismatch)
+	}
+	return nil
+}
+
+func (r *GenreRepository) GetById(ctx context.Context, id string) (*models.Genre, error) {
+	g, err := scanGenre(r.pool.QueryRow(ctx, "SELECT "+genreColumns+" FROM genres WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, genre.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting genre: %w", err)
+	}
+	return &g, nil
+}
+
+func (r *GenreRepository) GetByName(ctx context.Context, name string) (*models.Genre, error) {
+	g, err := scanGenre(r.pool.QueryRow(ctx, "SELECT "+genreColumns+" FROM genres WHERE tag = $1 ORDER BY seq LIMIT 1", name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, genre.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting genre: %w", err)
+	}
+	return &g, nil
+}
+
+func (r *GenreRepository) GetByTags(ctx context.Context, tags []string) ([]models.Genre, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+genreColumns+" FROM genres WHERE tag = ANY($1) ORDER BY seq", tags)
+	if err != nil {
+		return nil, fmt.Errorf("error getting genres: %w", err)
+	}
+	gg, err := collect(rows, scanGenre)
+	if err != nil {
+		return nil, fmt.Errorf("error getting genres: %w", err)
+	}
+	return gg, nil
+}
+
+func (r *GenreRepository) GetAll(ctx context.Context) ([]models.Genre, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+genreColumns+" FROM genres ORDER BY seq")
+	if err != nil {
+		return nil, fmt.Errorf("error getting genres: %w", err)
+	}
+	gg, err := collect(rows, scanGenre)
+	if err != nil {
+		return nil, fmt.Errorf("error getting genres: %w", err)
+	}
+	return gg, nil
+}
+
+func (r *GenreRepository) List(ctx context.Context, q genre.Query) ([]models.Genre, int64, error) {
+	w := new(where)
+	if q.Tag != "" {
+		w.containsFold("tag", q.Tag)
+	}
+	return list(ctx, r.pool, "genres", genreColumns, w, q.ListOptions, genreSort, scanGenre)
+}
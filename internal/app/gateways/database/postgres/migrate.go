@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// The migrations are named <version>_<name>.up.sql and
+// <version>_<name>.down.sql, and applied in version order.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLock keeps concurrent migrators, such as two instances deployed
+// at once, from applying the same migration.
+const migrationLock = 7_451_220_231
+
+// Migration is a numbered change of the schema, with the SQL applying and
+// reverting it.
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+// MigrationStatus tells whether a migration was applied, and when.
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// Migrations returns the embedded migrations in version order.
+func Migrations() ([]Migration, error) {
+	return readMigrations(migrationFiles, "migrations")
+}
+
+func readMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		base, direction, ok := strings.Cut(strings.TrimSuffix(e.Name(), ".sql"), ".")
+		prefix, name, found := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || !found || err != nil || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name %s", e.Name())
+		}
+
+		b, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.up = string(b)
+		} else {
+			m.down = string(b)
+		}
+	}
+
+	mm := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both an up and a down file", m.Version, m.Name)
+		}
+		mm = append(mm, *m)
+	}
+	sort.Slice(mm, func(i, j int) bool { return mm[i].Version < mm[j].Version })
+	return mm, nil
+}
+
+// Migrator applies and reverts the embedded migrations, recording the
+// applied ones in the schema_migrations table.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	mm, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		pool:       pool,
+		migrations: mm,
+	}, nil
+}
+
+func (m *Migrator) init(ctx context.Context) error {
+	_, err := m.pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    integer PRIMARY KEY,
+		name       text NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// applied returns when each applied migration was applied, by version.
+func applied(ctx context.Context, q querier) (map[int]time.Time, error) {
+	rows, err := q.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[int]time.Time)
+	for rows.Next() {
+		var (
+			version int
+			at      time.Time
+		)
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+		}
+		versions[version] = at.UTC()
+	}
+	return versions, rows.Err()
+}
+
+// Status lists every migration, telling which are applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.init(ctx); err != nil {
+		return nil, err
+	}
+	versions, err := applied(ctx, m.pool)
+	if err != nil {
+		return nil, err
+	}
+
+	ss := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if at, ok := versions[mig.Version]; ok {
+			s.AppliedAt = &at
+		}
+		ss = append(ss, s)
+	}
+	return ss, nil
+}
+
+// Pending returns the migrations not applied yet.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	ss, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for i, s := range ss {
+		if s.AppliedAt == nil {
+			pending = append(pending, m.migrations[i])
+		}
+	}
+	return pending, nil
+}
+
+// Up applies the pending migrations in order, each in a transaction, and
+// returns them.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if err := m.init(ctx); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, mig := range m.migrations {
+		ran := false
+		err := m.locked(ctx, func(tx pgx.Tx, versions map[int]time.Time) error {
+			if _, ok := versions[mig.Version]; ok {
+				return nil
+			}
+			if _, err := tx.Exec(ctx, mig.up); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", mig.Version, mig.Name)
+			ran = err == nil
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("error applying migration %d_%s: %w", mig.Version, mig.Name, err)
+		}
+		if ran {
+			done = append(done, mig)
+		}
+	}
+	return done, nil
+}
+
+// Down reverts the last applied migration and returns it, or nil when none
+// is applied.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	if err := m.init(ctx); err != nil {
+		return nil, err
+	}
+
+	var reverted *Migration
+	err := m.locked(ctx, func(tx pgx.Tx, versions map[int]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := versions[mig.Version]; !ok {
+				continue
+			}
+			if _, err := tx.Exec(ctx, mig.down); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", mig.Version); err != nil {
+				return err
+			}
+			reverted = &mig
+			return nil
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reverting migration: %w", err)
+	}
+	return reverted, nil
+}
+
+// locked runs fn in a transaction holding the migration lock, with the
+// migrations applied by then.
+func (m *Migrator) locked(ctx context.Context, fn func(tx pgx.Tx, versions map[int]time.Time) error) error {
+	return pgx.BeginFunc(ctx, m.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLock); err != nil {
+			return err
+		}
+		versions, err := applied(ctx, tx)
+		if err != nil {
+			return err
+		}
+		return fn(tx, versions)
+	})
+}
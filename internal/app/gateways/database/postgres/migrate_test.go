@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadMigrations(t *testing.T) {
+	file := func(s string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(s)} }
+
+	mm, err := readMigrations(fstest.MapFS{
+		"m/0002_add_covers.up.sql":     file("up 2"),
+		"m/0002_add_covers.down.sql":   file("down 2"),
+		"m/0010_add_index.up.sql":      file("up 10"),
+		"m/0010_add_index.down.sql":    file("down 10"),
+		"m/0001_create_books.up.sql":   file("up 1"),
+		"m/0001_create_books.down.sql": file("down 1"),
+	}, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Migration{
+		{Version: 1, Name: "create_books", up: "up 1", down: "down 1"},
+		{Version: 2, Name: "add_covers", up: "up 2", down: "down 2"},
+		{Version: 10, Name: "add_index", up: "up 10", down: "down 10"},
+	}
+	if len(mm) != len(want) {
+		t.Fatalf("got %d migrations, want %d", len(mm), len(want))
+	}
+	for i := range want {
+		if mm[i] != want[i] {
+			t.Errorf("migration %d: got %+v, want %+v", i, mm[i], want[i])
+		}
+	}
+
+	invalid := map[string]fstest.MapFS{
+		"no version":   {"m/create.up.sql": file("up"), "m/create.down.sql": file("down")},
+		"no direction": {"m/0001_create.sql": file("up")},
+		"no down":      {"m/0001_create.up.sql": file("up")},
+		"renamed":      {"m/0001_create.up.sql": file("up"), "m/0001_init.down.sql": file("down")},
+	}
+	for name, fsys := range invalid {
+		if _, err := readMigrations(fsys, "m"); err == nil {
+			t.Errorf("%s: got no error", name)
+		}
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	mm, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range mm {
+		if m.Version != i+1 {
+			t.Errorf("migration %d_%s should be numbered %d", m.Version, m.Name, i+1)
+		}
+	}
+}
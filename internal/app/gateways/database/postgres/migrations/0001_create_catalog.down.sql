@@ -0,0 +1,13 @@
+DROP TABLE genre_aliases;
+DROP TABLE series_aliases;
+DROP TABLE author_aliases;
+DROP TABLE book_genres;
+DROP TABLE book_isbns;
+DROP TABLE book_contributors;
+DROP TABLE books;
+DROP TABLE work_genres;
+DROP TABLE work_contributors;
+DROP TABLE works;
+DROP TABLE genres;
+DROP TABLE series;
+DROP TABLE authors;
@@ -0,0 +1,135 @@
+CREATE TABLE authors (
+    id         text PRIMARY KEY,
+    seq        bigint GENERATED ALWAYS AS IDENTITY,
+    version    bigint NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    name       text NOT NULL
+);
+
+CREATE TABLE series (
+    id         text PRIMARY KEY,
+    seq        bigint GENERATED ALWAYS AS IDENTITY,
+    version    bigint NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    name       text NOT NULL
+);
+
+CREATE TABLE genres (
+    id         text PRIMARY KEY,
+    seq        bigint GENERATED ALWAYS AS IDENTITY,
+    version    bigint NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    tag        text NOT NULL
+);
+
+CREATE UNIQUE INDEX genres_tag ON genres (tag);
+
+CREATE TABLE works (
+    id         text PRIMARY KEY,
+    seq        bigint GENERATED ALWAYS AS IDENTITY,
+    version    bigint NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    title      text NOT NULL,
+    series_id  text,
+    series_no  integer NOT NULL DEFAULT 0,
+    blurb      text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX works_series ON works (series_id);
+
+CREATE TABLE work_contributors (
+    work_id   text NOT NULL REFERENCES works (id) ON DELETE CASCADE,
+    position  integer NOT NULL,
+    author_id text NOT NULL,
+    role      text NOT NULL,
+    ord       integer NOT NULL,
+    PRIMARY KEY (work_id, position)
+);
+
+CREATE TABLE work_genres (
+    work_id  text NOT NULL REFERENCES works (id) ON DELETE CASCADE,
+    position integer NOT NULL,
+    tag      text NOT NULL,
+    PRIMARY KEY (work_id, position)
+);
+
+CREATE TABLE books (
+    id         text PRIMARY KEY,
+    seq        bigint GENERATED ALWAYS AS IDENTITY,
+    version    bigint NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    work_id    text,
+    title      text NOT NULL,
+    author_id  text CONSTRAINT books_author_fk REFERENCES authors (id),
+    series_id  text CONSTRAINT books_series_fk REFERENCES series (id),
+    series_no  integer NOT NULL DEFAULT 0,
+    year       integer NOT NULL DEFAULT 0,
+    publisher  text NOT NULL DEFAULT '',
+    language   text NOT NULL DEFAULT '',
+    format     text NOT NULL DEFAULT '',
+    pages_no   integer NOT NULL DEFAULT 0,
+    hours_no   integer NOT NULL DEFAULT 0,
+    blurb      text NOT NULL DEFAULT '',
+    cover      text NOT NULL DEFAULT '',
+    not_a_book boolean NOT NULL DEFAULT false,
+    search     tsvector GENERATED ALWAYS AS (to_tsvector('simple', title || ' ' || blurb)) STORED
+);
+
+CREATE INDEX books_author ON books (author_id);
+CREATE INDEX books_series ON books (series_id, series_no);
+CREATE INDEX books_work ON books (work_id);
+CREATE INDEX books_search ON books USING gin (search);
+
+CREATE TABLE book_contributors (
+    book_id   text NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    position  integer NOT NULL,
+    author_id text NOT NULL CONSTRAINT book_contributors_author_fk REFERENCES authors (id),
+    role      text NOT NULL,
+    ord       integer NOT NULL,
+    PRIMARY KEY (book_id, position)
+);
+
+CREATE INDEX book_contributors_author ON book_contributors (author_id, role);
+
+CREATE TABLE book_isbns (
+    isbn     text CONSTRAINT book_isbns_pkey PRIMARY KEY,
+    book_id  text NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    position integer NOT NULL
+);
+
+CREATE INDEX book_isbns_book ON book_isbns (book_id);
+
+CREATE TABLE book_genres (
+    book_id  text NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    position integer NOT NULL,
+    tag      text NOT NULL,
+    PRIMARY KEY (book_id, position)
+);
+
+CREATE INDEX book_genres_tag ON book_genres (tag);
+
+CREATE TABLE author_aliases (
+    id     text PRIMARY KEY,
+    target text NOT NULL
+);
+
+CREATE INDEX author_aliases_target ON author_aliases (target);
+
+CREATE TABLE series_aliases (
+    id     text PRIMARY KEY,
+    target text NOT NULL
+);
+
+CREATE INDEX series_aliases_target ON series_aliases (target);
+
+CREATE TABLE genre_aliases (
+    id     text PRIMARY KEY,
+    target text NOT NULL
+);
+
+CREATE INDEX genre_aliases_target ON genre_aliases (target);
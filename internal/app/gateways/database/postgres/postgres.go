@@ -0,0 +1,207 @@
+// Package postgres stores the catalog in PostgreSQL. The schema is created
+// and upgraded by the migrations embedded in the package.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/literalog/library/pkg/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Storage struct {
+	Pool *pgxpool.Pool
+}
+
+func NewStorage(ctx context.Context, url string) (*Storage, error) {
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return &Storage{
+		Pool: pool,
+	}, nil
+}
+
+func (s *Storage) Name() string {
+	return "postgres"
+}
+
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.Pool.Ping(ctx)
+}
+
+// Close closes the pool, waiting for in-use connections until ctx is done.
+func (s *Storage) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.Pool.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// querier runs statements on the pool or within a transaction.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// violates reports whether err is a violation of the given class, and if
+// so which constraint was violated.
+func violates(err error, code string) (constraint string, ok bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == code {
+		return pgErr.ConstraintName, true
+	}
+	return "", false
+}
+
+// nullable stores empty optional references as NULL, which foreign keys
+// accept.
+func nullable(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// utc puts the times read from timestamptz columns back in UTC, as they
+// were written.
+func utc(m *models.Meta) {
+	m.CreatedAt = m.CreatedAt.UTC()
+	m.UpdatedAt = m.UpdatedAt.UTC()
+}
+
+// collect scans every row with scan.
+func collect[T any](rows pgx.Rows, scan func(row pgx.Row) (T, error)) ([]T, error) {
+	defer rows.Close()
+
+	items := make([]T, 0)
+	for rows.Next() {
+		v, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	return items, rows.Err()
+}
+
+// errStale aborts the transaction of an update that changed nothing, to be
+// told apart by stale once rolled back.
+var errStale = errors.New("stale update")
+
+// stale tells why an update or a delete of the row with the given id in
+// table changed nothing: it returns notFound when the row is missing and
+// versionMismatch when its version changed.
+func stale(ctx context.Context, q querier, table, id string, notFound, versionMismatch error) error {
+	var exists bool
+	err := q.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = $1)", id).Scan(&exists)
+	switch {
+	case err != nil:
+		return err
+	case !exists:
+		return notFound
+	default:
+		return versionMismatch
+	}
+}
+
+// where builds the WHERE clause of a list query along with its arguments.
+type where struct {
+	conds []string
+	args  []any
+}
+
+// arg adds an argument and returns its placeholder.
+func (w *where) arg(v any) string {
+	w.args = append(w.args, v)
+	return fmt.Sprintf("$%d", len(w.args))
+}
+
+func (w *where) and(cond string) {
+	w.conds = append(w.conds, cond)
+}
+
+// containsFold matches column against s anywhere, ignoring case.
+func (w *where) containsFold(column, s string) {
+	pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+	w.and(column + " ILIKE " + w.arg(pattern))
+}
+
+func (w *where) String() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conds, " AND ")
+}
+
+// page returns the ORDER BY, LIMIT and OFFSET clauses of a list query,
+// adding the limit and offset to w. Only the fields in columns can be
+// sorted by; seq is appended as a tie breaker so pages are stable and
+// equal items keep their insertion order.
+func (w *where) page(opts models.ListOptions, columns map[string]string) string {
+	var order []string
+	for _, f := range opts.Sort {
+		column, ok := columns[f.Field]
+		if !ok {
+			continue
+		}
+		if f.Desc {
+			column += " DESC"
+		}
+		order = append(order, column)
+	}
+	clause := " ORDER BY " + strings.Join(append(order, "seq"), ", ")
+
+	if opts.Limit > 0 {
+		clause += " LIMIT " + w.arg(opts.Limit)
+	}
+	return clause + " OFFSET " + w.arg(opts.Offset)
+}
+
+// list counts the rows of table matching w, then returns the requested page
+// of them.
+func list[T any](ctx context.Context, q querier, table, columns string, w *where, opts models.ListOptions, sortable map[string]string, scan func(row pgx.Row) (T, error)) ([]T, int64, error) {
+	var total int64
+	if err := q.QueryRow(ctx, "SELECT count(*) FROM "+table+w.String(), w.args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error counting %s: %w", table, err)
+	}
+
+	sql := "SELECT " + columns + " FROM " + table + w.String()
+	sql += w.page(opts, sortable)
+	rows, err := q.Query(ctx, sql, w.args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting %s: %w", table, err)
+	}
+	items, err := collect(rows, scan)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting %s: %w", table, err)
+	}
+	return items, total, nil
+}
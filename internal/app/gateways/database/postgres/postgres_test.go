@@ -0,0 +1,268 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/literalog/library/internal/app/domain/author"
+	"github.com/literalog/library/internal/app/domain/book"
+	"github.com/literalog/library/internal/app/domain/genre"
+	"github.com/literalog/library/internal/app/domain/series"
+	"github.com/literalog/library/internal/app/domain/work"
+	"github.com/literalog/library/internal/app/gateways/database/postgres"
+	"github.com/literalog/library/internal/app/gateways/database/repotest"
+	"github.com/literalog/library/pkg/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// url is the server the tests run against: POSTGRES_URL when set, or else
+// a throwaway cluster started by TestMain when initdb and pg_ctl are on
+// the PATH. The tests are skipped when it is empty.
+var url string
+
+func TestMain(m *testing.M) {
+	url = os.Getenv("POSTGRES_URL")
+	if url != "" {
+		os.Exit(m.Run())
+	}
+
+	dir, err := startCluster()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "postgres tests skipped:", err)
+		os.Exit(m.Run())
+	}
+	url = "postgres://postgres@/postgres?host=" + dir
+
+	code := m.Run()
+	exec.Command("pg_ctl", "-D", filepath.Join(dir, "data"), "-m", "immediate", "stop").Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// startCluster initializes a cluster in a temporary directory and starts it
+// listening only on a socket in that directory, so it cannot clash with a
+// server already running.
+func startCluster() (string, error) {
+	for _, bin := range []string{"initdb", "pg_ctl"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			return "", err
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "library-postgres-")
+	if err != nil {
+		return "", err
+	}
+	data := filepath.Join(dir, "data")
+
+	cmds := [][]string{
+		{"initdb", "-D", data, "-U", "postgres", "-A", "trust", "--no-sync"},
+		{"pg_ctl", "-D", data, "-l", filepath.Join(dir, "log"), "-w", "start",
+			"-o", "-c listen_addresses='' -k " + dir + " -c fsync=off"},
+	}
+	for _, args := range cmds {
+		if out, err := exec.Command(args[0], args[1:]...).CombinedOutput(); err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("%s: %w\n%s", args[0], err, out)
+		}
+	}
+	return dir, nil
+}
+
+// schema returns a pool confined to a fresh, migrated schema, dropped once
+// the test is done.
+func schema(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	if url == "" {
+		t.Skip("no postgres server, set POSTGRES_URL or put initdb on the PATH")
+	}
+	ctx := context.Background()
+
+	admin, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(admin.Close)
+
+	name := "library_test_" + uuid.NewString()[:8]
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+name); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Exec(ctx, "DROP SCHEMA "+name+" CASCADE") })
+
+	cfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = name
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+
+	m, err := postgres.NewMigrator(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+// seedingBooks stores the authors and series a book refers to before
+// writing it, as the suite writes books on their own and the foreign keys
+// would reject them.
+type seedingBooks struct {
+	book.Repository
+	pool *pgxpool.Pool
+}
+
+func (r seedingBooks) seed(ctx context.Context, b *models.Book) error {
+	meta := models.NewMeta()
+	authors := []string{b.AuthorId}
+	for _, c := range b.Contributors {
+		authors = append(authors, c.AuthorId)
+	}
+	for _, id := range authors {
+		if id == "" {
+			continue
+		}
+		_, err := r.pool.Exec(ctx, "INSERT INTO authors (id, version, created_at, updated_at, name) VALUES ($1, $2, $3, $3, $1) ON CONFLICT DO NOTHING",
+			id, meta.Version, meta.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	if b.SeriesId != "" {
+		_, err := r.pool.Exec(ctx, "INSERT INTO series (id, version, created_at, updated_at, name) VALUES ($1, $2, $3, $3, $1) ON CONFLICT DO NOTHING",
+			b.SeriesId, meta.Version, meta.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r seedingBooks) Create(ctx context.Context, b *models.Book) error {
+	if err := r.seed(ctx, b); err != nil {
+		return err
+	}
+	return r.Repository.Create(ctx, b)
+}
+
+func (r seedingBooks) Update(ctx context.Context, b *models.Book) error {
+	if err := r.seed(ctx, b); err != nil {
+		return err
+	}
+	return r.Repository.Update(ctx, b)
+}
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, repotest.Factories{
+		Authors: func(t *testing.T) author.Repository { return postgres.NewAuthorRepository(schema(t)) },
+		Series:  func(t *testing.T) series.Repository { return postgres.NewSeriesRepository(schema(t)) },
+		Genres:  func(t *testing.T) genre.Repository { return postgres.NewGenreRepository(schema(t)) },
+		Works:   func(t *testing.T) work.Repository { return postgres.NewWorkRepository(schema(t)) },
+		Books: func(t *testing.T) book.Repository {
+			pool := schema(t)
+			return seedingBooks{Repository: postgres.NewBookRepository(pool), pool: pool}
+		},
+	})
+}
+
+func TestBookReferences(t *testing.T) {
+	ctx := context.Background()
+	pool := schema(t)
+	authors := postgres.NewAuthorRepository(pool)
+	books := postgres.NewBookRepository(pool)
+
+	b := &models.Book{Id: uuid.NewString(), Meta: models.NewMeta(), Title: "A Wizard of Earthsea", AuthorId: "le-guin"}
+	if err := books.Create(ctx, b); !errors.Is(err, book.ErrAuthorNotFound) {
+		t.Fatalf("creating a book of a missing author: got %v, want %v", err, book.ErrAuthorNotFound)
+	}
+
+	a := &models.Author{Id: "le-guin", Meta: models.NewMeta(), Name: "Ursula K. Le Guin"}
+	if err := authors.Create(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if err := books.Create(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	b.SeriesId = "earthsea"
+	if err := books.Update(ctx, b); !errors.Is(err, book.ErrSeriesNotFound) {
+		t.Fatalf("updating a book to a missing series: got %v, want %v", err, book.ErrSeriesNotFound)
+	}
+
+	if err := authors.Delete(ctx, a.Id, 0); !errors.Is(err, author.ErrConflict) {
+		t.Fatalf("deleting an author of a book: got %v, want %v", err, author.ErrConflict)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	pool := schema(t)
+	m, err := postgres.NewMigrator(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := postgres.Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := all[len(all)-1]
+
+	if done, err := m.Up(ctx); err != nil || len(done) != 0 {
+		t.Fatalf("Up on a migrated schema: got %v, %v, want nothing applied", done, err)
+	}
+
+	reverted, err := m.Down(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reverted == nil || reverted.Version != latest.Version {
+		t.Fatalf("Down reverted %v, want %d", reverted, latest.Version)
+	}
+
+	ss, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := ss[len(ss)-1]; s.AppliedAt != nil {
+		t.Fatalf("%d_%s is still applied after Down", s.Version, s.Name)
+	}
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Version != latest.Version {
+		t.Fatalf("got pending %v, want %d", pending, latest.Version)
+	}
+
+	for range all[:len(all)-1] {
+		if _, err := m.Down(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if reverted, err := m.Down(ctx); err != nil || reverted != nil {
+		t.Fatalf("Down with nothing applied: got %v, %v, want nil", reverted, err)
+	}
+
+	done, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != len(all) {
+		t.Fatalf("Up applied %d migrations, want %d", len(done), len(all))
+	}
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/literalog/library/internal/app/domain/series"
+	"github.com/literalog/library/pkg/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const seriesColumns = "id, version, created_at, updated_at, name"
+
+var seriesSort = map[string]string{"name": "name"}
+
+type SeriesRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSeriesRepository(pool *pgxpool.Pool) series.Repository {
+	return &SeriesRepository{
+		pool: pool,
+	}
+}
+
+func scanSeries(row pgx.Row) (models.Series, error) {
+	var s models.Series
+	err := row.Scan(&s.Id, &s.Version, &s.CreatedAt, &s.UpdatedAt, &s.Name)
+	utc(&s.Meta)
+	return s, err
+}
+
+func (r *SeriesRepository) Create(ctx context.Context, s *models.Series) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO series ("+seriesColumns+") VALUES ($1, $2, $3, $4, $5)",
+		s.Id, s.Version, s.CreatedAt, s.UpdatedAt, s.Name)
+	if _, ok := violates(err, uniqueViolation); ok {
+		return series.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error creating series: %w", err)
+	}
+	return nil
+}
+
+func (r *SeriesRepository) Update(ctx context.Context, s *models.Series) error {
+	next := s.Meta.Next()
+	tag, err := r.pool.Exec(ctx, "UPDATE series SET version = $3, updated_at = $4, name = $5 WHERE id = $1 AND version = $2",
+		s.Id, s.Version, next.Version, next.UpdatedAt, s.Name)
+	if err != nil {
+		return fmt.Errorf("error updating series: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return stale(ctx, r.pool, "series", s.Id, series.ErrNotFound, series.ErrVersionMismatch)
+	}
+
+	s.Meta = next
+	return nil
+}
+
+func (r *SeriesRepository) Delete(ctx context.Context, id string, version int64) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM series WHERE id = $1 AND ($2::bigint = 0 OR version = $2)", id, version)
+	if _, ok := violates(err, foreignKeyViolation); ok {
+		return series.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting series: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return stale(ctx, r.pool, "series", id, series.ErrNotFound, series.ErrVersionMismatch)
+	}
+	return nil
+}
+
+func (r *SeriesRepository) GetById(ctx context.Context, id string) (*models.Series, error) {
+	s, err := scanSeries(r.pool.QueryRow(ctx, "SELECT "+seriesColumns+" FROM series WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, series.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting series: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *SeriesRepository) GetAll(ctx context.Context) ([]models.Series, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+seriesColumns+" FROM series ORDER BY seq")
+	if err != nil {
+		return nil, fmt.Errorf("error getting series: %w", err)
+	}
+	ss, err := collect(rows, scanSeries)
+	if err != nil {
+		return nil, fmt.Errorf("error getting series: %w", err)
+	}
+	return ss, nil
+}
+
+func (r *SeriesRepository) List(ctx context.Context, q series.Query) ([]models.Series, int64, error) {
+	w := new(where)
+	if q.Name != "" {
+		w.containsFold("name", q.Name)
+	}
+	return list(ctx, r.pool, "series", seriesColumns, w, q.ListOptions, seriesSort, scanSeries)
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/literalog/library/internal/app/domain/work"
+	"github.com/literalog/library/pkg/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const workColumns = "id, version, created_at, updated_at, title, coalesce(series_id, ''), series_no, blurb"
+
+var workSort = map[string]string{"title": "title", "series_no": "series_no"}
+
+type WorkRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWorkRepository(pool *pgxpool.Pool) work.Repository {
+	return &WorkRepository{
+		pool: pool,
+	}
+}
+
+func scanWork(row pgx.Row) (models.Work, error) {
+	var w models.Work
+	err := row.Scan(&w.Id, &w.Version, &w.CreatedAt, &w.UpdatedAt, &w.Title, &w.SeriesId, &w.SeriesNo, &w.Blurb)
+	utc(&w.Meta)
+	return w, err
+}
+
+// attach reads the contributors and genres of ww.
+func (r *WorkRepository) attach(ctx context.Context, ww []models.Work) error {
+	ids := make([]string, 0, len(ww))
+	for _, w := range ww {
+		ids = append(ids, w.Id)
+	}
+
+	contributors, err := readContributors(ctx, r.pool, "work", ids)
+	if err != nil {
+		return err
+	}
+	genres, err := readValues(ctx, r.pool, "work_genres", "work", "tag", ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range ww {
+		ww[i].Contributors = contributors[ww[i].Id]
+		ww[i].Genre = genres[ww[i].Id]
+	}
+	return nil
+}
+
+// query returns the works selected by sql, with their contributors and
+// genres.
+func (r *WorkRepository) query(ctx context.Context, sql string, args ...any) ([]models.Work, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting works: %w", err)
+	}
+	ww, err := collect(rows, scanWork)
+	if err != nil {
+		return nil, fmt.Errorf("error getting works: %w", err)
+	}
+	if err := r.attach(ctx, ww); err != nil {
+		return nil, fmt.Errorf("error getting works: %w", err)
+	}
+	return ww, nil
+}
+
+func (r *WorkRepository) Create(ctx context.Context, w *models.Work) error {
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "INSERT INTO works (id, version, created_at, updated_at, title, series_id, series_no, blurb) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+			w.Id, w.Version, w.CreatedAt, w.UpdatedAt, w.Title, nullable(w.SeriesId), w.SeriesNo, w.Blurb)
+		if err != nil {
+			return err
+		}
+		if err := writeContributors(ctx, tx, "work", w.Id, w.Contributors); err != nil {
+			return err
+		}
+		return writeGenres(ctx, tx, "work", w.Id, w.Genre)
+	})
+	if _, ok := violates(err, uniqueViolation); ok {
+		return work.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error creating work: %w", err)
+	}
+	return nil
+}
+
+func (r *WorkRepository) Update(ctx context.Context, w *models.Work) error {
+	next := w.Meta.Next()
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, "UPDATE works SET version = $3, updated_at = $4, title = $5, series_id = $6, series_no = $7, blurb = $8 WHERE id = $1 AND version = $2",
+			w.Id, w.Version, next.Version, next.UpdatedAt, w.Title, nullable(w.SeriesId), w.SeriesNo, w.Blurb)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return errStale
+		}
+		if err := writeContributors(ctx, tx, "work", w.Id, w.Contributors); err != nil {
+			return err
+		}
+		return writeGenres(ctx, tx, "work", w.Id, w.Genre)
+	})
+	if errors.Is(err, errStale) {
+		return stale(ctx, r.pool, "works", w.Id, work.ErrNotFound, work.ErrVersionMismatch)
+	}
+	if err != nil {
+		return fmt.Errorf("error updating work: %w", err)
+	}
+
+	w.Meta = next
+	return nil
+}
+
+func (r *WorkRepository) Delete(ctx context.Context, id string, version int64) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM works WHERE id = $1 AND ($2::bigint = 0 OR version = $2)", id, version)
+	if err != nil {
+		return fmt.Errorf("error deleting work: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return stale(ctx, r.pool, "works", id, work.ErrNotFound, work.ErrVersionMismatch)
+	}
+	return nil
+}
+
+func (r *WorkRepository) GetById(ctx context.Context, id string) (*models.Work, error) {
+	ww, err := r.query(ctx, "SELECT "+workColumns+" FROM works WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(ww) == 0 {
+		return nil, work.ErrNotFound
+	}
+	return &ww[0], nil
+}
+
+func (r *WorkRepository) GetAll(ctx context.Context) ([]models.Work, error) {
+	return r.query(ctx, "SELECT "+workColumns+" FROM works ORDER BY seq")
+}
+
+func (r *WorkRepository) List(ctx context.Context, q work.Query) ([]models.Work, int64, error) {
+	w := new(where)
+	if q.Title != "" {
+		w.containsFold("title", q.Title)
+	}
+	if q.SeriesId != "" {
+		w.and("series_id = " + w.arg(q.SeriesId))
+	}
+
+	ww, total, err := list(ctx, r.pool, "works", workColumns, w, q.ListOptions, workSort, scanWork)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := r.attach(ctx, ww); err != nil {
+		return nil, 0, fmt.Errorf("error getting works: %w", err)
+	}
+	return ww, total, nil
+}
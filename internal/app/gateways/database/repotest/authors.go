@@ -0,0 +1,70 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/author"
+	"github.com/literalog/library/pkg/models"
+)
+
+func newAuthor(i int) *models.Author {
+	return models.NewAuthor(models.AuthorRequest{Name: fmt.Sprintf("Author %04d", i)})
+}
+
+func testAuthors(t *testing.T, newRepo func(t *testing.T) author.Repository) {
+	testEntity(t, func(t *testing.T) entity[models.Author] {
+		repo := newRepo(t)
+		return entity[models.Author]{
+			repo:   repo,
+			new:    newAuthor,
+			id:     func(a *models.Author) string { return a.Id },
+			name:   func(a *models.Author) string { return a.Name },
+			rename: func(a *models.Author, name string) { a.Name = name },
+			sort:   "name",
+			meta:   func(a *models.Author) *models.Meta { return &a.Meta },
+			list: func(ctx context.Context, opts models.ListOptions) ([]models.Author, int64, error) {
+				return repo.List(ctx, author.Query{ListOptions: opts})
+			},
+			notFound:        author.ErrNotFound,
+			conflict:        author.ErrConflict,
+			versionMismatch: author.ErrVersionMismatch,
+		}
+	})
+
+	t.Run("filtering", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+
+		leGuin := models.NewAuthor(models.AuthorRequest{Name: "Ursula K. Le Guin"})
+		tolkien := models.NewAuthor(models.AuthorRequest{Name: "J.R.R. Tolkien"})
+		create(t, repo, leGuin, tolkien)
+
+		aa, total, err := repo.List(ctx, author.Query{Name: "le gu"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 1 || len(aa) != 1 || aa[0].Id != leGuin.Id {
+			t.Errorf("name filter: got %v (total %d), want %s", aa, total, leGuin.Id)
+		}
+
+		aa, err = repo.GetByIds(ctx, []string{tolkien.Id, "missing", leGuin.Id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]string, 0, len(aa))
+		for _, a := range aa {
+			got = append(got, a.Id)
+		}
+		if len(got) != 2 || !slices.Contains(got, leGuin.Id) || !slices.Contains(got, tolkien.Id) {
+			t.Errorf("get by ids: got %v, want %s and %s", got, leGuin.Id, tolkien.Id)
+		}
+
+		aa, err = repo.GetByIds(ctx, []string{"missing"})
+		if err != nil || len(aa) != 0 {
+			t.Errorf("get by missing ids: got %v, %v, want none", aa, err)
+		}
+	})
+}
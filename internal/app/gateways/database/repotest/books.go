@@ -0,0 +1,211 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/book"
+	"github.com/literalog/library/pkg/models"
+)
+
+func newBook(i int) *models.Book {
+	return models.NewBook(models.BookRequest{Title: fmt.Sprintf("Book %04d", i), AuthorId: "author"})
+}
+
+func testBooks(t *testing.T, newRepo func(t *testing.T) book.Repository) {
+	testEntity(t, func(t *testing.T) entity[models.Book] {
+		repo := newRepo(t)
+		return entity[models.Book]{
+			repo:   repo,
+			new:    newBook,
+			id:     func(b *models.Book) string { return b.Id },
+			name:   func(b *models.Book) string { return b.Title },
+			rename: func(b *models.Book, title string) { b.Title = title },
+			sort:   "title",
+			meta:   func(b *models.Book) *models.Meta { return &b.Meta },
+			list: func(ctx context.Context, opts models.ListOptions) ([]models.Book, int64, error) {
+				return repo.List(ctx, book.Query{ListOptions: opts})
+			},
+			notFound:        book.ErrNotFound,
+			conflict:        book.ErrConflict,
+			versionMismatch: book.ErrVersionMismatch,
+		}
+	})
+
+	t.Run("isbn", func(t *testing.T) { testBookIsbn(t, newRepo(t)) })
+	t.Run("filtering", func(t *testing.T) { testBookFilters(t, newRepo(t)) })
+	t.Run("isolation", func(t *testing.T) { testBookIsolation(t, newRepo(t)) })
+}
+
+func testBookIsbn(t *testing.T, repo book.Repository) {
+	ctx := context.Background()
+
+	wizard := models.NewBook(models.BookRequest{Title: "A Wizard of Earthsea", Isbn: []string{"9780547773742", "9780553262506"}})
+	tombs := models.NewBook(models.BookRequest{Title: "The Tombs of Atuan"})
+	create(t, repo, wizard, tombs)
+
+	b, err := repo.GetByIsbn(ctx, "9780553262506")
+	if err != nil || b.Id != wizard.Id {
+		t.Errorf("get by isbn: got %v, %v, want %s", b, err, wizard.Id)
+	}
+	_, err = repo.GetByIsbn(ctx, "9780000000002")
+	wantErr(t, "get by missing isbn", err, book.ErrNotFound, http.StatusNotFound)
+
+	taken := models.NewBook(models.BookRequest{Title: "Another", Isbn: []string{"9780547773742"}})
+	wantErr(t, "create with a taken isbn", repo.Create(ctx, taken), book.ErrDuplicateIsbn, http.StatusConflict)
+
+	tombs.Isbn = []string{"9780553262506"}
+	wantErr(t, "update with a taken isbn", repo.Update(ctx, tombs), book.ErrDuplicateIsbn, http.StatusConflict)
+
+	// A book keeps its own ISBNs when updated.
+	if err := repo.Update(ctx, wizard); err != nil {
+		t.Errorf("update keeping the isbns: %v", err)
+	}
+}
+
+func testBookFilters(t *testing.T, repo book.Repository) {
+	ctx := context.Background()
+
+	wizard := &models.Book{
+		Title: "A Wizard of Earthsea", Year: 1968, Language: "en", Format: models.Paperback,
+		SeriesId: "earthsea", SeriesNo: 1, WorkId: "wizard", Genre: []string{"fantasy"},
+		Isbn:         []string{"9780547773742"},
+		Contributors: []models.Contributor{{AuthorId: "le-guin", Role: models.RoleAuthor}},
+	}
+	tombs := &models.Book{
+		Title: "The Tombs of Atuan", Year: 1971, Language: "en", Format: models.Hardcover,
+		SeriesId: "earthsea", SeriesNo: 2, Genre: []string{"fantasy", "young-adult"},
+		Contributors: []models.Contributor{
+			{AuthorId: "le-guin", Role: models.RoleAuthor},
+			{AuthorId: "gay", Role: models.RoleIllustrator},
+		},
+	}
+	sorcier := &models.Book{
+		Title: "Le Sorcier de Terremer", Year: 1980, Language: "fr", Format: models.Paperback,
+		WorkId: "wizard", Genre: []string{"fantasy"},
+		Contributors: []models.Contributor{
+			{AuthorId: "le-guin", Role: models.RoleAuthor},
+			{AuthorId: "gay", Role: models.RoleTranslator},
+		},
+	}
+	// Books stored before contributors existed only have an author_id.
+	legacy := &models.Book{Title: "The Left Hand of Darkness", Year: 1969, AuthorId: "le-guin"}
+	for _, b := range []*models.Book{wizard, tombs, sorcier, legacy} {
+		b.Id = b.Title
+		b.Meta = models.NewMeta()
+	}
+	create(t, repo, wizard, tombs, sorcier, legacy)
+
+	byYear := models.ListOptions{Sort: []models.SortField{{Field: "year"}}}
+	ids := func(bb []models.Book) []string {
+		ii := make([]string, 0, len(bb))
+		for _, b := range bb {
+			ii = append(ii, b.Id)
+		}
+		return ii
+	}
+
+	tests := []struct {
+		name  string
+		query book.Query
+		want  []*models.Book
+	}{
+		{"all", book.Query{}, []*models.Book{wizard, legacy, tombs, sorcier}},
+		{"isbn", book.Query{Isbn: "9780547773742"}, []*models.Book{wizard}},
+		{"author", book.Query{AuthorId: "le-guin"}, []*models.Book{wizard, legacy, tombs, sorcier}},
+		{"author role", book.Query{AuthorId: "le-guin", Role: models.RoleAuthor}, []*models.Book{wizard, legacy, tombs, sorcier}},
+		{"illustrator", book.Query{AuthorId: "gay", Role: models.RoleIllustrator}, []*models.Book{tombs}},
+		{"any role", book.Query{AuthorId: "gay"}, []*models.Book{tombs, sorcier}},
+		{"series", book.Query{SeriesId: "earthsea"}, []*models.Book{wizard, tombs}},
+		{"genre", book.Query{Genre: "young-adult"}, []*models.Book{tombs}},
+		{"language", book.Query{Language: "fr"}, []*models.Book{sorcier}},
+		{"format", book.Query{Format: models.Paperback}, []*models.Book{wizard, sorcier}},
+		{"years", book.Query{YearFrom: 1969, YearTo: 1971}, []*models.Book{legacy, tombs}},
+		{"year from", book.Query{YearFrom: 1971}, []*models.Book{tombs, sorcier}},
+		{"work", book.Query{WorkId: "wizard"}, []*models.Book{wizard, sorcier}},
+		{"combined", book.Query{Genre: "fantasy", Language: "en", Format: models.Hardcover}, []*models.Book{tombs}},
+		{"text", book.Query{Text: "earthsea WIZARD"}, []*models.Book{wizard}},
+		{"text of every book", book.Query{Text: "the"}, []*models.Book{legacy, tombs}},
+		{"text missing a word", book.Query{Text: "wizard atuan"}, nil},
+		{"none", book.Query{SeriesId: "discworld"}, nil},
+	}
+	for _, tt := range tests {
+		tt.query.ListOptions = byYear
+		bb, total, err := repo.List(ctx, tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := make([]string, 0, len(tt.want))
+		for _, b := range tt.want {
+			want = append(want, b.Id)
+		}
+		if total != int64(len(want)) || !slices.Equal(ids(bb), want) {
+			t.Errorf("%s: got %v (total %d), want %v", tt.name, ids(bb), total, want)
+		}
+	}
+
+	lookups := []struct {
+		name string
+		get  func() ([]models.Book, int64, error)
+		want []*models.Book
+	}{
+		{"by author", func() ([]models.Book, int64, error) {
+			return repo.GetByAuthorId(ctx, "gay", models.RoleTranslator, byYear)
+		}, []*models.Book{sorcier}},
+		{"by series", func() ([]models.Book, int64, error) {
+			return repo.GetBySeriesId(ctx, "earthsea", models.ListOptions{Sort: []models.SortField{{Field: "series_no", Desc: true}}})
+		}, []*models.Book{tombs, wizard}},
+		{"by genre", func() ([]models.Book, int64, error) {
+			return repo.GetByGenre(ctx, "fantasy", models.ListOptions{Sort: byYear.Sort, Limit: 2})
+		}, []*models.Book{wizard, tombs}},
+		{"by work", func() ([]models.Book, int64, error) {
+			return repo.GetByWorkId(ctx, "wizard", byYear)
+		}, []*models.Book{wizard, sorcier}},
+	}
+	for _, l := range lookups {
+		bb, _, err := l.get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := make([]string, 0, len(l.want))
+		for _, b := range l.want {
+			want = append(want, b.Id)
+		}
+		if !slices.Equal(ids(bb), want) {
+			t.Errorf("%s: got %v, want %v", l.name, ids(bb), want)
+		}
+	}
+}
+
+// testBookIsolation checks that the books returned and the books stored do
+// not share memory.
+func testBookIsolation(t *testing.T, repo book.Repository) {
+	ctx := context.Background()
+
+	b := models.NewBook(models.BookRequest{Title: "A Wizard of Earthsea", AuthorId: "le-guin", Genre: []string{"fantasy"}})
+	create(t, repo, b)
+	b.Genre[0] = "changed after create"
+
+	got, err := repo.GetById(ctx, b.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Genre[0] = "changed after get"
+
+	all, err := repo.GetAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all[0].Genre[0] = "changed after get all"
+
+	got, err = repo.GetById(ctx, b.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Genre[0] != "fantasy" {
+		t.Errorf("stored genre changed to %q", got.Genre[0])
+	}
+}
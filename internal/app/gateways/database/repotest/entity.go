@@ -0,0 +1,384 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
+)
+
+// crud is what every entity repository has in common.
+type crud[T any] interface {
+	Create(ctx context.Context, v *T) error
+	Update(ctx context.Context, v *T) error
+	Delete(ctx context.Context, id string, version int64) error
+	GetById(ctx context.Context, id string) (*T, error)
+	GetAll(ctx context.Context) ([]T, error)
+}
+
+// entity binds a repository to what the generic tests need to know about
+// its entities.
+type entity[T any] struct {
+	repo crud[T]
+	// new returns the i-th of a set of distinct entities, whose name
+	// increases with i.
+	new func(i int) *T
+	id  func(v *T) string
+	// name is the field the entities are sorted by, named sort in lists.
+	name   func(v *T) string
+	rename func(v *T, name string)
+	sort   string
+	meta   func(v *T) *models.Meta
+	list   func(ctx context.Context, opts models.ListOptions) ([]T, int64, error)
+
+	notFound, conflict, versionMismatch error
+}
+
+// large is the number of entities stored to check large result sets.
+func large() int {
+	if testing.Short() {
+		return 200
+	}
+	return 1000
+}
+
+// testEntity runs the tests every repository must pass. newEntity must bind
+// an empty repository on every call.
+func testEntity[T any](t *testing.T, newEntity func(t *testing.T) entity[T]) {
+	t.Run("crud", func(t *testing.T) { testCrud(t, newEntity(t)) })
+	t.Run("not found", func(t *testing.T) { testNotFound(t, newEntity(t)) })
+	t.Run("conflicts", func(t *testing.T) { testConflicts(t, newEntity(t)) })
+	t.Run("ordering", func(t *testing.T) { testOrdering(t, newEntity(t)) })
+	t.Run("concurrency", func(t *testing.T) { testConcurrency(t, newEntity(t)) })
+	t.Run("large", func(t *testing.T) { testLarge(t, newEntity(t)) })
+}
+
+func create[T any](t *testing.T, repo crud[T], vv ...*T) {
+	t.Helper()
+	for _, v := range vv {
+		if err := repo.Create(context.Background(), v); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+}
+
+// wantErr fails unless err is sentinel, reported with the given status.
+func wantErr(t *testing.T, op string, err, sentinel error, status int) {
+	t.Helper()
+	if !errors.Is(err, sentinel) {
+		t.Errorf("%s: got %v, want %v", op, err, sentinel)
+		return
+	}
+	if got := problem.From(err).Status; got != status {
+		t.Errorf("%s: got status %d, want %d", op, got, status)
+	}
+}
+
+func names[T any](e entity[T], vv []T) []string {
+	nn := make([]string, 0, len(vv))
+	for i := range vv {
+		nn = append(nn, e.name(&vv[i]))
+	}
+	return nn
+}
+
+func ids[T any](e entity[T], vv []T) []string {
+	ii := make([]string, 0, len(vv))
+	for i := range vv {
+		ii = append(ii, e.id(&vv[i]))
+	}
+	return ii
+}
+
+func testCrud[T any](t *testing.T, e entity[T]) {
+	ctx := context.Background()
+
+	v := e.new(0)
+	create(t, e.repo, v)
+	if e.meta(v).Version != 1 {
+		t.Errorf("create: got version %d, want 1", e.meta(v).Version)
+	}
+
+	got, err := e.repo.GetById(ctx, e.id(v))
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if e.id(got) != e.id(v) || e.name(got) != e.name(v) || e.meta(got).Version != 1 {
+		t.Errorf("get: got %s %q v%d, want %s %q v1", e.id(got), e.name(got), e.meta(got).Version, e.id(v), e.name(v))
+	}
+	if !e.meta(got).CreatedAt.Equal(e.meta(v).CreatedAt) {
+		t.Errorf("get: got created_at %v, want %v", e.meta(got).CreatedAt, e.meta(v).CreatedAt)
+	}
+
+	e.rename(got, "Renamed")
+	if err := e.repo.Update(ctx, got); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if e.meta(got).Version != 2 {
+		t.Errorf("update: got version %d, want 2", e.meta(got).Version)
+	}
+	updated, err := e.repo.GetById(ctx, e.id(v))
+	if err != nil {
+		t.Fatalf("get updated: %v", err)
+	}
+	if e.name(updated) != "Renamed" || e.meta(updated).Version != 2 {
+		t.Errorf("get updated: got %q v%d, want %q v2", e.name(updated), e.meta(updated).Version, "Renamed")
+	}
+
+	other := e.new(1)
+	create(t, e.repo, other)
+	all, err := e.repo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("get all: %v", err)
+	}
+	if got := ids(e, all); len(got) != 2 || !slices.Contains(got, e.id(v)) || !slices.Contains(got, e.id(other)) {
+		t.Errorf("get all: got %v, want %s and %s", got, e.id(v), e.id(other))
+	}
+
+	if err := e.repo.Delete(ctx, e.id(v), 0); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	all, err = e.repo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("get all: %v", err)
+	}
+	if got := ids(e, all); !slices.Equal(got, []string{e.id(other)}) {
+		t.Errorf("get all after delete: got %v, want [%s]", got, e.id(other))
+	}
+}
+
+func testNotFound[T any](t *testing.T, e entity[T]) {
+	ctx := context.Background()
+
+	missing := e.new(0)
+	_, err := e.repo.GetById(ctx, e.id(missing))
+	wantErr(t, "get missing", err, e.notFound, http.StatusNotFound)
+	wantErr(t, "update missing", e.repo.Update(ctx, missing), e.notFound, http.StatusNotFound)
+	wantErr(t, "delete missing", e.repo.Delete(ctx, e.id(missing), 0), e.notFound, http.StatusNotFound)
+
+	v := e.new(1)
+	create(t, e.repo, v)
+	if err := e.repo.Delete(ctx, e.id(v), 0); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	_, err = e.repo.GetById(ctx, e.id(v))
+	wantErr(t, "get deleted", err, e.notFound, http.StatusNotFound)
+	wantErr(t, "update deleted", e.repo.Update(ctx, v), e.notFound, http.StatusNotFound)
+	wantErr(t, "delete twice", e.repo.Delete(ctx, e.id(v), 0), e.notFound, http.StatusNotFound)
+
+	all, err := e.repo.GetAll(ctx)
+	if err != nil || len(all) != 0 {
+		t.Errorf("get all: got %d, %v, want none", len(all), err)
+	}
+	page, total, err := e.list(ctx, models.ListOptions{Limit: 10})
+	if err != nil || len(page) != 0 || total != 0 || page == nil {
+		t.Errorf("list: got %v, %d, %v, want an empty page", page, total, err)
+	}
+}
+
+func testConflicts[T any](t *testing.T, e entity[T]) {
+	ctx := context.Background()
+
+	v := e.new(0)
+	create(t, e.repo, v)
+	dup := *v
+	wantErr(t, "create twice", e.repo.Create(ctx, &dup), e.conflict, http.StatusConflict)
+
+	stale := *v
+	if err := e.repo.Update(ctx, v); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	wantErr(t, "update stale", e.repo.Update(ctx, &stale), e.versionMismatch, http.StatusPreconditionFailed)
+
+	got, err := e.repo.GetById(ctx, e.id(v))
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if e.meta(got).Version != 2 {
+		t.Errorf("got version %d after a stale update, want 2", e.meta(got).Version)
+	}
+
+	wantErr(t, "delete stale", e.repo.Delete(ctx, e.id(v), 1), e.versionMismatch, http.StatusPreconditionFailed)
+	if err := e.repo.Delete(ctx, e.id(v), 2); err != nil {
+		t.Fatalf("delete current: %v", err)
+	}
+	wantErr(t, "delete deleted", e.repo.Delete(ctx, e.id(v), 2), e.notFound, http.StatusNotFound)
+}
+
+func testOrdering[T any](t *testing.T, e entity[T]) {
+	ctx := context.Background()
+
+	const n = 7
+	want := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		want = append(want, e.name(e.new(i)))
+	}
+	for _, i := range rand.Perm(n) {
+		create(t, e.repo, e.new(i))
+	}
+
+	asc := []models.SortField{{Field: e.sort}}
+	got, total, err := e.list(ctx, models.ListOptions{Sort: asc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != n || !slices.Equal(names(e, got), want) {
+		t.Errorf("ascending: got %v (total %d), want %v", names(e, got), total, want)
+	}
+
+	desc := []models.SortField{{Field: e.sort, Desc: true}}
+	got, _, err = e.list(ctx, models.ListOptions{Sort: desc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reversed := slices.Clone(want)
+	slices.Reverse(reversed)
+	if !slices.Equal(names(e, got), reversed) {
+		t.Errorf("descending: got %v, want %v", names(e, got), reversed)
+	}
+
+	got, total, err = e.list(ctx, models.ListOptions{Sort: asc, Offset: 2, Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != n || !slices.Equal(names(e, got), want[2:5]) {
+		t.Errorf("page: got %v (total %d), want %v (total %d)", names(e, got), total, want[2:5], n)
+	}
+
+	got, total, err = e.list(ctx, models.ListOptions{Sort: asc, Offset: n, Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != n || len(got) != 0 {
+		t.Errorf("past the end: got %v (total %d), want none (total %d)", names(e, got), total, n)
+	}
+
+	// Without a sort the order is still stable, so pages neither repeat nor
+	// skip entities.
+	all, _, err := e.list(ctx, models.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paged []T
+	for offset := 0; offset < n; offset += 3 {
+		page, _, err := e.list(ctx, models.ListOptions{Offset: offset, Limit: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		paged = append(paged, page...)
+	}
+	if !slices.Equal(ids(e, paged), ids(e, all)) {
+		t.Errorf("unsorted pages: got %v, want %v", ids(e, paged), ids(e, all))
+	}
+}
+
+func testConcurrency[T any](t *testing.T, e entity[T]) {
+	ctx := context.Background()
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- e.repo.Create(ctx, e.new(i))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent create: %v", err)
+		}
+	}
+	all, err := e.repo.GetAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != n {
+		t.Errorf("got %d entities after %d concurrent creates", len(all), n)
+	}
+
+	// Updates based on the same version race: exactly one must win.
+	base := all[0]
+	results := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v := base
+			e.rename(&v, fmt.Sprintf("Writer %d", i))
+			results <- e.repo.Update(ctx, &v)
+		}(i)
+	}
+	wg.Wait()
+	close(results)
+
+	won := 0
+	for err := range results {
+		switch {
+		case err == nil:
+			won++
+		case !errors.Is(err, e.versionMismatch):
+			t.Errorf("concurrent update: got %v, want nil or %v", err, e.versionMismatch)
+		}
+	}
+	if won != 1 {
+		t.Errorf("%d concurrent updates of the same version succeeded, want 1", won)
+	}
+	got, err := e.repo.GetById(ctx, e.id(&base))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.meta(got).Version != e.meta(&base).Version+1 {
+		t.Errorf("got version %d, want %d", e.meta(got).Version, e.meta(&base).Version+1)
+	}
+}
+
+func testLarge[T any](t *testing.T, e entity[T]) {
+	ctx := context.Background()
+
+	n := large()
+	for _, i := range rand.Perm(n) {
+		create(t, e.repo, e.new(i))
+	}
+
+	all, err := e.repo.GetAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != n {
+		t.Errorf("get all: got %d, want %d", len(all), n)
+	}
+
+	var seen []string
+	opts := models.ListOptions{Limit: models.MaxLimit, Sort: []models.SortField{{Field: e.sort}}}
+	for {
+		page, total, err := e.list(ctx, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != int64(n) {
+			t.Fatalf("offset %d: got total %d, want %d", opts.Offset, total, n)
+		}
+		if len(page) == 0 {
+			break
+		}
+		seen = append(seen, names(e, page)...)
+		opts.Offset += len(page)
+	}
+	if len(seen) != n || !slices.IsSorted(seen) {
+		t.Errorf("paging: got %d sorted %v, want %d sorted", len(seen), slices.IsSorted(seen), n)
+	}
+	if len(slices.Compact(slices.Clone(seen))) != n {
+		t.Error("paging returned an entity twice")
+	}
+}
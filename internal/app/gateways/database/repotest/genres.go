@@ -0,0 +1,85 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/genre"
+	"github.com/literalog/library/pkg/models"
+)
+
+func newGenre(i int) *models.Genre {
+	return models.NewGenre(models.GenreRequest{Tag: fmt.Sprintf("genre-%04d", i)})
+}
+
+func testGenres(t *testing.T, newRepo func(t *testing.T) genre.Repository) {
+	testEntity(t, func(t *testing.T) entity[models.Genre] {
+		repo := newRepo(t)
+		return entity[models.Genre]{
+			repo:   repo,
+			new:    newGenre,
+			id:     func(g *models.Genre) string { return g.Id },
+			name:   func(g *models.Genre) string { return g.Tag },
+			rename: func(g *models.Genre, tag string) { g.Tag = tag },
+			sort:   "tag",
+			meta:   func(g *models.Genre) *models.Meta { return &g.Meta },
+			list: func(ctx context.Context, opts models.ListOptions) ([]models.Genre, int64, error) {
+				return repo.List(ctx, genre.Query{ListOptions: opts})
+			},
+			notFound:        genre.ErrNotFound,
+			conflict:        genre.ErrConflict,
+			versionMismatch: genre.ErrVersionMismatch,
+		}
+	})
+
+	t.Run("filtering", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+
+		fantasy := models.NewGenre(models.GenreRequest{Tag: "fantasy"})
+		scifi := models.NewGenre(models.GenreRequest{Tag: "science-fiction"})
+		create(t, repo, fantasy, scifi)
+
+		gg, total, err := repo.List(ctx, genre.Query{Tag: "Fant"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 1 || len(gg) != 1 || gg[0].Id != fantasy.Id {
+			t.Errorf("tag filter: got %v (total %d), want %s", gg, total, fantasy.Id)
+		}
+
+		g, err := repo.GetByName(ctx, "science-fiction")
+		if err != nil || g.Id != scifi.Id {
+			t.Errorf("get by name: got %v, %v, want %s", g, err, scifi.Id)
+		}
+		_, err = repo.GetByName(ctx, "romance")
+		wantErr(t, "get by missing name", err, genre.ErrNotFound, http.StatusNotFound)
+
+		gg, err = repo.GetByTags(ctx, []string{"fantasy", "romance"})
+		if err != nil || len(gg) != 1 || gg[0].Id != fantasy.Id {
+			t.Errorf("get by tags: got %v, %v, want %s", gg, err, fantasy.Id)
+		}
+	})
+
+	t.Run("unique tags", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+
+		fantasy := models.NewGenre(models.GenreRequest{Tag: "fantasy"})
+		horror := models.NewGenre(models.GenreRequest{Tag: "horror"})
+		create(t, repo, fantasy, horror)
+
+		again := models.NewGenre(models.GenreRequest{Tag: "fantasy"})
+		wantErr(t, "create with a taken tag", repo.Create(ctx, again), genre.ErrDuplicateTag, http.StatusConflict)
+
+		horror.Tag = "fantasy"
+		wantErr(t, "update to a taken tag", repo.Update(ctx, horror), genre.ErrDuplicateTag, http.StatusConflict)
+
+		// A genre keeps its own tag when updated.
+		if err := repo.Update(ctx, fantasy); err != nil {
+			t.Errorf("update keeping the tag: %v", err)
+		}
+	})
+}
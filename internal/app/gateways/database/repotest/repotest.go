@@ -0,0 +1,66 @@
+// Package repotest checks that a storage backend behaves the way the domain
+// services expect of their repositories. A backend runs the suite from its
+// own tests:
+//
+//	func TestRepositories(t *testing.T) {
+//		repotest.Run(t, repotest.Factories{
+//			Authors: func(t *testing.T) author.Repository { return NewAuthorRepository() },
+//			...
+//		})
+//	}
+package repotest
+
+import (
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/author"
+	"github.com/literalog/library/internal/app/domain/book"
+	"github.com/literalog/library/internal/app/domain/genre"
+	"github.com/literalog/library/internal/app/domain/series"
+	"github.com/literalog/library/internal/app/domain/work"
+)
+
+// Factories make the repositories under test. Every call must return an
+// empty repository, isolated from the ones returned before. The tests of a
+// repository whose factory is nil are skipped.
+type Factories struct {
+	Authors func(t *testing.T) author.Repository
+	Series  func(t *testing.T) series.Repository
+	Genres  func(t *testing.T) genre.Repository
+	Works   func(t *testing.T) work.Repository
+	Books   func(t *testing.T) book.Repository
+}
+
+// Run runs the whole suite against the repositories made by f.
+func Run(t *testing.T, f Factories) {
+	t.Run("authors", func(t *testing.T) {
+		if f.Authors == nil {
+			t.Skip("no author repository")
+		}
+		testAuthors(t, f.Authors)
+	})
+	t.Run("series", func(t *testing.T) {
+		if f.Series == nil {
+			t.Skip("no series repository")
+		}
+		testSeries(t, f.Series)
+	})
+	t.Run("genres", func(t *testing.T) {
+		if f.Genres == nil {
+			t.Skip("no genre repository")
+		}
+		testGenres(t, f.Genres)
+	})
+	t.Run("works", func(t *testing.T) {
+		if f.Works == nil {
+			t.Skip("no work repository")
+		}
+		testWorks(t, f.Works)
+	})
+	t.Run("books", func(t *testing.T) {
+		if f.Books == nil {
+			t.Skip("no book repository")
+		}
+		testBooks(t, f.Books)
+	})
+}
@@ -0,0 +1,51 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/series"
+	"github.com/literalog/library/pkg/models"
+)
+
+func newSeries(i int) *models.Series {
+	return models.NewSeries(models.SeriesRequest{Name: fmt.Sprintf("Series %04d", i)})
+}
+
+func testSeries(t *testing.T, newRepo func(t *testing.T) series.Repository) {
+	testEntity(t, func(t *testing.T) entity[models.Series] {
+		repo := newRepo(t)
+		return entity[models.Series]{
+			repo:   repo,
+			new:    newSeries,
+			id:     func(s *models.Series) string { return s.Id },
+			name:   func(s *models.Series) string { return s.Name },
+			rename: func(s *models.Series, name string) { s.Name = name },
+			sort:   "name",
+			meta:   func(s *models.Series) *models.Meta { return &s.Meta },
+			list: func(ctx context.Context, opts models.ListOptions) ([]models.Series, int64, error) {
+				return repo.List(ctx, series.Query{ListOptions: opts})
+			},
+			notFound:        series.ErrNotFound,
+			conflict:        series.ErrConflict,
+			versionMismatch: series.ErrVersionMismatch,
+		}
+	})
+
+	t.Run("filtering", func(t *testing.T) {
+		repo := newRepo(t)
+
+		earthsea := models.NewSeries(models.SeriesRequest{Name: "Earthsea Cycle"})
+		discworld := models.NewSeries(models.SeriesRequest{Name: "Discworld"})
+		create(t, repo, earthsea, discworld)
+
+		ss, total, err := repo.List(context.Background(), series.Query{Name: "EARTH"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 1 || len(ss) != 1 || ss[0].Id != earthsea.Id {
+			t.Errorf("name filter: got %v (total %d), want %s", ss, total, earthsea.Id)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/work"
+	"github.com/literalog/library/pkg/models"
+)
+
+func newWork(i int) *models.Work {
+	return models.NewWork(models.WorkRequest{Title: fmt.Sprintf("Work %04d", i)})
+}
+
+func testWorks(t *testing.T, newRepo func(t *testing.T) work.Repository) {
+	testEntity(t, func(t *testing.T) entity[models.Work] {
+		repo := newRepo(t)
+		return entity[models.Work]{
+			repo:   repo,
+			new:    newWork,
+			id:     func(w *models.Work) string { return w.Id },
+			name:   func(w *models.Work) string { return w.Title },
+			rename: func(w *models.Work, title string) { w.Title = title },
+			sort:   "title",
+			meta:   func(w *models.Work) *models.Meta { return &w.Meta },
+			list: func(ctx context.Context, opts models.ListOptions) ([]models.Work, int64, error) {
+				return repo.List(ctx, work.Query{ListOptions: opts})
+			},
+			notFound:        work.ErrNotFound,
+			conflict:        work.ErrConflict,
+			versionMismatch: work.ErrVersionMismatch,
+		}
+	})
+
+	t.Run("filtering", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+
+		wizard := models.NewWork(models.WorkRequest{Title: "A Wizard of Earthsea", SeriesId: "earthsea"})
+		tombs := models.NewWork(models.WorkRequest{Title: "The Tombs of Atuan", SeriesId: "earthsea"})
+		hobbit := models.NewWork(models.WorkRequest{Title: "The Hobbit"})
+		create(t, repo, wizard, tombs, hobbit)
+
+		tests := []struct {
+			name  string
+			query work.Query
+			want  []string
+		}{
+			{"title", work.Query{Title: "WIZARD"}, []string{wizard.Id}},
+			{"series", work.Query{SeriesId: "earthsea"}, []string{wizard.Id, tombs.Id}},
+			{"title and series", work.Query{Title: "the", SeriesId: "earthsea"}, []string{tombs.Id}},
+			{"none", work.Query{Title: "silmarillion"}, nil},
+		}
+		for _, tt := range tests {
+			tt.query.Sort = []models.SortField{{Field: "title"}}
+			ww, total, err := repo.List(ctx, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0, len(ww))
+			for _, w := range ww {
+				got = append(got, w.Id)
+			}
+			if total != int64(len(tt.want)) || !slices.Equal(got, tt.want) {
+				t.Errorf("%s: got %v (total %d), want %v", tt.name, got, total, tt.want)
+			}
+		}
+	})
+}
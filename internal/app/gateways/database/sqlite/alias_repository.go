@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/literalog/library/internal/app/domain/alias"
+)
+
+type AliasRepository struct {
+	db    *sql.DB
+	table string
+}
+
+// NewAliasRepository keeps aliases in table, one of the *_aliases tables of
+// the schema.
+func NewAliasRepository(db *sql.DB, table string) alias.Repository {
+	return &AliasRepository{
+		db:    db,
+		table: table,
+	}
+}
+
+func (r *AliasRepository) Redirect(ctx context.Context, id string, from []string) error {
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		w := new(where)
+		w.and("target IN " + w.in(from))
+		if _, err := tx.ExecContext(ctx, "UPDATE "+r.table+" SET target = "+w.arg(id)+w.String(), w.args...); err != nil {
+			return err
+		}
+		for _, a := range from {
+			_, err := tx.ExecContext(ctx, "INSERT INTO "+r.table+" (id, target) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET target = excluded.target", a, id)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error redirecting aliases: %w", err)
+	}
+	return nil
+}
+
+func (r *AliasRepository) Resolve(ctx context.Context, id string) (string, error) {
+	var to string
+	err := r.db.QueryRowContext(ctx, "SELECT target FROM "+r.table+" WHERE id = ?", id).Scan(&to)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", alias.ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("error resolving alias: %w", err)
+	}
+	return to, nil
+}
@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/literalog/library/internal/app/domain/author"
+	"github.com/literalog/library/pkg/models"
+)
+
+const authorColumns = "id, version, created_at, updated_at, name"
+
+var authorSort = map[string]string{"name": "name"}
+
+type AuthorRepository struct {
+	db *sql.DB
+}
+
+func NewAuthorRepository(db *sql.DB) author.Repository {
+	return &AuthorRepository{
+		db: db,
+	}
+}
+
+func scanAuthor(row row) (models.Author, error) {
+	var a models.Author
+	err := row.Scan(&a.Id, &a.Version, stamp{&a.CreatedAt}, stamp{&a.UpdatedAt}, &a.Name)
+	return a, err
+}
+
+func (r *AuthorRepository) Create(ctx context.Context, a *models.Author) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO authors ("+authorColumns+") VALUES (?, ?, ?, ?, ?)",
+		a.Id, a.Version, stamp{&a.CreatedAt}, stamp{&a.UpdatedAt}, a.Name)
+	if _, ok := unique(err); ok {
+		return author.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error creating author: %w", err)
+	}
+	return nil
+}
+
+func (r *AuthorRepository) Update(ctx context.Context, a *models.Author) error {
+	next := a.Meta.Next()
+	res, err := r.db.ExecContext(ctx, "UPDATE authors SET version = ?, updated_at = ?, name = ? WHERE id = ? AND version = ?",
+		next.Version, stamp{&next.UpdatedAt}, a.Name, a.Id, a.Version)
+	if err != nil {
+		return fmt.Errorf("error updating author: %w", err)
+	}
+	if !changed(res) {
+		return stale(ctx, r.db, "authors", a.Id, author.ErrNotFound, author.ErrVersionMismatch)
+	}
+
+	a.Meta = next
+	return nil
+}
+
+func (r *AuthorRepository) Delete(ctx context.Context, id string, version int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM authors WHERE id = ?1 AND (?2 = 0 OR version = ?2)", id, version)
+	if _, ok := violates(err, foreignKeyViolation); ok {
+		return author.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting author: %w", err)
+	}
+	if !changed(res) {
+		return stale(ctx, r.db, "authors", id, author.ErrNotFound, author.ErrVersionMismatch)
+	}
+	return nil
+}
+
+func (r *AuthorRepository) GetById(ctx context.Context, id string) (*models.Author, error) {
+	a, err := scanAuthor(r.db.QueryRowContext(ctx, "SELECT "+authorColumns+" FROM authors WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, author.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting author: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *AuthorRepository) GetByIds(ctx context.Context, ids []string) ([]models.Author, error) {
+	w := new(where)
+	w.and("id IN " + w.in(ids))
+	rows, err := r.db.QueryContext(ctx, "SELECT "+authorColumns+" FROM authors"+w.String(), w.args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting authors: %w", err)
+	}
+	aa, err := collect(rows, scanAuthor)
+	if err != nil {
+		return nil, fmt.Errorf("error getting authors: %w", err)
+	}
+	return aa, nil
+}
+
+func (r *AuthorRepository) GetAll(ctx context.Context) ([]models.Author, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+authorColumns+" FROM authors ORDER BY seq")
+	if err != nil {
+		return nil, fmt.Errorf("error getting authors: %w", err)
+	}
+	aa, err := collect(rows, scanAuthor)
+	if err != nil {
+		return nil, fmt.Errorf("error getting authors: %w", err)
+	}
+	return aa, nil
+}
+
+func (r *AuthorRepository) List(ctx context.Context, q author.Query) ([]models.Author, int64, error) {
+	w := new(where)
+	if q.Name != "" {
+		w.containsFold("name", q.Name)
+	}
+	return list(ctx, r.db, "authors", authorColumns, w, q.ListOptions, authorSort, scanAuthor)
+}
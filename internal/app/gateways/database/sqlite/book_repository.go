@@ -0,0 +1,317 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/literalog/library/internal/app/domain/book"
+	"github.com/literalog/library/pkg/models"
+)
+
+const bookColumns = "id, version, created_at, updated_at, coalesce(work_id, ''), title, coalesce(author_id, ''), coalesce(series_id, ''), " +
+	"series_no, year, publisher, language, format, pages_no, hours_no, blurb, cover, not_a_book"
+
+var bookSort = map[string]string{
+	"title":     "title",
+	"year":      "year",
+	"series_no": "series_no",
+	"language":  "language",
+	"format":    "format",
+	"pages_no":  "pages_no",
+	"hours_no":  "hours_no",
+}
+
+type BookRepository struct {
+	db *sql.DB
+}
+
+func NewBookRepository(db *sql.DB) book.Repository {
+	return &BookRepository{
+		db: db,
+	}
+}
+
+func scanBook(row row) (models.Book, error) {
+	var (
+		b      models.Book
+		format string
+	)
+	err := row.Scan(&b.Id, &b.Version, stamp{&b.CreatedAt}, stamp{&b.UpdatedAt}, &b.WorkId, &b.Title, &b.AuthorId, &b.SeriesId,
+		&b.SeriesNo, &b.Year, &b.Publisher, &b.Language, &format, &b.PagesNo, &b.HoursNo, &b.Blurb, &b.Cover, &b.NotABook)
+	b.Format = models.Format(format)
+	return b, err
+}
+
+// bookError translates the constraint violations of a write of b: an ISBN
+// or id already in use, or a missing author or series.
+func (r *BookRepository) bookError(ctx context.Context, op string, b *models.Book, err error) error {
+	if msg, ok := unique(err); ok {
+		if strings.Contains(msg, "book_isbns.isbn") {
+			return book.ErrDuplicateIsbn
+		}
+		return book.ErrConflict
+	}
+	if _, ok := violates(err, foreignKeyViolation); ok {
+		return r.missingReference(ctx, b)
+	}
+	if err != nil {
+		return fmt.Errorf("error %s book: %w", op, err)
+	}
+	return nil
+}
+
+// missingReference tells which reference of b broke a foreign key, as
+// SQLite does not name the failed one: the series if missing, or else an
+// author.
+func (r *BookRepository) missingReference(ctx context.Context, b *models.Book) error {
+	if b.SeriesId == "" {
+		return book.ErrAuthorNotFound
+	}
+	var exists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM series WHERE id = ?)", b.SeriesId).Scan(&exists)
+	switch {
+	case err != nil:
+		return fmt.Errorf("error writing book: %w", err)
+	case !exists:
+		return book.ErrSeriesNotFound
+	default:
+		return book.ErrAuthorNotFound
+	}
+}
+
+// writeChildren replaces the contributors, ISBNs and genres of b.
+func writeChildren(ctx context.Context, tx *sql.Tx, b *models.Book) error {
+	if err := writeContributors(ctx, tx, "book", b.Id, b.Contributors); err != nil {
+		return err
+	}
+	if err := writeGenres(ctx, tx, "book", b.Id, b.Genre); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM book_isbns WHERE book_id = ?", b.Id); err != nil {
+		return err
+	}
+	for i, isbn := range b.Isbn {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO book_isbns (isbn, book_id, position) VALUES (?, ?, ?)", isbn, b.Id, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attach reads the contributors, ISBNs and genres of bb.
+func (r *BookRepository) attach(ctx context.Context, bb []models.Book) error {
+	ids := make([]string, 0, len(bb))
+	for _, b := range bb {
+		ids = append(ids, b.Id)
+	}
+
+	contributors, err := readContributors(ctx, r.db, "book", ids)
+	if err != nil {
+		return err
+	}
+	isbns, err := readValues(ctx, r.db, "book_isbns", "book", "isbn", ids)
+	if err != nil {
+		return err
+	}
+	genres, err := readValues(ctx, r.db, "book_genres", "book", "tag", ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range bb {
+		bb[i].Contributors = contributors[bb[i].Id]
+		bb[i].Isbn = isbns[bb[i].Id]
+		bb[i].Genre = genres[bb[i].Id]
+	}
+	return nil
+}
+
+// query returns the books selected by sql, with their contributors, ISBNs
+// and genres.
+func (r *BookRepository) query(ctx context.Context, query string, args ...any) ([]models.Book, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting books: %w", err)
+	}
+	bb, err := collect(rows, scanBook)
+	if err != nil {
+		return nil, fmt.Errorf("error getting books: %w", err)
+	}
+	if err := r.attach(ctx, bb); err != nil {
+		return nil, fmt.Errorf("error getting books: %w", err)
+	}
+	return bb, nil
+}
+
+func (r *BookRepository) Create(ctx context.Context, b *models.Book) error {
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO books (id, version, created_at, updated_at, work_id, title, author_id, series_id, "+
+			"series_no, year, publisher, language, format, pages_no, hours_no, blurb, cover, not_a_book) "+
+			"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			b.Id, b.Version, stamp{&b.CreatedAt}, stamp{&b.UpdatedAt}, nullable(b.WorkId), b.Title, nullable(b.AuthorId), nullable(b.SeriesId),
+			b.SeriesNo, b.Year, b.Publisher, b.Language, string(b.Format), b.PagesNo, b.HoursNo, b.Blurb, b.Cover, b.NotABook)
+		if err != nil {
+			return err
+		}
+		return writeChildren(ctx, tx, b)
+	})
+	return r.bookError(ctx, "creating", b, err)
+}
+
+func (r *BookRepository) Update(ctx context.Context, b *models.Book) error {
+	next := b.Meta.Next()
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "UPDATE books SET version = ?, updated_at = ?, work_id = ?, title = ?, author_id = ?, series_id = ?, "+
+			"series_no = ?, year = ?, publisher = ?, language = ?, format = ?, pages_no = ?, hours_no = ?, "+
+			"blurb = ?, cover = ?, not_a_book = ? WHERE id = ? AND version = ?",
+			next.Version, stamp{&next.UpdatedAt}, nullable(b.WorkId), b.Title, nullable(b.AuthorId), nullable(b.SeriesId),
+			b.SeriesNo, b.Year, b.Publisher, b.Language, string(b.Format), b.PagesNo, b.HoursNo, b.Blurb, b.Cover, b.NotABook,
+			b.Id, b.Version)
+		if err != nil {
+			return err
+		}
+		if !changed(res) {
+			return errStale
+		}
+		return writeChildren(ctx, tx, b)
+	})
+	if errors.Is(err, errStale) {
+		return stale(ctx, r.db, "books", b.Id, book.ErrNotFound, book.ErrVersionMismatch)
+	}
+	if err != nil {
+		return r.bookError(ctx, "updating", b, err)
+	}
+
+	b.Meta = next
+	return nil
+}
+
+func (r *BookRepository) Delete(ctx context.Context, id string, version int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM books WHERE id = ?1 AND (?2 = 0 OR version = ?2)", id, version)
+	if err != nil {
+		return fmt.Errorf("error deleting book: %w", err)
+	}
+	if !changed(res) {
+		return stale(ctx, r.db, "books", id, book.ErrNotFound, book.ErrVersionMismatch)
+	}
+	return nil
+}
+
+func (r *BookRepository) GetById(ctx context.Context, id string) (*models.Book, error) {
+	bb, err := r.query(ctx, "SELECT "+bookColumns+" FROM books WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(bb) == 0 {
+		return nil, book.ErrNotFound
+	}
+	return &bb[0], nil
+}
+
+func (r *BookRepository) GetByIsbn(ctx context.Context, isbn string) (*models.Book, error) {
+	bb, err := r.query(ctx, "SELECT "+bookColumns+" FROM books WHERE id = (SELECT book_id FROM book_isbns WHERE isbn = ?)", isbn)
+	if err != nil {
+		return nil, err
+	}
+	if len(bb) == 0 {
+		return nil, book.ErrNotFound
+	}
+	return &bb[0], nil
+}
+
+func (r *BookRepository) GetAll(ctx context.Context) ([]models.Book, error) {
+	return r.query(ctx, "SELECT "+bookColumns+" FROM books ORDER BY seq")
+}
+
+func (r *BookRepository) List(ctx context.Context, q book.Query) ([]models.Book, int64, error) {
+	bb, total, err := list(ctx, r.db, "books", bookColumns, bookWhere(q), q.ListOptions, bookSort, scanBook)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := r.attach(ctx, bb); err != nil {
+		return nil, 0, fmt.Errorf("error getting books: %w", err)
+	}
+	return bb, total, nil
+}
+
+func bookWhere(q book.Query) *where {
+	w := new(where)
+	if q.WorkId != "" {
+		w.and("work_id = " + w.arg(q.WorkId))
+	}
+	if q.Isbn != "" {
+		w.and("EXISTS (SELECT 1 FROM book_isbns i WHERE i.book_id = books.id AND i.isbn = " + w.arg(q.Isbn) + ")")
+	}
+	if q.AuthorId != "" {
+		w.and(contributorCond(w, q.AuthorId, q.Role))
+	}
+	if q.SeriesId != "" {
+		w.and("series_id = " + w.arg(q.SeriesId))
+	}
+	if q.Genre != "" {
+		w.and("EXISTS (SELECT 1 FROM book_genres g WHERE g.book_id = books.id AND g.tag = " + w.arg(q.Genre) + ")")
+	}
+	if q.Language != "" {
+		w.and("language = " + w.arg(q.Language))
+	}
+	if q.Format != "" {
+		w.and("format = " + w.arg(string(q.Format)))
+	}
+	if q.YearFrom != 0 {
+		w.and("year >= " + w.arg(q.YearFrom))
+	}
+	if q.YearTo != 0 {
+		w.and("year <= " + w.arg(q.YearTo))
+	}
+	if match := ftsQuery(q.Text); match != "" {
+		w.and("seq IN (SELECT rowid FROM books_fts WHERE books_fts MATCH " + w.arg(match) + ")")
+	}
+	return w
+}
+
+// contributorCond matches the books authorId contributed to in role, or in
+// any role when role is empty. Books stored before contributors existed
+// only have an author_id.
+func contributorCond(w *where, authorId string, role models.Role) string {
+	id := w.arg(authorId)
+	cond := "EXISTS (SELECT 1 FROM book_contributors c WHERE c.book_id = books.id AND c.author_id = " + id
+	if role != "" {
+		cond += " AND c.role = " + w.arg(string(role))
+	}
+	cond += ")"
+
+	if role == "" || role == models.RoleAuthor {
+		cond = "(" + cond + " OR author_id = " + id + ")"
+	}
+	return cond
+}
+
+func (r *BookRepository) GetByAuthorId(ctx context.Context, authorId string, role models.Role, opts models.ListOptions) ([]models.Book, int64, error) {
+	return r.List(ctx, book.Query{ListOptions: opts, AuthorId: authorId, Role: role})
+}
+
+func (r *BookRepository) GetBySeriesId(ctx context.Context, seriesId string, opts models.ListOptions) ([]models.Book, int64, error) {
+	return r.List(ctx, book.Query{ListOptions: opts, SeriesId: seriesId})
+}
+
+func (r *BookRepository) GetByGenre(ctx context.Context, tag string, opts models.ListOptions) ([]models.Book, int64, error) {
+	return r.List(ctx, book.Query{ListOptions: opts, Genre: tag})
+}
+
+func (r *BookRepository) GetByWorkId(ctx context.Context, workId string, opts models.ListOptions) ([]models.Book, int64, error) {
+	return r.List(ctx, book.Query{ListOptions: opts, WorkId: workId})
+}
+
+// ftsQuery quotes each word of text as an FTS5 string, so that the words
+// are matched as they are rather than read as query syntax.
+func ftsQuery(text string) string {
+	words := strings.Fields(text)
+	for i, w := range words {
+		words[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"`
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/literalog/library/pkg/models"
+)
+
+// Books and works keep their contributors and genres in the
+// <owner>_contributors and <owner>_genres tables, where owner is "book" or
+// "work", in the order they are listed.
+
+// writeContributors replaces the contributors of the book or work with the
+// given id.
+func writeContributors(ctx context.Context, q querier, owner, id string, cc []models.Contributor) error {
+	if _, err := q.ExecContext(ctx, "DELETE FROM "+owner+"_contributors WHERE "+owner+"_id = ?", id); err != nil {
+		return err
+	}
+	for i, c := range cc {
+		_, err := q.ExecContext(ctx, "INSERT INTO "+owner+"_contributors ("+owner+"_id, position, author_id, role, ord) VALUES (?, ?, ?, ?, ?)",
+			id, i, c.AuthorId, string(c.Role), c.Order)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeGenres replaces the genres of the book or work with the given id.
+func writeGenres(ctx context.Context, q querier, owner, id string, tags []string) error {
+	if _, err := q.ExecContext(ctx, "DELETE FROM "+owner+"_genres WHERE "+owner+"_id = ?", id); err != nil {
+		return err
+	}
+	for i, tag := range tags {
+		_, err := q.ExecContext(ctx, "INSERT INTO "+owner+"_genres ("+owner+"_id, position, tag) VALUES (?, ?, ?)", id, i, tag)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readContributors returns the contributors of the books or works with the
+// given ids, by id.
+func readContributors(ctx context.Context, q querier, owner string, ids []string) (map[string][]models.Contributor, error) {
+	w := new(where)
+	w.and(owner + "_id IN " + w.in(ids))
+	rows, err := q.QueryContext(ctx, "SELECT "+owner+"_id, author_id, role, ord FROM "+owner+"_contributors"+w.String()+" ORDER BY "+owner+"_id, position", w.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contributors := make(map[string][]models.Contributor, len(ids))
+	for rows.Next() {
+		var (
+			id, role string
+			c        models.Contributor
+		)
+		if err := rows.Scan(&id, &c.AuthorId, &role, &c.Order); err != nil {
+			return nil, err
+		}
+		c.Role = models.Role(role)
+		contributors[id] = append(contributors[id], c)
+	}
+	return contributors, rows.Err()
+}
+
+// readValues returns the values of column in the child table of the books
+// or works with the given ids, by id.
+func readValues(ctx context.Context, q querier, table, owner, column string, ids []string) (map[string][]string, error) {
+	w := new(where)
+	w.and(owner + "_id IN " + w.in(ids))
+	rows, err := q.QueryContext(ctx, "SELECT "+owner+"_id, "+column+" FROM "+table+w.String()+" ORDER BY "+owner+"_id, position", w.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[string][]string, len(ids))
+	for rows.Next() {
+		var id, v string
+		if err := rows.Scan(&id, &v); err != nil {
+			return nil, err
+		}
+		values[id] = append(values[id], v)
+	}
+	return values, rows.Err()
+}
@@ -0,0 +1,141 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/literalog/library/internal/app/domain/genre"
+	"github.com/literalog/library/pkg/models"
+)
+
+const genreColumns = "id, version, created_at, updated_at, tag"
+
+var genreSort = map[string]string{"tag": "tag"}
+
+type GenreRepository struct {
+	db *sql.DB
+}
+
+func NewGenreRepository(db *sql.DB) genre.Repository {
+	return &GenreRepository{
+		db: db,
+	}
+}
+
+func scanGenre(row row) (models.Genre, error) {
+	var g models.Genre
+	err := row.Scan(&g.Id, &g.Version, stamp{&g.CreatedAt}, stamp{&g.UpdatedAt}, &g.Tag)
+	return g, err
+}
+
+func (r *GenreRepository) Create(ctx context.Context, g *models.Genre) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO genres ("+genreColumns+") VALUES (?, ?, ?, ?, ?)",
+		g.Id, g.Version, stamp{&g.CreatedAt}, stamp{&g.UpdatedAt}, g.Tag)
+	if msg, ok := unique(err); ok {
+		// SQLite names a single violated constraint, the tag's even when
+		// the id is taken too, so a taken id is looked up.
+		if strings.Contains(msg, "genres.tag") && !r.exists(ctx, g.Id) {
+			return genre.ErrDuplicateTag
+		}
+		return genre.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error creating genre: %w", err)
+	}
+	return nil
+}
+
+// exists reports whether a genre with the given id is stored.
+func (r *GenreRepository) exists(ctx context.Context, id string) bool {
+	var exists bool
+	r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM genres WHERE id = ?)", id).Scan(&exists)
+	return exists
+}
+
+func (r *GenreRepository) Update(ctx context.Context, g *models.Genre) error {
+	next := g.Meta.Next()
+	res, err := r.db.ExecContext(ctx, "UPDATE genres SET version = ?, updated_at = ?, tag = ? WHERE id = ? AND version = ?",
+		next.Version, stamp{&next.UpdatedAt}, g.Tag, g.Id, g.Version)
+	if _, ok := unique(err); ok {
+		return genre.ErrDuplicateTag
+	}
+	if err != nil {
+		return fmt.Errorf("error updating genre: %w", err)
+	}
+	if !changed(res) {
+		return stale(ctx, r.db, "genres", g.Id, genre.ErrNotFound, genre.ErrVersionMismatch)
+	}
+
+	g.Meta = next
+	return nil
+}
+
+func (r *GenreRepository) Delete(ctx context.Context, id string, version int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM genres WHERE id = ?1 AND (?2 = 0 OR version = ?2)", id, version)
+	if err != nil {
+		return fmt.Errorf("error deleting genre: %w", err)
+	}
+	if !changed(res) {
+		return stale(ctx, r.db, "genres", id, genre.ErrNotFound, genre.ErrVersionMismatch)
+	}
+	return nil
+}
+
+func (r *GenreRepository) GetById(ctx context.Context, id string) (*models.Genre, error) {
+	g, err := scanGenre(r.db.QueryRowContext(ctx, "SELECT "+genreColumns+" FROM genres WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, genre.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting genre: %w", err)
+	}
+	return &g, nil
+}
+
+func (r *GenreRepository) GetByName(ctx context.Context, name string) (*models.Genre, error) {
+	g, err := scanGenre(r.db.QueryRowContext(ctx, "SELECT "+genreColumns+" FROM genres WHERE tag = ? ORDER BY seq LIMIT 1", name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, genre.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting genre: %w", err)
+	}
+	return &g, nil
+}
+
+func (r *GenreRepository) GetByTags(ctx context.Context, tags []string) ([]models.Genre, error) {
+	w := new(where)
+	w.and("tag IN " + w.in(tags))
+	rows, err := r.db.QueryContext(ctx, "SELECT "+genreColumns+" FROM genres"+w.String()+" ORDER BY seq", w.args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting genres: %w", err)
+	}
+	gg, err := collect(rows, scanGenre)
+	if err != nil {
+		return nil, fmt.Errorf("error getting genres: %w", err)
+	}
+	return gg, nil
+}
+
+func (r *GenreRepository) GetAll(ctx context.Context) ([]models.Genre, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+genreColumns+" FROM genres ORDER BY seq")
+	if err != nil {
+		return nil, fmt.Errorf("error getting genres: %w", err)
+	}
+	gg, err := collect(rows, scanGenre)
+	if err != nil {
+		return nil, fmt.Errorf("error getting genres: %w", err)
+	}
+	return gg, nil
+}
+
+func (r *GenreRepository) List(ctx context.Context, q genre.Query) ([]models.Genre, int64, error) {
+	w := new(where)
+	if q.Tag != "" {
+		w.containsFold("tag", q.Tag)
+	}
+	return list(ctx, r.db, "genres", genreColumns, w, q.ListOptions, genreSort, scanGenre)
+}
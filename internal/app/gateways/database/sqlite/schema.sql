@@ -0,0 +1,159 @@
+-- Entities keep their uuid in id; seq, the rowid, orders them by insertion.
+-- Times are milliseconds since the epoch.
+
+CREATE TABLE authors (
+    seq        INTEGER PRIMARY KEY,
+    id         TEXT NOT NULL UNIQUE,
+    version    INTEGER NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    name       TEXT NOT NULL
+);
+
+CREATE TABLE series (
+    seq        INTEGER PRIMARY KEY,
+    id         TEXT NOT NULL UNIQUE,
+    version    INTEGER NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    name       TEXT NOT NULL
+);
+
+CREATE TABLE genres (
+    seq        INTEGER PRIMARY KEY,
+    id         TEXT NOT NULL UNIQUE,
+    version    INTEGER NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    tag        TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX genres_tag ON genres (tag);
+
+CREATE TABLE works (
+    seq        INTEGER PRIMARY KEY,
+    id         TEXT NOT NULL UNIQUE,
+    version    INTEGER NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    title      TEXT NOT NULL,
+    series_id  TEXT,
+    series_no  INTEGER NOT NULL DEFAULT 0,
+    blurb      TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX works_series ON works (series_id);
+
+CREATE TABLE work_contributors (
+    work_id   TEXT NOT NULL REFERENCES works (id) ON DELETE CASCADE,
+    position  INTEGER NOT NULL,
+    author_id TEXT NOT NULL,
+    role      TEXT NOT NULL,
+    ord       INTEGER NOT NULL,
+    PRIMARY KEY (work_id, position)
+);
+
+CREATE TABLE work_genres (
+    work_id  TEXT NOT NULL REFERENCES works (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    tag      TEXT NOT NULL,
+    PRIMARY KEY (work_id, position)
+);
+
+CREATE TABLE books (
+    seq        INTEGER PRIMARY KEY,
+    id         TEXT NOT NULL UNIQUE,
+    version    INTEGER NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    work_id    TEXT,
+    title      TEXT NOT NULL,
+    author_id  TEXT REFERENCES authors (id),
+    series_id  TEXT REFERENCES series (id),
+    series_no  INTEGER NOT NULL DEFAULT 0,
+    year       INTEGER NOT NULL DEFAULT 0,
+    publisher  TEXT NOT NULL DEFAULT '',
+    language   TEXT NOT NULL DEFAULT '',
+    format     TEXT NOT NULL DEFAULT '',
+    pages_no   INTEGER NOT NULL DEFAULT 0,
+    hours_no   INTEGER NOT NULL DEFAULT 0,
+    blurb      TEXT NOT NULL DEFAULT '',
+    cover      TEXT NOT NULL DEFAULT '',
+    not_a_book INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX books_author ON books (author_id);
+CREATE INDEX books_series ON books (series_id, series_no);
+CREATE INDEX books_work ON books (work_id);
+
+CREATE TABLE book_contributors (
+    book_id   TEXT NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    position  INTEGER NOT NULL,
+    author_id TEXT NOT NULL REFERENCES authors (id),
+    role      TEXT NOT NULL,
+    ord       INTEGER NOT NULL,
+    PRIMARY KEY (book_id, position)
+);
+
+CREATE INDEX book_contributors_author ON book_contributors (author_id, role);
+
+CREATE TABLE book_isbns (
+    isbn     TEXT PRIMARY KEY,
+    book_id  TEXT NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL
+);
+
+CREATE INDEX book_isbns_book ON book_isbns (book_id);
+
+CREATE TABLE book_genres (
+    book_id  TEXT NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    tag      TEXT NOT NULL,
+    PRIMARY KEY (book_id, position)
+);
+
+CREATE INDEX book_genres_tag ON book_genres (tag);
+
+-- books_fts indexes the titles and blurbs of books, kept in sync by the
+-- triggers below. Its rowid is the seq of the book.
+CREATE VIRTUAL TABLE books_fts USING fts5 (
+    title,
+    blurb,
+    content = 'books',
+    content_rowid = 'seq',
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER books_fts_insert AFTER INSERT ON books BEGIN
+    INSERT INTO books_fts (rowid, title, blurb) VALUES (new.seq, new.title, new.blurb);
+END;
+
+CREATE TRIGGER books_fts_delete AFTER DELETE ON books BEGIN
+    INSERT INTO books_fts (books_fts, rowid, title, blurb) VALUES ('delete', old.seq, old.title, old.blurb);
+END;
+
+CREATE TRIGGER books_fts_update AFTER UPDATE OF title, blurb ON books BEGIN
+    INSERT INTO books_fts (books_fts, rowid, title, blurb) VALUES ('delete', old.seq, old.title, old.blurb);
+    INSERT INTO books_fts (rowid, title, blurb) VALUES (new.seq, new.title, new.blurb);
+END;
+
+CREATE TABLE author_aliases (
+    id     TEXT PRIMARY KEY,
+    target TEXT NOT NULL
+);
+
+CREATE INDEX author_aliases_target ON author_aliases (target);
+
+CREATE TABLE series_aliases (
+    id     TEXT PRIMARY KEY,
+    target TEXT NOT NULL
+);
+
+CREATE INDEX series_aliases_target ON series_aliases (target);
+
+CREATE TABLE genre_aliases (
+    id     TEXT PRIMARY KEY,
+    target TEXT NOT NULL
+);
+
+CREATE INDEX genre_aliases_target ON genre_aliases (target);
@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/literalog/library/internal/app/domain/series"
+	"github.com/literalog/library/pkg/models"
+)
+
+const seriesColumns = "id, version, created_at, updated_at, name"
+
+var seriesSort = map[string]string{"name": "name"}
+
+type SeriesRepository struct {
+	db *sql.DB
+}
+
+func NewSeriesRepository(db *sql.DB) series.Repository {
+	return &SeriesRepository{
+		db: db,
+	}
+}
+
+func scanSeries(row row) (models.Series, error) {
+	var s models.Series
+	err := row.Scan(&s.Id, &s.Version, stamp{&s.CreatedAt}, stamp{&s.UpdatedAt}, &s.Name)
+	return s, err
+}
+
+func (r *SeriesRepository) Create(ctx context.Context, s *models.Series) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO series ("+seriesColumns+") VALUES (?, ?, ?, ?, ?)",
+		s.Id, s.Version, stamp{&s.CreatedAt}, stamp{&s.UpdatedAt}, s.Name)
+	if _, ok := unique(err); ok {
+		return series.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error creating series: %w", err)
+	}
+	return nil
+}
+
+func (r *SeriesRepository) Update(ctx context.Context, s *models.Series) error {
+	next := s.Meta.Next()
+	res, err := r.db.ExecContext(ctx, "UPDATE series SET version = ?, updated_at = ?, name = ? WHERE id = ? AND version = ?",
+		next.Version, stamp{&next.UpdatedAt}, s.Name, s.Id, s.Version)
+	if err != nil {
+		return fmt.Errorf("error updating series: %w", err)
+	}
+	if !changed(res) {
+		return stale(ctx, r.db, "series", s.Id, series.ErrNotFound, series.ErrVersionMismatch)
+	}
+
+	s.Meta = next
+	return nil
+}
+
+func (r *SeriesRepository) Delete(ctx context.Context, id string, version int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM series WHERE id = ?1 AND (?2 = 0 OR version = ?2)", id, version)
+	if _, ok := violates(err, foreignKeyViolation); ok {
+		return series.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting series: %w", err)
+	}
+	if !changed(res) {
+		return stale(ctx, r.db, "series", id, series.ErrNotFound, series.ErrVersionMismatch)
+	}
+	return nil
+}
+
+func (r *SeriesRepository) GetById(ctx context.Context, id string) (*models.Series, error) {
+	s, err := scanSeries(r.db.QueryRowContext(ctx, "SELECT "+seriesColumns+" FROM series WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, series.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting series: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *SeriesRepository) GetAll(ctx context.Context) ([]models.Series, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+seriesColumns+" FROM series ORDER BY seq")
+	if err != nil {
+		return nil, fmt.Errorf("error getting series: %w", err)
+	}
+	ss, err := collect(rows, scanSeries)
+	if err != nil {
+		return nil, fmt.Errorf("error getting series: %w", err)
+	}
+	return ss, nil
+}
+
+func (r *SeriesRepository) List(ctx context.Context, q series.Query) ([]models.Series, int64, error) {
+	w := new(where)
+	if q.Name != "" {
+		w.containsFold("name", q.Name)
+	}
+	return list(ctx, r.db, "series", seriesColumns, w, q.ListOptions, seriesSort, scanSeries)
+}
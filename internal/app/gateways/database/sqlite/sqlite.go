@@ -0,0 +1,314 @@
+// Package sqlite stores the catalog in a single SQLite file, for
+// deployments that do not want to run a database server. The driver is pure
+// Go, so the binary needs no C library, and the schema is created when the
+// file is first opened.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/literalog/library/pkg/models"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+//go:embed schema.sql
+var schema string
+
+// schemaVersion is the PRAGMA user_version of a file holding the current
+// schema.
+const schemaVersion = 1
+
+type Storage struct {
+	DB *sql.DB
+}
+
+// NewStorage opens the database file at path, creating it and its schema
+// if missing. Every connection writes ahead to a log, so reads go on while
+// a write is in progress, enforces foreign keys and waits for the lock
+// rather than failing when another connection is writing.
+func NewStorage(ctx context.Context, path string) (*Storage, error) {
+	dsn := "file:" + path + "?" + url.Values{
+		"_pragma": {"journal_mode(WAL)", "foreign_keys(1)", "busy_timeout(5000)", "synchronous(NORMAL)"},
+		"_txlock": {"immediate"},
+	}.Encode()
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite: %w", err)
+	}
+
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Storage{
+		DB: db,
+	}, nil
+}
+
+// migrate creates the schema of a new file. A file holding a schema newer
+// than this binary knows is refused.
+func migrate(ctx context.Context, db *sql.DB) error {
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to open sqlite: %w", err)
+	}
+
+	switch {
+	case version == schemaVersion:
+		return nil
+	case version > schemaVersion:
+		return fmt.Errorf("sqlite schema version %d is newer than the supported %d", version, schemaVersion)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("error creating sqlite schema: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", schemaVersion)); err != nil {
+		return fmt.Errorf("error creating sqlite schema: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (s *Storage) Name() string {
+	return "sqlite"
+}
+
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.DB.PingContext(ctx)
+}
+
+func (s *Storage) Close(ctx context.Context) error {
+	return s.DB.Close()
+}
+
+// querier runs statements on the database or within a transaction.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// row is a single row or the current one of several.
+type row interface {
+	Scan(dest ...any) error
+}
+
+// SQLite names the table and columns of a failed UNIQUE constraint in the
+// message, but not the parent of a failed foreign key.
+const (
+	uniqueViolation     = sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	primaryKeyViolation = sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	foreignKeyViolation = sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+)
+
+// violates reports whether err is a violation of the given kind, and if so
+// the message naming what was violated.
+func violates(err error, code int) (msg string, ok bool) {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code() == code {
+		return sqliteErr.Error(), true
+	}
+	return "", false
+}
+
+// unique reports whether err is a violation of a UNIQUE or PRIMARY KEY
+// constraint, and if so the message naming the columns.
+func unique(err error) (msg string, ok bool) {
+	if msg, ok := violates(err, uniqueViolation); ok {
+		return msg, true
+	}
+	return violates(err, primaryKeyViolation)
+}
+
+// nullable stores empty optional references as NULL, which foreign keys
+// accept.
+func nullable(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// stamp stores a time as milliseconds since the epoch, the precision the
+// entities keep, and reads it back in UTC.
+type stamp struct {
+	t *time.Time
+}
+
+func (s stamp) Value() (driver.Value, error) {
+	return s.t.UnixMilli(), nil
+}
+
+func (s stamp) Scan(v any) error {
+	ms, ok := v.(int64)
+	if !ok {
+		return fmt.Errorf("cannot read %T as a time", v)
+	}
+	*s.t = time.UnixMilli(ms).UTC()
+	return nil
+}
+
+// collect scans every row with scan.
+func collect[T any](rows *sql.Rows, scan func(row row) (T, error)) ([]T, error) {
+	defer rows.Close()
+
+	items := make([]T, 0)
+	for rows.Next() {
+		v, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	return items, rows.Err()
+}
+
+// errStale aborts the transaction of an update that changed nothing, to be
+// told apart by stale once rolled back.
+var errStale = errors.New("stale update")
+
+// stale tells why an update or a delete of the row with the given id in
+// table changed nothing: it returns notFound when the row is missing and
+// versionMismatch when its version changed.
+func stale(ctx context.Context, q querier, table, id string, notFound, versionMismatch error) error {
+	var exists bool
+	err := q.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = ?)", id).Scan(&exists)
+	switch {
+	case err != nil:
+		return err
+	case !exists:
+		return notFound
+	default:
+		return versionMismatch
+	}
+}
+
+// changed reports whether res changed any row. SQLite always counts the
+// rows a statement changed, so the count cannot fail.
+func changed(res sql.Result) bool {
+	n, _ := res.RowsAffected()
+	return n > 0
+}
+
+// inTx runs fn in a transaction, committed if fn succeeds. Transactions
+// take the write lock as they begin, so two of them never deadlock
+// upgrading a read lock.
+func inTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// where builds the WHERE clause of a list query along with its arguments.
+type where struct {
+	conds []string
+	args  []any
+}
+
+// arg adds an argument and returns its placeholder.
+func (w *where) arg(v any) string {
+	w.args = append(w.args, v)
+	return fmt.Sprintf("?%d", len(w.args))
+}
+
+// in adds the values as arguments and returns an IN list of their
+// placeholders.
+func (w *where) in(values []string) string {
+	if len(values) == 0 {
+		return "(NULL)"
+	}
+	placeholders := make([]string, len(values))
+	for i, v := range values {
+		placeholders[i] = w.arg(v)
+	}
+	return "(" + strings.Join(placeholders, ", ") + ")"
+}
+
+func (w *where) and(cond string) {
+	w.conds = append(w.conds, cond)
+}
+
+// containsFold matches column against s anywhere, ignoring case.
+func (w *where) containsFold(column, s string) {
+	pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+	w.and(column + " LIKE " + w.arg(pattern) + ` ESCAPE '\'`)
+}
+
+func (w *where) String() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conds, " AND ")
+}
+
+// page returns the ORDER BY, LIMIT and OFFSET clauses of a list query,
+// adding the limit and offset to w. Only the fields in columns can be
+// sorted by; seq is appended as a tie breaker so pages are stable and
+// equal items keep their insertion order.
+func (w *where) page(opts models.ListOptions, columns map[string]string) string {
+	var order []string
+	for _, f := range opts.Sort {
+		column, ok := columns[f.Field]
+		if !ok {
+			continue
+		}
+		if f.Desc {
+			column += " DESC"
+		}
+		order = append(order, column)
+	}
+	clause := " ORDER BY " + strings.Join(append(order, "seq"), ", ")
+
+	// SQLite only takes an OFFSET after a LIMIT, where -1 is no limit.
+	limit := -1
+	if opts.Limit > 0 {
+		limit = opts.Limit
+	}
+	return clause + " LIMIT " + w.arg(limit) + " OFFSET " + w.arg(opts.Offset)
+}
+
+// list counts the rows of table matching w, then returns the requested page
+// of them.
+func list[T any](ctx context.Context, q querier, table, columns string, w *where, opts models.ListOptions, sortable map[string]string, scan func(row row) (T, error)) ([]T, int64, error) {
+	var total int64
+	if err := q.QueryRowContext(ctx, "SELECT count(*) FROM "+table+w.String(), w.args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error counting %s: %w", table, err)
+	}
+
+	query := "SELECT " + columns + " FROM " + table + w.String()
+	query += w.page(opts, sortable)
+	rows, err := q.QueryContext(ctx, query, w.args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting %s: %w", table, err)
+	}
+	items, err := collect(rows, scan)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting %s: %w", table, err)
+	}
+	return items, total, nil
+}
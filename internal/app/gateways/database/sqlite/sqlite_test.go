@@ -0,0 +1,218 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/literalog/library/internal/app/domain/author"
+	"github.com/literalog/library/internal/app/domain/book"
+	"github.com/literalog/library/internal/app/domain/genre"
+	"github.com/literalog/library/internal/app/domain/series"
+	"github.com/literalog/library/internal/app/domain/work"
+	"github.com/literalog/library/internal/app/gateways/database/repotest"
+	"github.com/literalog/library/internal/app/gateways/database/sqlite"
+	"github.com/literalog/library/pkg/models"
+)
+
+// open returns a database in a file of its own, closed once the test is
+// done.
+func open(t *testing.T) *sql.DB {
+	t.Helper()
+	s, err := sqlite.NewStorage(context.Background(), filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close(context.Background()) })
+	return s.DB
+}
+
+// seedingBooks stores the authors and series a book refers to before
+// writing it, as the suite writes books on their own and the foreign keys
+// would reject them.
+type seedingBooks struct {
+	book.Repository
+	db *sql.DB
+}
+
+func (r seedingBooks) seed(ctx context.Context, b *models.Book) error {
+	meta := models.NewMeta()
+	created := meta.CreatedAt.UnixMilli()
+	authors := []string{b.AuthorId}
+	for _, c := range b.Contributors {
+		authors = append(authors, c.AuthorId)
+	}
+	for _, id := range authors {
+		if id == "" {
+			continue
+		}
+		_, err := r.db.ExecContext(ctx, "INSERT INTO authors (id, version, created_at, updated_at, name) VALUES (?1, ?2, ?3, ?3, ?1) ON CONFLICT DO NOTHING",
+			id, meta.Version, created)
+		if err != nil {
+			return err
+		}
+	}
+	if b.SeriesId != "" {
+		_, err := r.db.ExecContext(ctx, "INSERT INTO series (id, version, created_at, updated_at, name) VALUES (?1, ?2, ?3, ?3, ?1) ON CONFLICT DO NOTHING",
+			b.SeriesId, meta.Version, created)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r seedingBooks) Create(ctx context.Context, b *models.Book) error {
+	if err := r.seed(ctx, b); err != nil {
+		return err
+	}
+	return r.Repository.Create(ctx, b)
+}
+
+func (r seedingBooks) Update(ctx context.Context, b *models.Book) error {
+	if err := r.seed(ctx, b); err != nil {
+		return err
+	}
+	return r.Repository.Update(ctx, b)
+}
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, repotest.Factories{
+		Authors: func(t *testing.T) author.Repository { return sqlite.NewAuthorRepository(open(t)) },
+		Series:  func(t *testing.T) series.Repository { return sqlite.NewSeriesRepository(open(t)) },
+		Genres:  func(t *testing.T) genre.Repository { return sqlite.NewGenreRepository(open(t)) },
+		Works:   func(t *testing.T) work.Repository { return sqlite.NewWorkRepository(open(t)) },
+		Books: func(t *testing.T) book.Repository {
+			db := open(t)
+			return seedingBooks{Repository: sqlite.NewBookRepository(db), db: db}
+		},
+	})
+}
+
+func TestStorage(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "library.db")
+
+	s, err := sqlite.NewStorage(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &models.Author{Id: "le-guin", Meta: models.NewMeta(), Name: "Ursula K. Le Guin"}
+	if err := sqlite.NewAuthorRepository(s.DB).Create(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	var mode string
+	var foreignKeys bool
+	if err := s.DB.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DB.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+		t.Fatal(err)
+	}
+	if mode != "wal" || !foreignKeys {
+		t.Errorf("got journal_mode %s and foreign_keys %v, want wal and true", mode, foreignKeys)
+	}
+	if err := s.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening keeps the data rather than creating the schema again.
+	s, err = sqlite.NewStorage(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close(ctx)
+	if _, err := sqlite.NewAuthorRepository(s.DB).GetById(ctx, a.Id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBookReferences(t *testing.T) {
+	ctx := context.Background()
+	db := open(t)
+	authors := sqlite.NewAuthorRepository(db)
+	books := sqlite.NewBookRepository(db)
+
+	b := &models.Book{Id: uuid.NewString(), Meta: models.NewMeta(), Title: "A Wizard of Earthsea", AuthorId: "le-guin"}
+	if err := books.Create(ctx, b); !errors.Is(err, book.ErrAuthorNotFound) {
+		t.Fatalf("creating a book of a missing author: got %v, want %v", err, book.ErrAuthorNotFound)
+	}
+
+	a := &models.Author{Id: "le-guin", Meta: models.NewMeta(), Name: "Ursula K. Le Guin"}
+	if err := authors.Create(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if err := books.Create(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	b.SeriesId = "earthsea"
+	if err := books.Update(ctx, b); !errors.Is(err, book.ErrSeriesNotFound) {
+		t.Fatalf("updating a book to a missing series: got %v, want %v", err, book.ErrSeriesNotFound)
+	}
+
+	if err := authors.Delete(ctx, a.Id, 0); !errors.Is(err, author.ErrConflict) {
+		t.Fatalf("deleting an author of a book: got %v, want %v", err, author.ErrConflict)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	books := sqlite.NewBookRepository(open(t)).(*sqlite.BookRepository)
+
+	create := func(title, blurb string) *models.Book {
+		b := &models.Book{Id: uuid.NewString(), Meta: models.NewMeta(), Title: title, Blurb: blurb}
+		if err := books.Create(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	wizard := create("A Wizard of Earthsea", "Ged, a young mage, unleashes a shadow.")
+	tombs := create("The Tombs of Atuan", "Tenar, priestess of the Nameless Ones, meets a wizard.")
+	create("Parable of the Sower", "Lauren Olamina flees a collapsing California.")
+
+	search := func(text string) []string {
+		t.Helper()
+		opts := models.ListOptions{Limit: models.MaxLimit, Sort: []models.SortField{{Field: "title"}}}
+		bb, total, err := books.List(ctx, book.Query{ListOptions: opts, Text: text})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != int64(len(bb)) {
+			t.Errorf("search %q: got total %d for %d books", text, total, len(bb))
+		}
+		ids := make([]string, len(bb))
+		for i, b := range bb {
+			ids[i] = b.Id
+		}
+		return ids
+	}
+
+	if got := search("WIZARD"); len(got) != 2 || got[0] != wizard.Id {
+		t.Errorf("search wizard: got %v, want %s first of 2", got, wizard.Id)
+	}
+	if got := search("priestess atuan"); len(got) != 1 || got[0] != tombs.Id {
+		t.Errorf("search priestess atuan: got %v, want %s", got, tombs.Id)
+	}
+	if got := search(`"nameless" OR (`); len(got) != 0 {
+		t.Errorf("query syntax should be matched as words: got %v", got)
+	}
+
+	tombs.Blurb = "Tenar guards the labyrinth."
+	if err := books.Update(ctx, tombs); err != nil {
+		t.Fatal(err)
+	}
+	if got := search("wizard"); len(got) != 1 {
+		t.Errorf("search wizard after an update: got %v, want 1 book", got)
+	}
+	if err := books.Delete(ctx, wizard.Id, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := search("wizard"); len(got) != 0 {
+		t.Errorf("search wizard after a delete: got %v, want none", got)
+	}
+}
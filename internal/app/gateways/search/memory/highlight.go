@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"html"
+	"slices"
+	"strings"
+)
+
+const (
+	// snippetLength is the rough number of bytes of text around the first
+	// match kept in a snippet.
+	snippetLength = 160
+
+	markStart = "<mark>"
+	markEnd   = "</mark>"
+	ellipsis  = "…"
+)
+
+// highlight returns snippets of the fields of the document with key k that
+// matched, and of the referenced documents that matched in its place.
+func (x *Index) highlight(k key, m *match) map[string]string {
+	highlights := make(map[string]string)
+
+	for _, f := range x.docs[k].doc.Fields {
+		if s, ok := snippet(f.Text, m.terms); ok {
+			if prev, ok := highlights[f.Name]; ok {
+				s = prev + " " + s
+			}
+			highlights[f.Name] = s
+		}
+	}
+
+	refs := make([]key, 0, len(m.refs))
+	for rk := range m.refs {
+		refs = append(refs, rk)
+	}
+	slices.SortFunc(refs, func(a, b key) int {
+		return strings.Compare(string(a.kind)+a.id, string(b.kind)+b.id)
+	})
+
+	for _, rk := range refs {
+		ref, ok := x.docs[rk]
+		if !ok {
+			continue
+		}
+		for _, f := range ref.doc.Fields {
+			if s, ok := snippet(f.Text, m.refs[rk].terms); ok {
+				name := string(rk.kind)
+				if prev, ok := highlights[name]; ok {
+					s = prev + ", " + s
+				}
+				highlights[name] = s
+				break
+			}
+		}
+	}
+
+	if len(highlights) == 0 {
+		return nil
+	}
+	return highlights
+}
+
+// snippet escapes text for HTML and marks the tokens whose term is in terms,
+// cutting the text around the first of them when it is long. It reports
+// false when nothing matched.
+func snippet(text string, terms map[string]bool) (string, bool) {
+	tokens := tokenize(text)
+
+	var marked []token
+	for _, t := range tokens {
+		if terms[t.term] {
+			marked = append(marked, t)
+		}
+	}
+	if len(marked) == 0 {
+		return "", false
+	}
+
+	from, to := 0, len(text)
+	if len(text) > snippetLength {
+		from = max(0, marked[0].start-snippetLength/3)
+		to = min(len(text), from+snippetLength)
+		// Cut on word boundaries.
+		for _, t := range tokens {
+			if t.start < from && t.end > from {
+				from = t.start
+			}
+			if t.start < to && t.end > to {
+				to = t.end
+			}
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString(ellipsis)
+	}
+	pos := from
+	for _, t := range marked {
+		if t.start < from || t.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:t.start]))
+		b.WriteString(markStart)
+		b.WriteString(html.EscapeString(text[t.start:t.end]))
+		b.WriteString(markEnd)
+		pos = t.end
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString(ellipsis)
+	}
+	return strings.TrimSpace(b.String()), true
+}
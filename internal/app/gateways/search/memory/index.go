@@ -0,0 +1,271 @@
+// Package memory holds in-process indexes for search and suggestions.
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/literalog/library/internal/app/domain/search"
+)
+
+const (
+	// Factors applied to the weight of a match by how the query term
+	// matched the indexed term.
+	exactMatch  = 1.0
+	prefixMatch = 0.7
+	typoMatch   = 0.5
+
+	// refMatch scales matches found in a referenced document, such as the
+	// author of a book.
+	refMatch = 0.5
+
+	// minPrefix is the shortest query term matching longer terms by prefix.
+	minPrefix = 3
+)
+
+type key struct {
+	kind search.Kind
+	id   string
+}
+
+type entry struct {
+	doc   search.Document
+	terms []string
+}
+
+// Index keeps, for every term, the documents containing it. It is safe for
+// concurrent use.
+type Index struct {
+	mu sync.RWMutex
+	// docs holds every indexed document.
+	docs map[key]*entry
+	// postings maps a term to the documents containing it, with the weight
+	// of its occurrences.
+	postings map[string]map[key]float64
+	// referrers maps a document to the documents referring to it.
+	referrers map[key]map[key]bool
+}
+
+func NewIndex() *Index {
+	return &Index{
+		docs:      make(map[key]*entry),
+		postings:  make(map[string]map[key]float64),
+		referrers: make(map[key]map[key]bool),
+	}
+}
+
+func (x *Index) Put(ctx context.Context, doc search.Document) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	k := key{doc.Kind, doc.Id}
+	x.remove(k)
+
+	weights := make(map[string]float64)
+	for _, f := range doc.Fields {
+		for _, t := range tokenize(f.Text) {
+			weights[t.term] += f.Weight
+		}
+	}
+
+	e := &entry{doc: doc}
+	for term, w := range weights {
+		p, ok := x.postings[term]
+		if !ok {
+			p = make(map[key]float64)
+			x.postings[term] = p
+		}
+		p[k] = w
+		e.terms = append(e.terms, term)
+	}
+	for _, ref := range doc.Refs {
+		rk := key{ref.Kind, ref.Id}
+		if x.referrers[rk] == nil {
+			x.referrers[rk] = make(map[key]bool)
+		}
+		x.referrers[rk][k] = true
+	}
+	x.docs[k] = e
+	return nil
+}
+
+func (x *Index) Remove(ctx context.Context, kind search.Kind, id string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.remove(key{kind, id})
+	return nil
+}
+
+func (x *Index) remove(k key) {
+	e, ok := x.docs[k]
+	if !ok {
+		return
+	}
+
+	for _, term := range e.terms {
+		delete(x.postings[term], k)
+		if len(x.postings[term]) == 0 {
+			delete(x.postings, term)
+		}
+	}
+	for _, ref := range e.doc.Refs {
+		rk := key{ref.Kind, ref.Id}
+		delete(x.referrers[rk], k)
+		if len(x.referrers[rk]) == 0 {
+			delete(x.referrers, rk)
+		}
+	}
+	delete(x.docs, k)
+}
+
+// match is how a document matched the query.
+type match struct {
+	score float64
+	// covered counts the query terms the document matched.
+	covered int
+	// terms are the indexed terms it matched directly, to highlight.
+	terms map[string]bool
+	// refs are the referenced documents that matched in its place.
+	refs map[key]*match
+}
+
+// Search ranks the documents by how many query terms they match, then by
+// score. When some documents match every term only those are returned.
+func (x *Index) Search(ctx context.Context, q search.Query) ([]search.Hit, int64, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	qterms := terms(q.Text)
+	matches := make(map[key]*match)
+	get := func(k key) *match {
+		m, ok := matches[k]
+		if !ok {
+			m = &match{terms: make(map[string]bool), refs: make(map[key]*match)}
+			matches[k] = m
+		}
+		return m
+	}
+
+	for _, qt := range qterms {
+		// The best score of each document for this query term.
+		scores := make(map[key]float64)
+		for term, factor := range x.expand(qt) {
+			for k, w := range x.postings[term] {
+				scores[k] = max(scores[k], w*factor)
+				get(k).terms[term] = true
+			}
+		}
+
+		direct := make([]key, 0, len(scores))
+		for k := range scores {
+			direct = append(direct, k)
+		}
+		for _, k := range direct {
+			for r := range x.referrers[k] {
+				if _, ok := x.docs[r]; !ok {
+					continue
+				}
+				if s := scores[k] * refMatch; s > scores[r] {
+					scores[r] = s
+				}
+				get(r).refs[k] = get(k)
+			}
+		}
+
+		for k, s := range scores {
+			m := get(k)
+			m.score += s
+			m.covered++
+		}
+	}
+
+	all := false
+	for _, m := range matches {
+		all = all || m.covered == len(qterms)
+	}
+
+	type result struct {
+		key   key
+		title string
+		match *match
+	}
+	var results []result
+	for k, m := range matches {
+		if all && m.covered < len(qterms) {
+			continue
+		}
+		if len(q.Kinds) > 0 && !slices.Contains(q.Kinds, k.kind) {
+			continue
+		}
+		if e, ok := x.docs[k]; ok {
+			results = append(results, result{k, e.doc.Title, m})
+		}
+	}
+
+	slices.SortFunc(results, func(a, b result) int {
+		switch {
+		case a.match.covered != b.match.covered:
+			return b.match.covered - a.match.covered
+		case a.match.score > b.match.score:
+			return -1
+		case a.match.score < b.match.score:
+			return 1
+		default:
+			return strings.Compare(a.title, b.title)
+		}
+	})
+
+	total := int64(len(results))
+	results = paginate(results, q.Offset, q.Limit)
+
+	hits := make([]search.Hit, len(results))
+	for i, r := range results {
+		hits[i] = search.Hit{
+			Kind:       r.key.kind,
+			Id:         r.key.id,
+			Title:      r.title,
+			Score:      r.match.score,
+			Highlights: x.highlight(r.key, r.match),
+		}
+	}
+	return hits, total, nil
+}
+
+// expand returns the indexed terms a query term matches, with the factor of
+// each match.
+func (x *Index) expand(qt string) map[string]float64 {
+	expanded := make(map[string]float64)
+	if _, ok := x.postings[qt]; ok {
+		expanded[qt] = exactMatch
+	}
+
+	typos := maxTypos(qt)
+	for term := range x.postings {
+		if term == qt {
+			continue
+		}
+		switch {
+		case len(qt) >= minPrefix && strings.HasPrefix(term, qt):
+			expanded[term] = prefixMatch
+		case typos > 0:
+			if d := distance(qt, term, typos); d <= typos {
+				expanded[term] = typoMatch / float64(d)
+			}
+		}
+	}
+	return expanded
+}
+
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
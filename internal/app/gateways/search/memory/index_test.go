@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/literalog/library/internal/app/domain/search"
+)
+
+func newTestIndex(t *testing.T) *Index {
+	t.Helper()
+
+	docs := []search.Document{
+		{Kind: search.KindAuthor, Id: "a1", Title: "Brandon Sanderson", Fields: []search.Field{{Name: "name", Text: "Brandon Sanderson", Weight: 3}}},
+		{Kind: search.KindAuthor, Id: "a2", Title: "Émile Zola", Fields: []search.Field{{Name: "name", Text: "Émile Zola", Weight: 3}}},
+		{Kind: search.KindSeries, Id: "s1", Title: "Mistborn", Fields: []search.Field{{Name: "name", Text: "Mistborn", Weight: 3}}},
+		{Kind: search.KindBook, Id: "b1", Title: "The Final Empire", Fields: []search.Field{
+			{Name: "title", Text: "The Final Empire", Weight: 3},
+			{Name: "blurb", Text: "In a world where ash falls from the sky, a crew of thieves plots to overthrow the Lord Ruler.", Weight: 1},
+		}, Refs: []search.Ref{{Kind: search.KindAuthor, Id: "a1"}, {Kind: search.KindSeries, Id: "s1"}}},
+		{Kind: search.KindBook, Id: "b2", Title: "Elantris", Fields: []search.Field{{Name: "title", Text: "Elantris", Weight: 3}},
+			Refs: []search.Ref{{Kind: search.KindAuthor, Id: "a1"}}},
+		{Kind: search.KindBook, Id: "b3", Title: "Germinal", Fields: []search.Field{{Name: "title", Text: "Germinal", Weight: 3}},
+			Refs: []search.Ref{{Kind: search.KindAuthor, Id: "a2"}}},
+	}
+
+	x := NewIndex()
+	for _, d := range docs {
+		if err := x.Put(context.Background(), d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return x
+}
+
+func ids(hits []search.Hit) []string {
+	var ids []string
+	for _, h := range hits {
+		ids = append(ids, h.Id)
+	}
+	return ids
+}
+
+func TestSearch(t *testing.T) {
+	x := newTestIndex(t)
+
+	tests := []struct {
+		name  string
+		text  string
+		kinds []search.Kind
+		want  []string
+	}{
+		{"across author and series", "mistborn sanderson", nil, []string{"b1"}},
+		{"diacritics", "emile", nil, []string{"a2", "b3"}},
+		{"diacritics in the query", "zolà", nil, []string{"a2", "b3"}},
+		{"typo", "sandersen", []search.Kind{search.KindAuthor}, []string{"a1"}},
+		{"transposition", "elantirs", nil, []string{"b2"}},
+		{"prefix", "germ", nil, []string{"b3"}},
+		{"blurb", "thieves ash", nil, []string{"b1"}},
+		{"kind filter", "sanderson", []search.Kind{search.KindBook}, []string{"b2", "b1"}},
+		{"no match", "dune", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, total, err := x.Search(context.Background(), search.Query{Text: tt.text, Kinds: tt.kinds})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ids(hits)
+			if len(got) != len(tt.want) || total != int64(len(tt.want)) {
+				t.Fatalf("got %v (total %d), want %v", got, total, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchRanksFullMatchesFirst(t *testing.T) {
+	x := newTestIndex(t)
+
+	hits, _, err := x.Search(context.Background(), search.Query{Text: "brandon elantris"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].Id != "b2" {
+		t.Errorf("got %v, want only b2", ids(hits))
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	x := newTestIndex(t)
+
+	hits, _, err := x.Search(context.Background(), search.Query{Text: "mistborn sanderson ash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 {
+		t.Fatalf("got %v, want b1", ids(hits))
+	}
+
+	h := hits[0].Highlights
+	want := map[string]string{
+		"author": "Brandon <mark>Sanderson</mark>",
+		"series": "<mark>Mistborn</mark>",
+		"blurb":  "In a world where <mark>ash</mark> falls from the sky, a crew of thieves plots to overthrow the Lord Ruler.",
+	}
+	for field, snippet := range want {
+		if h[field] != snippet {
+			t.Errorf("highlight %s: got %q, want %q", field, h[field], snippet)
+		}
+	}
+	if _, ok := h["title"]; ok {
+		t.Errorf("title highlighted without a match: %q", h["title"])
+	}
+}
+
+func TestSnippetEscapesAndCuts(t *testing.T) {
+	text := "<b>Bold</b> " + strings.Repeat("filler ", 40) + "needle " + strings.Repeat("filler ", 40)
+
+	s, ok := snippet(text, map[string]bool{"needle": true})
+	if !ok {
+		t.Fatal("no match")
+	}
+	if s[:len(ellipsis)] != ellipsis || s[len(s)-len(ellipsis):] != ellipsis {
+		t.Errorf("long text not cut: %q", s)
+	}
+	if len(s) > snippetLength+2*len(ellipsis)+len(markStart)+len(markEnd)+16 {
+		t.Errorf("snippet too long: %d bytes", len(s))
+	}
+
+	s, _ = snippet("<b>Bold</b> move", map[string]bool{"bold": true})
+	if s != "&lt;b&gt;<mark>Bold</mark>&lt;/b&gt; move" {
+		t.Errorf("got %q", s)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	x := newTestIndex(t)
+	ctx := context.Background()
+
+	if err := x.Remove(ctx, search.KindBook, "b2"); err != nil {
+		t.Fatal(err)
+	}
+	hits, _, _ := x.Search(ctx, search.Query{Text: "elantris"})
+	if len(hits) != 0 {
+		t.Errorf("removed book found: %v", ids(hits))
+	}
+
+	// Replacing a document drops its old terms.
+	x.Put(ctx, search.Document{Kind: search.KindBook, Id: "b3", Title: "L'Assommoir", Fields: []search.Field{{Name: "title", Text: "L'Assommoir", Weight: 3}}})
+	hits, _, _ = x.Search(ctx, search.Query{Text: "germinal"})
+	if len(hits) != 0 {
+		t.Errorf("old title found: %v", ids(hits))
+	}
+	if len(x.referrers[key{search.KindAuthor, "a2"}]) != 0 {
+		t.Error("old reference kept")
+	}
+}
@@ -0,0 +1,215 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/literalog/library/internal/app/domain/search"
+	"github.com/literalog/library/internal/app/domain/suggest"
+)
+
+// Suggester suggests authors, series and genres by the beginning of the
+// words of their names, ranking them by the books referring to them. It
+// learns the names from the author, series and genre documents and the
+// references from the book documents. It is safe for concurrent use.
+type Suggester struct {
+	mu    sync.RWMutex
+	words trie
+	// names holds the name of every suggested entity.
+	names map[key]string
+	// refs holds what every book refers to, to count it once.
+	refs map[string][]key
+	// books counts the books referring to an author or series by id, or
+	// to a genre by folded tag.
+	books map[key]int
+}
+
+func NewSuggester() *Suggester {
+	return &Suggester{
+		names: make(map[key]string),
+		refs:  make(map[string][]key),
+		books: make(map[key]int),
+	}
+}
+
+func (s *Suggester) Put(ctx context.Context, doc search.Document) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch doc.Kind {
+	case search.KindBook:
+		s.uncount(doc.Id)
+
+		var refs []key
+		for _, ref := range doc.Refs {
+			if ref.Kind == search.KindAuthor || ref.Kind == search.KindSeries {
+				refs = append(refs, key{ref.Kind, ref.Id})
+			}
+		}
+		for _, f := range doc.Fields {
+			if f.Name == "genre" {
+				refs = append(refs, key{search.KindGenre, fold(f.Text)})
+			}
+		}
+
+		slices.SortFunc(refs, compareKeys)
+		refs = slices.Compact(refs)
+		for _, k := range refs {
+			s.books[k]++
+		}
+		s.refs[doc.Id] = refs
+	case search.KindAuthor, search.KindSeries, search.KindGenre:
+		k := key{doc.Kind, doc.Id}
+		s.forget(k)
+
+		for _, word := range terms(doc.Title) {
+			s.words.insert(word, k)
+		}
+		s.names[k] = doc.Title
+	}
+	return nil
+}
+
+func (s *Suggester) Remove(ctx context.Context, kind search.Kind, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if kind == search.KindBook {
+		s.uncount(id)
+	} else {
+		s.forget(key{kind, id})
+	}
+	return nil
+}
+
+func (s *Suggester) uncount(bookId string) {
+	for _, k := range s.refs[bookId] {
+		if s.books[k]--; s.books[k] <= 0 {
+			delete(s.books, k)
+		}
+	}
+	delete(s.refs, bookId)
+}
+
+func (s *Suggester) forget(k key) {
+	name, ok := s.names[k]
+	if !ok {
+		return
+	}
+	for _, word := range terms(name) {
+		s.words.delete(word, k)
+	}
+	delete(s.names, k)
+}
+
+// Suggest returns the entities with a word starting like each word of the
+// prefix. Those matching without typos come first, then the ones referred to
+// by the most books.
+func (s *Suggester) Suggest(ctx context.Context, q suggest.Query) ([]suggest.Suggestion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	qterms := terms(q.Prefix)
+	if len(qterms) == 0 {
+		return []suggest.Suggestion{}, nil
+	}
+
+	// The typos of each candidate over the query terms, and its score.
+	typos := make(map[key]int)
+	scores := make(map[key]float64)
+	for i, qt := range qterms {
+		matched := s.words.match(qt, maxTypos(qt))
+		if i > 0 {
+			for k := range typos {
+				if _, ok := matched[k]; !ok {
+					delete(typos, k)
+				}
+			}
+		}
+		for k, d := range matched {
+			if i > 0 {
+				if _, ok := typos[k]; !ok {
+					continue
+				}
+			}
+			if q.Type != "" && k.kind != q.Type {
+				continue
+			}
+			typos[k] += d
+			scores[k] += 1 / float64(1+d) / float64(len(qterms))
+		}
+	}
+
+	// Keep the best candidates only, rather than sorting them all: short
+	// prefixes match most names.
+	best := make([]candidate, 0, q.Limit+1)
+	for k, d := range typos {
+		c := candidate{key: k, typos: d, score: scores[k], books: s.books[s.countKey(k)]}
+		i, _ := slices.BinarySearchFunc(best, c, s.compare)
+		if i < q.Limit {
+			best = slices.Insert(best, i, c)
+			if len(best) > q.Limit {
+				best = best[:q.Limit]
+			}
+		}
+	}
+
+	ss := make([]suggest.Suggestion, len(best))
+	for i, c := range best {
+		ss[i] = suggest.Suggestion{
+			Type:  c.key.kind,
+			Id:    c.key.id,
+			Name:  s.names[c.key],
+			Books: c.books,
+			Score: c.score,
+		}
+	}
+	return ss, nil
+}
+
+type candidate struct {
+	key   key
+	typos int
+	score float64
+	books int
+}
+
+// compare orders candidates matching without typos first, then by books,
+// score and name.
+func (s *Suggester) compare(a, b candidate) int {
+	switch {
+	case (a.typos == 0) != (b.typos == 0):
+		if a.typos == 0 {
+			return -1
+		}
+		return 1
+	case a.books != b.books:
+		return b.books - a.books
+	case a.score > b.score:
+		return -1
+	case a.score < b.score:
+		return 1
+	}
+	if c := strings.Compare(s.names[a.key], s.names[b.key]); c != 0 {
+		return c
+	}
+	return compareKeys(a.key, b.key)
+}
+
+// countKey is the key books are counted under for an entity: genres are
+// referred to by tag rather than id.
+func (s *Suggester) countKey(k key) key {
+	if k.kind == search.KindGenre {
+		return key{search.KindGenre, fold(s.names[k])}
+	}
+	return k
+}
+
+func compareKeys(a, b key) int {
+	if c := strings.Compare(string(a.kind), string(b.kind)); c != 0 {
+		return c
+	}
+	return strings.Compare(a.id, b.id)
+}
@@ -0,0 +1,202 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/literalog/library/internal/app/domain/search"
+	"github.com/literalog/library/internal/app/domain/suggest"
+)
+
+func entity(kind search.Kind, id, name string) search.Document {
+	return search.Document{Kind: kind, Id: id, Title: name}
+}
+
+func book(id string, genres []string, refs ...search.Ref) search.Document {
+	doc := search.Document{Kind: search.KindBook, Id: id, Title: id, Refs: refs}
+	for _, g := range genres {
+		doc.Fields = append(doc.Fields, search.Field{Name: "genre", Text: g})
+	}
+	return doc
+}
+
+func newTestSuggester(t *testing.T) *Suggester {
+	t.Helper()
+
+	sanderson := search.Ref{Kind: search.KindAuthor, Id: "a1"}
+	bradbury := search.Ref{Kind: search.KindAuthor, Id: "a2"}
+	mistborn := search.Ref{Kind: search.KindSeries, Id: "s1"}
+
+	docs := []search.Document{
+		book("b1", []string{"Fantasy"}, sanderson, mistborn),
+		book("b2", []string{"Fantasy"}, sanderson, mistborn),
+		book("b3", []string{"Science Fiction"}, bradbury),
+		entity(search.KindAuthor, "a1", "Brandon Sanderson"),
+		entity(search.KindAuthor, "a2", "Ray Bradbury"),
+		entity(search.KindAuthor, "a3", "Branwell Brontë"),
+		entity(search.KindAuthor, "a4", "Émile Zola"),
+		entity(search.KindSeries, "s1", "Mistborn"),
+		entity(search.KindSeries, "s2", "Brandywine Chronicles"),
+		entity(search.KindGenre, "g1", "fantasy"),
+		entity(search.KindGenre, "g2", "science fiction"),
+	}
+
+	s := NewSuggester()
+	for _, d := range docs {
+		if err := s.Put(context.Background(), d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func suggestions(t *testing.T, s *Suggester, q suggest.Query) []string {
+	t.Helper()
+
+	if q.Limit == 0 {
+		q.Limit = suggest.DefaultLimit
+	}
+	ss, err := s.Suggest(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, x := range ss {
+		got = append(got, fmt.Sprintf("%s:%d", x.Id, x.Books))
+	}
+	return got
+}
+
+func TestSuggest(t *testing.T) {
+	s := newTestSuggester(t)
+
+	tests := []struct {
+		name string
+		q    suggest.Query
+		want []string
+	}{
+		{"ranked by books", suggest.Query{Type: search.KindAuthor, Prefix: "bra"}, []string{"a1:2", "a2:1", "a3:0"}},
+		{"any word", suggest.Query{Type: search.KindAuthor, Prefix: "sand"}, []string{"a1:2"}},
+		{"case and accents", suggest.Query{Type: search.KindAuthor, Prefix: "BRONTE"}, []string{"a3:0"}},
+		{"accents in the prefix", suggest.Query{Type: search.KindAuthor, Prefix: "émi"}, []string{"a4:0"}},
+		{"every word", suggest.Query{Type: search.KindAuthor, Prefix: "bran sand"}, []string{"a1:2"}},
+		{"typo after exact matches", suggest.Query{Type: search.KindAuthor, Prefix: "brad"}, []string{"a2:1", "a1:2", "a3:0"}},
+		{"transposition", suggest.Query{Type: search.KindSeries, Prefix: "mitsb"}, []string{"s1:2"}},
+		{"type", suggest.Query{Type: search.KindSeries, Prefix: "bran"}, []string{"s2:0"}},
+		{"every type", suggest.Query{Prefix: "bran"}, []string{"a1:2", "s2:0", "a3:0", "a2:1"}},
+		{"genres by tag", suggest.Query{Type: search.KindGenre, Prefix: "f"}, []string{"g1:2", "g2:1"}},
+		{"limit", suggest.Query{Type: search.KindAuthor, Prefix: "bra", Limit: 1}, []string{"a1:2"}},
+		{"no match", suggest.Query{Prefix: "xyz"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suggestions(t, s, tt.q); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestStaysInSync(t *testing.T) {
+	s := newTestSuggester(t)
+	ctx := context.Background()
+	q := suggest.Query{Type: search.KindAuthor, Prefix: "bra"}
+
+	// A renamed author is found by its new name only.
+	s.Put(ctx, entity(search.KindAuthor, "a3", "Anne Brontë"))
+	if got, want := suggestions(t, s, q), []string{"a1:2", "a2:1"}; !slices.Equal(got, want) {
+		t.Errorf("after rename: got %v, want %v", got, want)
+	}
+
+	// Moving the books changes the ranking.
+	s.Put(ctx, book("b1", nil, search.Ref{Kind: search.KindAuthor, Id: "a2"}))
+	s.Remove(ctx, search.KindBook, "b2")
+	if got, want := suggestions(t, s, q), []string{"a2:2", "a1:0"}; !slices.Equal(got, want) {
+		t.Errorf("after moving books: got %v, want %v", got, want)
+	}
+
+	s.Remove(ctx, search.KindAuthor, "a2")
+	if got, want := suggestions(t, s, q), []string{"a1:0"}; !slices.Equal(got, want) {
+		t.Errorf("after removal: got %v, want %v", got, want)
+	}
+	if _, ok := s.words.root.children['r']; ok {
+		t.Error("words of a removed author left in the trie")
+	}
+}
+
+// newLargeSuggester indexes a catalog of made up authors, series and books.
+func newLargeSuggester(tb testing.TB, authors, books int) *Suggester {
+	tb.Helper()
+
+	r := rand.New(rand.NewSource(1))
+	syllables := []string{"an", "bel", "cor", "da", "el", "fi", "gor", "ha", "is", "jo", "ka", "lin", "mar", "no", "or", "pe", "qui", "ra", "san", "to", "ul", "ve", "wen", "xa", "yo", "zé"}
+	word := func() string {
+		var b strings.Builder
+		for i := 0; i < 2+r.Intn(3); i++ {
+			b.WriteString(syllables[r.Intn(len(syllables))])
+		}
+		w := b.String()
+		return strings.ToUpper(w[:1]) + w[1:]
+	}
+
+	ctx := context.Background()
+	s := NewSuggester()
+	for i := 0; i < authors; i++ {
+		s.Put(ctx, entity(search.KindAuthor, fmt.Sprint("a", i), word()+" "+word()))
+		s.Put(ctx, entity(search.KindSeries, fmt.Sprint("s", i), word()))
+	}
+	for i := 0; i < books; i++ {
+		s.Put(ctx, book(fmt.Sprint("b", i), []string{"Fantasy"},
+			search.Ref{Kind: search.KindAuthor, Id: fmt.Sprint("a", r.Intn(authors))},
+			search.Ref{Kind: search.KindSeries, Id: fmt.Sprint("s", r.Intn(authors))}))
+	}
+	return s
+}
+
+var latencyPrefixes = []string{"a", "ma", "san", "bel", "kor", "marsan", "linto", "zé", "wenor", "qu", "yoda fi", "pequi", "gorha"}
+
+func TestSuggestLatency(t *testing.T) {
+	if testing.Short() {
+		t.Skip("indexes a large catalog")
+	}
+
+	s := newLargeSuggester(t, 20000, 100000)
+	ctx := context.Background()
+
+	var durations []time.Duration
+	for i := 0; i < 20; i++ {
+		for _, prefix := range latencyPrefixes {
+			start := time.Now()
+			if _, err := s.Suggest(ctx, suggest.Query{Type: search.KindAuthor, Prefix: prefix, Limit: suggest.DefaultLimit}); err != nil {
+				t.Fatal(err)
+			}
+			durations = append(durations, time.Since(start))
+		}
+	}
+
+	slices.Sort(durations)
+	p50 := durations[len(durations)/2]
+	p99 := durations[len(durations)*99/100]
+	t.Logf("p50 %v, p99 %v over %d queries", p50, p99, len(durations))
+
+	// Generous bounds, to catch a full scan rather than measure the machine.
+	if p50 > 20*time.Millisecond || p99 > 100*time.Millisecond {
+		t.Errorf("suggestions too slow: p50 %v, p99 %v", p50, p99)
+	}
+}
+
+func BenchmarkSuggest(b *testing.B) {
+	s := newLargeSuggester(b, 20000, 100000)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		prefix := latencyPrefixes[i%len(latencyPrefixes)]
+		s.Suggest(ctx, suggest.Query{Type: search.KindAuthor, Prefix: prefix, Limit: suggest.DefaultLimit})
+	}
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// token is a word of a text, folded into a term, with its byte offsets in
+// the original text.
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits text into words of letters and digits.
+func tokenize(text string) []token {
+	var (
+		tokens []token
+		start  = -1
+	)
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			tokens = append(tokens, token{term: fold(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{term: fold(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// terms returns the distinct terms of text in order of appearance.
+func terms(text string) []string {
+	var (
+		tt   []string
+		seen = make(map[string]bool)
+	)
+	for _, t := range tokenize(text) {
+		if t.term != "" && !seen[t.term] {
+			seen[t.term] = true
+			tt = append(tt, t.term)
+		}
+	}
+	return tt
+}
+
+// fold lower cases s and strips its diacritics, so "Émile" and "emile" are
+// the same term.
+func fold(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+	return strings.ToLower(folded)
+}
+
+// maxTypos is the edit distance tolerated for a query term: none for short
+// words, where a typo makes another word, one for medium and two for long
+// ones.
+func maxTypos(term string) int {
+	switch n := utf8.RuneCountInString(term); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance is the optimal string alignment distance between a and b: the
+// number of insertions, deletions, substitutions and transpositions of
+// adjacent letters turning one into the other. It gives up, returning
+// max+1, once the distance exceeds max.
+func distance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > max || -d > max {
+		return max + 1
+	}
+
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		best := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			best = min(best, curr[j])
+		}
+		if best > max {
+			return max + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,100 @@
+package memory
+
+// trie holds the words of names rune by rune. Each node knows the entities
+// with a word going through it, so the entities with a word starting with a
+// prefix are found without walking the words below it.
+type trie struct {
+	root node
+}
+
+type node struct {
+	children map[rune]*node
+	// below counts, per entity, its words going through the node.
+	below map[key]int
+}
+
+func (t *trie) insert(word string, k key) {
+	n := &t.root
+	for _, r := range word {
+		c, ok := n.children[r]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[rune]*node)
+			}
+			c = &node{below: make(map[key]int)}
+			n.children[r] = c
+		}
+		c.below[k]++
+		n = c
+	}
+}
+
+func (t *trie) delete(word string, k key) {
+	n := &t.root
+	for _, r := range word {
+		c, ok := n.children[r]
+		if !ok {
+			return
+		}
+		if c.below[k]--; c.below[k] <= 0 {
+			delete(c.below, k)
+		}
+		if len(c.below) == 0 {
+			// Nothing is left below, drop the whole branch.
+			delete(n.children, r)
+			return
+		}
+		n = c
+	}
+}
+
+// match returns the entities with a word starting like prefix, give or take
+// typos edits, with the fewest edits each. Edits are counted as in distance.
+func (t *trie) match(prefix string, typos int) map[key]int {
+	q := []rune(prefix)
+	found := make(map[key]int)
+
+	row := make([]int, len(q)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	// walk extends the path to n, whose last rune is last, by each child,
+	// computing the distances from the query prefixes to the longer path.
+	var walk func(n *node, last rune, prev2, prev []int)
+	walk = func(n *node, last rune, prev2, prev []int) {
+		for r, c := range n.children {
+			curr := make([]int, len(q)+1)
+			curr[0] = prev[0] + 1
+			best := curr[0]
+			for j := 1; j <= len(q); j++ {
+				cost := 1
+				if q[j-1] == r {
+					cost = 0
+				}
+				curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+				if prev2 != nil && j > 1 && q[j-1] == last && q[j-2] == r {
+					curr[j] = min(curr[j], prev2[j-2]+1)
+				}
+				best = min(best, curr[j])
+			}
+
+			if d := curr[len(q)]; d <= typos {
+				for k := range c.below {
+					if old, ok := found[k]; !ok || d < old {
+						found[k] = d
+					}
+				}
+				if d == 0 {
+					// Longer paths cannot match better.
+					continue
+				}
+			}
+			if best <= typos {
+				walk(c, r, prev, curr)
+			}
+		}
+	}
+	walk(&t.root, 0, nil, row)
+	return found
+}
@@ -0,0 +1,177 @@
+// Package client is a Go client for the library API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/literalog/library/pkg/models"
+)
+
+// Client calls the library API at a base URL. It is safe for concurrent use.
+type Client struct {
+	baseURL *url.URL
+	http    *http.Client
+	retry   RetryPolicy
+	token   string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sends requests with h instead of http.DefaultClient.
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) {
+		c.http = h
+	}
+}
+
+// WithRetry retries failed requests as decided by p.
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithToken authenticates requests with a bearer token.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// New returns a client for the API served at baseURL, such as
+// "http://localhost:8080".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base url %q", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	c := &Client{
+		baseURL: u,
+		http:    http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+func (c *Client) Books() *Books {
+	return &Books{resource[models.Book, models.BookRequest]{c, "/books"}}
+}
+
+func (c *Client) Works() *Works {
+	return &Works{resource[models.Work, models.WorkRequest]{c, "/works"}}
+}
+
+func (c *Client) Authors() *Authors {
+	return &Authors{resource[models.Author, models.AuthorRequest]{c, "/authors"}}
+}
+
+func (c *Client) Series() *Series {
+	return &Series{resource[models.Series, models.SeriesRequest]{c, "/series"}}
+}
+
+func (c *Client) Genres() *Genres {
+	return &Genres{resource[models.Genre, models.GenreRequest]{c, "/genres"}}
+}
+
+// do sends a request with in as its JSON body, if not nil, and decodes the
+// response into out, if not nil. Failed requests are retried according to
+// the retry policy.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	return c.send(ctx, method, path, query, "application/json", in, out)
+}
+
+// send is do with the given content type for the request body. path is
+// already escaped, so ids holding a slash or a percent sign stay whole.
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, contentType string, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("error encoding request: %w", err)
+		}
+	}
+
+	unescaped, err := url.PathUnescape(path)
+	if err != nil {
+		return fmt.Errorf("invalid path %q: %w", path, err)
+	}
+	u := *c.baseURL
+	u.RawPath = u.EscapedPath() + path
+	u.Path += unescaped
+	u.RawQuery = query.Encode()
+
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json")
+		if in != nil {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.http.Do(req)
+		if c.retry != nil {
+			if wait, ok := c.retry(req, attempt, resp, err); ok {
+				if resp != nil {
+					io.Copy(io.Discard, resp.Body)
+					resp.Body.Close()
+				}
+				if err := sleep(ctx, wait); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		return decode(resp, out)
+	}
+}
+
+func decode(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
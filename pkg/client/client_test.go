@@ -0,0 +1,289 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/literalog/library/internal/app/config"
+	"github.com/literalog/library/internal/app/gateways/api"
+	"github.com/literalog/library/pkg/client"
+	"github.com/literalog/library/pkg/models"
+	"github.com/literalog/library/pkg/problem"
+)
+
+func newServer(t *testing.T) *api.Server {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Storage = config.StorageMemory
+	s, err := api.NewServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func newClient(t *testing.T, opts ...client.Option) *client.Client {
+	t.Helper()
+
+	s := newServer(t)
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+
+	c, err := client.New(ts.URL, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCRUD(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t)
+
+	a, err := c.Authors().Create(ctx, models.AuthorRequest{Name: "Ursula K. Le Guin"})
+	if err != nil {
+		t.Fatalf("create author: %v", err)
+	}
+	s, err := c.Series().Create(ctx, models.SeriesRequest{Name: "Earthsea"})
+	if err != nil {
+		t.Fatalf("create series: %v", err)
+	}
+
+	b, err := c.Books().Create(ctx, models.BookRequest{
+		Title:    "A Wizard of Earthsea",
+		AuthorId: a.Id,
+		SeriesId: s.Id,
+		SeriesNo: 1,
+		Isbn:     []string{"0-306-40615-2"},
+		Format:   models.Paperback,
+	})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	if b.Version != 1 || len(b.Contributors) != 1 {
+		t.Errorf("unexpected book %+v", b)
+	}
+
+	got, err := c.Books().GetByIsbn(ctx, "9780306406157")
+	if err != nil {
+		t.Fatalf("get by isbn: %v", err)
+	}
+	if got.Id != b.Id {
+		t.Errorf("got book %s, want %s", got.Id, b.Id)
+	}
+
+	a, err = c.Authors().Update(ctx, a.Id, models.AuthorRequest{Name: "Ursula Le Guin"})
+	if err != nil {
+		t.Fatalf("update author: %v", err)
+	}
+	if a.Name != "Ursula Le Guin" || a.Version != 2 {
+		t.Errorf("unexpected author %+v", a)
+	}
+
+	s, err = c.Series().Patch(ctx, s.Id, map[string]any{"name": "The Earthsea Cycle"})
+	if err != nil {
+		t.Fatalf("patch series: %v", err)
+	}
+	if s.Name != "The Earthsea Cycle" || s.Version != 2 {
+		t.Errorf("unexpected series %+v", s)
+	}
+
+	books, err := c.Series().Books(ctx, s.Id, client.ListOptions{})
+	if err != nil {
+		t.Fatalf("series books: %v", err)
+	}
+	if books.Total != 1 {
+		t.Errorf("got %d series books, want 1", books.Total)
+	}
+
+	if err := c.Books().Delete(ctx, b.Id); err != nil {
+		t.Fatalf("delete book: %v", err)
+	}
+	if _, err := c.Books().Get(ctx, b.Id); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("get deleted book: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t)
+
+	_, err := c.Books().Create(ctx, models.BookRequest{Title: "A"})
+	if !errors.Is(err, client.ErrInvalid) {
+		t.Fatalf("got %v, want ErrInvalid", err)
+	}
+
+	var e *client.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("got %T, want *client.Error", err)
+	}
+	if e.Status != http.StatusUnprocessableEntity || len(e.Errors) == 0 {
+		t.Errorf("unexpected problem %+v", e.Problem)
+	}
+	if e.Errors[0].Code == "" || e.Errors[0].Field == "" {
+		t.Errorf("field error without field or code: %+v", e.Errors[0])
+	}
+
+	if _, err := c.Genres().Get(ctx, "missing"); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+}
+
+func TestForceCreate(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t)
+
+	a, _ := c.Authors().Create(ctx, models.AuthorRequest{Name: "Ursula K. Le Guin"})
+	s, _ := c.Series().Create(ctx, models.SeriesRequest{Name: "Earthsea"})
+	req := models.BookRequest{Title: "A Wizard of Earthsea", AuthorId: a.Id, SeriesId: s.Id, Year: 1968}
+
+	first, err := c.Books().Create(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Title = "Wizard of Earthsea, A"
+	_, err = c.Books().Create(ctx, req)
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || !errors.Is(err, client.ErrConflict) {
+		t.Fatalf("probable duplicate: got %v, want a conflict", err)
+	}
+	if ids, _ := apiErr.Extensions["candidate_ids"].([]any); len(ids) != 1 || ids[0] != first.Id {
+		t.Errorf("candidate_ids: got %v, want [%s]", apiErr.Extensions["candidate_ids"], first.Id)
+	}
+
+	if _, err := c.Books().ForceCreate(ctx, req); err != nil {
+		t.Errorf("forced create: %v", err)
+	}
+}
+
+func TestIterator(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t)
+
+	for _, tag := range []string{"fantasy", "horror", "mystery", "romance", "sci-fi"} {
+		if _, err := c.Genres().Create(ctx, models.GenreRequest{Tag: tag}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, err := c.Genres().List(ctx, client.ListOptions{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 2 || page.Total != 5 || page.NextCursor == "" {
+		t.Errorf("unexpected first page %+v", page)
+	}
+
+	it := c.Genres().All(ctx, client.ListOptions{
+		Limit: 2,
+		Sort:  []models.SortField{{Field: "tag", Desc: true}},
+	})
+	var tags []string
+	for it.Next() {
+		tags = append(tags, it.Item().Tag)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"sci-fi", "romance", "mystery", "horror", "fantasy"}
+	if len(tags) != len(want) {
+		t.Fatalf("got tags %v, want %v", tags, want)
+	}
+	for i := range want {
+		if tags[i] != want[i] {
+			t.Fatalf("got tags %v, want %v", tags, want)
+		}
+	}
+
+	it = c.Genres().All(ctx, client.ListOptions{Filter: url.Values{"tag": {"r"}}})
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if it.Err() != nil || n != 3 {
+		t.Errorf("filtered iteration: got %d items, err %v", n, it.Err())
+	}
+}
+
+func TestRetry(t *testing.T) {
+	s := newServer(t)
+
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			problem.New(http.StatusServiceUnavailable, "warming up").Render(w)
+			return
+		}
+		s.Handler().ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	var attempts []int
+	backoff := client.Backoff(3, time.Millisecond)
+	c, err := client.New(ts.URL, client.WithRetry(func(req *http.Request, attempt int, resp *http.Response, err error) (time.Duration, bool) {
+		attempts = append(attempts, attempt)
+		return backoff(req, attempt, resp, err)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err := c.Authors().List(ctx, client.ListOptions{}); err != nil {
+		t.Fatalf("got %v after retries", err)
+	}
+	if calls.Load() != 3 || len(attempts) != 3 {
+		t.Errorf("got %d calls and attempts %v, want 3", calls.Load(), attempts)
+	}
+
+	calls.Store(0)
+	_, err = c.Authors().Create(ctx, models.AuthorRequest{Name: "Octavia E. Butler"})
+	if err == nil || calls.Load() != 1 {
+		t.Errorf("create should not be retried: got %v after %d calls", err, calls.Load())
+	}
+}
+
+func TestEscapesIds(t *testing.T) {
+	var uris []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uris = append(uris, r.RequestURI)
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	c, err := client.New(ts.URL + "/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	const id = "a/b c%d"
+	c.Authors().Get(ctx, id)
+	c.Authors().Update(ctx, id, models.AuthorRequest{})
+	c.Authors().Patch(ctx, id, map[string]any{})
+	c.Authors().Delete(ctx, id)
+
+	want := "/v1/authors/a%2Fb%20c%25d"
+	if len(uris) != 4 {
+		t.Fatalf("got %d requests, want 4", len(uris))
+	}
+	for _, got := range uris {
+		if got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	}
+}
+
+func TestNewRejectsRelativeURL(t *testing.T) {
+	if _, err := client.New("localhost:8080"); err == nil {
+		t.Error("expected an error for a base url without scheme")
+	}
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/literalog/library/pkg/models"
+)
+
+type Books struct {
+	resource[models.Book, models.BookRequest]
+}
+
+// GetByIsbn finds the edition with the given ISBN-10 or ISBN-13.
+func (b *Books) GetByIsbn(ctx context.Context, isbn string) (*models.Book, error) {
+	v := new(models.Book)
+	if err := b.client.do(ctx, http.MethodGet, "/books/isbn/"+url.PathEscape(isbn), nil, nil, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ForceCreate creates a book even when it probably duplicates stored books,
+// which Create rejects with ErrConflict. A book with a stored ISBN is still
+// rejected.
+func (b *Books) ForceCreate(ctx context.Context, req models.BookRequest) (*models.Book, error) {
+	v := new(models.Book)
+	if err := b.client.do(ctx, http.MethodPost, b.path, url.Values{"force": {"true"}}, req, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+type Works struct {
+	resource[models.Work, models.WorkRequest]
+}
+
+// Editions lists the books published as editions of a work.
+func (w *Works) Editions(ctx context.Context, id string, opts ListOptions) (*models.List[models.Book], error) {
+	return list[models.Book](ctx, w.client, w.path+"/"+url.PathEscape(id)+"/editions", opts)
+}
+
+// CreateEdition creates a book under a work.
+func (w *Works) CreateEdition(ctx context.Context, id string, req models.EditionRequest) (*models.Book, error) {
+	v := new(models.Book)
+	if err := w.client.do(ctx, http.MethodPost, w.path+"/"+url.PathEscape(id)+"/editions", nil, req, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+type Authors struct {
+	resource[models.Author, models.AuthorRequest]
+}
+
+// Books lists the books an author contributed to.
+func (a *Authors) Books(ctx context.Context, id string, opts ListOptions) (*models.List[models.Book], error) {
+	return list[models.Book](ctx, a.client, a.path+"/"+url.PathEscape(id)+"/books", opts)
+}
+
+type Series struct {
+	resource[models.Series, models.SeriesRequest]
+}
+
+// Books lists the books of a series in reading order.
+func (s *Series) Books(ctx context.Context, id string, opts ListOptions) (*models.List[models.Book], error) {
+	return list[models.Book](ctx, s.client, s.path+"/"+url.PathEscape(id)+"/books", opts)
+}
+
+type Genres struct {
+	resource[models.Genre, models.GenreRequest]
+}
+
+// Books lists the books tagged with a genre.
+func (g *Genres) Books(ctx context.Context, id string, opts ListOptions) (*models.List[models.Book], error) {
+	return list[models.Book](ctx, g.client, g.path+"/"+url.PathEscape(id)+"/books", opts)
+}
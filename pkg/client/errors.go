@@ -0,0 +1,51 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/literalog/library/pkg/problem"
+)
+
+// Errors returned by the API can be matched with errors.Is against these.
+var (
+	ErrBadRequest         = errors.New("bad request")
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrInvalid            = errors.New("invalid entity")
+)
+
+var statusErrors = map[int]error{
+	http.StatusBadRequest:          ErrBadRequest,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusConflict:            ErrConflict,
+	http.StatusPreconditionFailed:  ErrPreconditionFailed,
+	http.StatusUnprocessableEntity: ErrInvalid,
+}
+
+// Error is a failed request, decoded from the problem details the API
+// returned. Validation failures list the offending fields in Errors.
+type Error struct {
+	problem.Problem
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("library: %d %s", e.Status, e.Problem.Error())
+}
+
+func (e *Error) Is(target error) bool {
+	return statusErrors[e.Status] == target
+}
+
+func newError(resp *http.Response) error {
+	e := &Error{}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err := json.Unmarshal(b, &e.Problem); err != nil || e.Status == 0 {
+		e.Problem = *problem.New(resp.StatusCode, string(b))
+	}
+	return e
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/literalog/library/pkg/models"
+)
+
+// ListOptions selects a page of a collection.
+type ListOptions struct {
+	Limit  int
+	Cursor string
+	Sort   []models.SortField
+	// Filter holds the collection's filters, such as "name" or "author_id".
+	Filter url.Values
+}
+
+func (o ListOptions) query() url.Values {
+	q := url.Values{}
+	for k, v := range o.Filter {
+		q[k] = v
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		q.Set("cursor", o.Cursor)
+	}
+	if len(o.Sort) > 0 {
+		fields := make([]string, len(o.Sort))
+		for i, s := range o.Sort {
+			fields[i] = s.Field
+			if s.Desc {
+				fields[i] = "-" + s.Field
+			}
+		}
+		q.Set("sort", strings.Join(fields, ","))
+	}
+	return q
+}
+
+// resource implements the calls shared by every collection, for entity T
+// created from request R.
+type resource[T, R any] struct {
+	client *Client
+	path   string
+}
+
+func (r resource[T, R]) Create(ctx context.Context, req R) (*T, error) {
+	v := new(T)
+	if err := r.client.do(ctx, http.MethodPost, r.path, nil, req, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (r resource[T, R]) Get(ctx context.Context, id string) (*T, error) {
+	v := new(T)
+	if err := r.client.do(ctx, http.MethodGet, r.path+"/"+url.PathEscape(id), nil, nil, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// List returns one page of the collection.
+func (r resource[T, R]) List(ctx context.Context, opts ListOptions) (*models.List[T], error) {
+	return list[T](ctx, r.client, r.path, opts)
+}
+
+// All iterates over the whole collection, fetching pages as needed.
+func (r resource[T, R]) All(ctx context.Context, opts ListOptions) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, client: r.client, path: r.path, opts: opts}
+}
+
+// Update replaces the entity with the given id.
+func (r resource[T, R]) Update(ctx context.Context, id string, req R) (*T, error) {
+	v := new(T)
+	if err := r.client.do(ctx, http.MethodPut, r.path+"/"+url.PathEscape(id), nil, req, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Patch changes the given fields of the entity with a JSON merge patch.
+func (r resource[T, R]) Patch(ctx context.Context, id string, fields map[string]any) (*T, error) {
+	v := new(T)
+	if err := r.client.send(ctx, http.MethodPatch, r.path+"/"+url.PathEscape(id), nil, "application/merge-patch+json", fields, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (r resource[T, R]) Delete(ctx context.Context, id string) error {
+	return r.client.do(ctx, http.MethodDelete, r.path+"/"+url.PathEscape(id), nil, nil, nil)
+}
+
+func list[T any](ctx context.Context, c *Client, path string, opts ListOptions) (*models.List[T], error) {
+	l := new(models.List[T])
+	if err := c.do(ctx, http.MethodGet, path, opts.query(), nil, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Iterator walks a collection page by page:
+//
+//	it := c.Books().All(ctx, client.ListOptions{})
+//	for it.Next() {
+//		b := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx    context.Context
+	client *Client
+	path   string
+	opts   ListOptions
+
+	page    []T
+	current T
+	done    bool
+	err     error
+}
+
+// Next advances to the next item, fetching the next page when needed. It
+// returns false at the end of the collection or on error.
+func (it *Iterator[T]) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		l, err := list[T](it.ctx, it.client, it.path, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = l.Items
+		it.opts.Cursor = l.NextCursor
+		it.done = l.NextCursor == ""
+	}
+
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Item returns the item Next advanced to.
+func (it *Iterator[T]) Item() T {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy decides whether the attempt of req, counted from 1, which
+// ended with resp or err, should be retried and how long to wait first.
+type RetryPolicy func(req *http.Request, attempt int, resp *http.Response, err error) (time.Duration, bool)
+
+// Backoff retries idempotent requests up to attempts times in total when the
+// connection fails or the server is unavailable, waiting base, then twice as
+// long, and so on, with some jitter.
+func Backoff(attempts int, base time.Duration) RetryPolicy {
+	return func(req *http.Request, attempt int, resp *http.Response, err error) (time.Duration, bool) {
+		if attempt >= attempts || !idempotent(req.Method) || !retryable(resp, err) {
+			return 0, false
+		}
+
+		wait := base << (attempt - 1)
+		jitter := time.Duration(rand.Int63n(int64(wait)/2 + 1))
+		return wait + jitter, true
+	}
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
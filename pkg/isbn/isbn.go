@@ -0,0 +1,161 @@
+// Package isbn parses, validates and converts ISBN-10 and ISBN-13 numbers.
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidLength    = errors.New("isbn must have 10 or 13 digits")
+	ErrInvalidCharacter = errors.New("isbn contains an invalid character")
+	ErrInvalidPrefix    = errors.New("isbn-13 must start with 978 or 979")
+	ErrInvalidChecksum  = errors.New("isbn check digit does not match")
+	ErrNoIsbn10         = errors.New("isbn-13 with a 979 prefix has no isbn-10 form")
+)
+
+// ISBN is a valid ISBN in its canonical form: 13 digits without separators.
+type ISBN string
+
+// Parse reads an ISBN-10 or ISBN-13, optionally separated by hyphens or
+// spaces, and returns it in canonical form once its check digit is verified.
+func Parse(s string) (ISBN, error) {
+	digits := Strip(s)
+
+	switch len(digits) {
+	case 10:
+		if err := Validate10(digits); err != nil {
+			return "", err
+		}
+		return ISBN(to13(digits)), nil
+	case 13:
+		if err := Validate13(digits); err != nil {
+			return "", err
+		}
+		return ISBN(digits), nil
+	default:
+		return "", ErrInvalidLength
+	}
+}
+
+// Strip removes hyphens and spaces and upper-cases the ISBN-10 check digit X.
+func Strip(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ':
+			return -1
+		case 'x':
+			return 'X'
+		default:
+			return r
+		}
+	}, s)
+}
+
+func (i ISBN) String() string {
+	return string(i)
+}
+
+// ISBN13 returns the canonical form.
+func (i ISBN) ISBN13() string {
+	return string(i)
+}
+
+// ISBN10 returns the ISBN-10 form, which only exists for the 978 prefix.
+func (i ISBN) ISBN10() (string, error) {
+	if !strings.HasPrefix(string(i), "978") {
+		return "", ErrNoIsbn10
+	}
+	body := string(i)[3:12]
+	return body + string(checkDigit10(body)), nil
+}
+
+// Validate10 checks a stripped ISBN-10.
+func Validate10(s string) error {
+	if len(s) != 10 {
+		return ErrInvalidLength
+	}
+	if !isDigits(s[:9]) || !(isDigits(s[9:]) || s[9] == 'X') {
+		return ErrInvalidCharacter
+	}
+	if checkDigit10(s[:9]) != s[9] {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+// Validate13 checks a stripped ISBN-13.
+func Validate13(s string) error {
+	if len(s) != 13 {
+		return ErrInvalidLength
+	}
+	if !isDigits(s) {
+		return ErrInvalidCharacter
+	}
+	if !strings.HasPrefix(s, "978") && !strings.HasPrefix(s, "979") {
+		return ErrInvalidPrefix
+	}
+	if checkDigit13(s[:12]) != s[12] {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+// To13 converts an ISBN-10 to ISBN-13.
+func To13(s string) (string, error) {
+	i, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return i.ISBN13(), nil
+}
+
+// To10 converts an ISBN-13 to ISBN-10.
+func To10(s string) (string, error) {
+	i, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return i.ISBN10()
+}
+
+func to13(isbn10 string) string {
+	body := "978" + isbn10[:9]
+	return body + string(checkDigit13(body))
+}
+
+// checkDigit10 weighs the nine digits from 10 down to 2, modulo 11.
+func checkDigit10(body string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+	switch d := (11 - sum%11) % 11; d {
+	case 10:
+		return 'X'
+	default:
+		return byte('0' + d)
+	}
+}
+
+// checkDigit13 weighs the twelve digits alternately by 1 and 3, modulo 10.
+func checkDigit13(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		w := 1
+		if i%2 == 1 {
+			w = 3
+		}
+		sum += int(body[i]-'0') * w
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
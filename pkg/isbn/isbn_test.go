@@ -0,0 +1,59 @@
+package isbn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ISBN
+		err  error
+	}{
+		{"9780306406157", "9780306406157", nil},
+		{"978-0-306-40615-7", "9780306406157", nil},
+		{"978 0 306 40615 7", "9780306406157", nil},
+		{"0306406152", "9780306406157", nil},
+		{"0-306-40615-2", "9780306406157", nil},
+		{"080442957X", "9780804429573", nil},
+		{"0-8044-2957-x", "9780804429573", nil},
+		{"9791090636071", "9791090636071", nil},
+		{"9780306406158", "", ErrInvalidChecksum},
+		{"0306406153", "", ErrInvalidChecksum},
+		{"0804429571", "", ErrInvalidChecksum},
+		{"0306406X52", "", ErrInvalidCharacter},
+		{"978030640615X", "", ErrInvalidCharacter},
+		{"9770306406151", "", ErrInvalidPrefix},
+		{"030640615", "", ErrInvalidLength},
+		{"", "", ErrInvalidLength},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q) = %q, %v, want %q, %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		isbn10, isbn13 string
+	}{
+		{"0306406152", "9780306406157"},
+		{"080442957X", "9780804429573"},
+		{"054792822X", "9780547928227"},
+	}
+	for _, tt := range tests {
+		if got, err := To13(tt.isbn10); got != tt.isbn13 || err != nil {
+			t.Errorf("To13(%q) = %q, %v, want %q", tt.isbn10, got, err, tt.isbn13)
+		}
+		if got, err := To10(tt.isbn13); got != tt.isbn10 || err != nil {
+			t.Errorf("To10(%q) = %q, %v, want %q", tt.isbn13, got, err, tt.isbn10)
+		}
+	}
+
+	if _, err := To10("9791090636071"); !errors.Is(err, ErrNoIsbn10) {
+		t.Errorf("To10 of a 979 isbn: got %v, want %v", err, ErrNoIsbn10)
+	}
+}
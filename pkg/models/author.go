@@ -3,8 +3,9 @@ package models
 import "github.com/google/uuid"
 
 type Author struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
+	Id   string `json:"id" bson:"_id"`
+	Meta `bson:",inline"`
+	Name string `json:"name" bson:"name"`
 }
 
 type AuthorRequest struct {
@@ -14,6 +15,7 @@ type AuthorRequest struct {
 func NewAuthor(req AuthorRequest) *Author {
 	return &Author{
 		Id:   uuid.NewString(),
+		Meta: NewMeta(),
 		Name: req.Name,
 	}
 }
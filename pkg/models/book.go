@@ -6,41 +6,51 @@ import (
 	"github.com/google/uuid"
 )
 
+// Book is an edition of a work. Until every book belongs to a work it keeps
+// its own copy of the work fields.
+//
+// Book.AuthorId is the primary author, derived from Contributors. On requests
+// it is only read when Contributors is empty, as the sole author.
 type Book struct {
-	Id        string   `json:"id" bson:"_id"`
-	Title     string   `json:"title" bson:"title"`
-	AuthorId  string   `json:"author_id" bson:"author_id,omitempty"`
-	Isbn      []string `json:"isbn" bson:"isbn,omitempty"`
-	SeriesId  string   `json:"series_id" bson:"series_id,omitempty"`
-	SeriesNo  int      `json:"series_no" bson:"series_no,omitempty"`
-	Year      int      `json:"year" bson:"year,omitempty"`
-	Publisher string   `json:"publisher" bson:"publisher,omitempty"`
-	Language  string   `json:"language" bson:"language,omitempty"`
-	Format    Format   `json:"format" bson:"format,omitempty"`
-	PagesNo   int      `json:"pages_no" bson:"pages_no,omitempty"`
-	HoursNo   int      `json:"hours_no" bson:"hours_no,omitempty"`
-	Genre     []string `json:"genre" bson:"genre,omitempty"`
-	Blurb     string   `json:"blurb" bson:"blurb,omitempty"`
-	Cover     string   `json:"cover" bson:"cover,omitempty"`
-	NotABook  bool     `json:"not_a_book" bson:"not_a_book"`
+	Id           string `json:"id" bson:"_id"`
+	Meta         `bson:",inline"`
+	WorkId       string        `json:"work_id,omitempty" bson:"work_id,omitempty"`
+	Title        string        `json:"title" bson:"title"`
+	Contributors []Contributor `json:"contributors" bson:"contributors,omitempty"`
+	AuthorId     string        `json:"author_id" bson:"author_id,omitempty"`
+	Isbn         []string      `json:"isbn" bson:"isbn,omitempty"`
+	SeriesId     string        `json:"series_id" bson:"series_id,omitempty"`
+	SeriesNo     int           `json:"series_no" bson:"series_no,omitempty"`
+	Year         int           `json:"year" bson:"year,omitempty"`
+	Publisher    string        `json:"publisher" bson:"publisher,omitempty"`
+	Language     string        `json:"language" bson:"language,omitempty"`
+	Format       Format        `json:"format" bson:"format,omitempty"`
+	PagesNo      int           `json:"pages_no" bson:"pages_no,omitempty"`
+	HoursNo      int           `json:"hours_no" bson:"hours_no,omitempty"`
+	Genre        []string      `json:"genre" bson:"genre,omitempty"`
+	Blurb        string        `json:"blurb" bson:"blurb,omitempty"`
+	Cover        string        `json:"cover" bson:"cover,omitempty"`
+	NotABook     bool          `json:"not_a_book" bson:"not_a_book"`
 }
 
 type BookRequest struct {
-	Title     string   `json:"title" bson:"title"`
-	AuthorId  string   `json:"author_id" bson:"author_id,omitempty"`
-	Isbn      []string `json:"isbn" bson:"isbn,omitempty"`
-	SeriesId  string   `json:"series_id" bson:"series_id,omitempty"`
-	SeriesNo  int      `json:"series_no" bson:"series_no,omitempty"`
-	Year      int      `json:"year" bson:"year,omitempty"`
-	Publisher string   `json:"publisher" bson:"publisher,omitempty"`
-	Language  string   `json:"language" bson:"language,omitempty"`
-	Format    Format   `json:"format" bson:"format,omitempty"`
-	PagesNo   int      `json:"pages_no" bson:"pages_no,omitempty"`
-	HoursNo   int      `json:"hours_no" bson:"hours_no,omitempty"`
-	Genre     []string `json:"genre" bson:"genre,omitempty"`
-	Blurb     string   `json:"blurb" bson:"blurb,omitempty"`
-	Cover     string   `json:"cover" bson:"cover,omitempty"`
-	NotABook  bool     `json:"not_a_book" bson:"not_a_book"`
+	WorkId       string        `json:"work_id" bson:"work_id,omitempty"`
+	Title        string        `json:"title" bson:"title"`
+	Contributors []Contributor `json:"contributors" bson:"contributors,omitempty"`
+	AuthorId     string        `json:"author_id" bson:"author_id,omitempty"`
+	Isbn         []string      `json:"isbn" bson:"isbn,omitempty"`
+	SeriesId     string        `json:"series_id" bson:"series_id,omitempty"`
+	SeriesNo     int           `json:"series_no" bson:"series_no,omitempty"`
+	Year         int           `json:"year" bson:"year,omitempty"`
+	Publisher    string        `json:"publisher" bson:"publisher,omitempty"`
+	Language     string        `json:"language" bson:"language,omitempty"`
+	Format       Format        `json:"format" bson:"format,omitempty"`
+	PagesNo      int           `json:"pages_no" bson:"pages_no,omitempty"`
+	HoursNo      int           `json:"hours_no" bson:"hours_no,omitempty"`
+	Genre        []string      `json:"genre" bson:"genre,omitempty"`
+	Blurb        string        `json:"blurb" bson:"blurb,omitempty"`
+	Cover        string        `json:"cover" bson:"cover,omitempty"`
+	NotABook     bool          `json:"not_a_book" bson:"not_a_book"`
 }
 
 type Format string
@@ -68,22 +78,26 @@ func NewFormat(s string) Format {
 }
 
 func NewBook(req BookRequest) *Book {
+	contributors := newContributors(req)
 	return &Book{
-		Id:        uuid.NewString(),
-		Title:     req.Title,
-		AuthorId:  req.AuthorId,
-		Isbn:      req.Isbn,
-		SeriesId:  req.SeriesId,
-		SeriesNo:  req.SeriesNo,
-		Year:      req.Year,
-		Publisher: req.Publisher,
-		Language:  req.Language,
-		Format:    req.Format,
-		PagesNo:   req.PagesNo,
-		HoursNo:   req.HoursNo,
-		Genre:     req.Genre,
-		Blurb:     req.Blurb,
-		Cover:     req.Cover,
-		NotABook:  req.NotABook,
+		Id:           uuid.NewString(),
+		Meta:         NewMeta(),
+		WorkId:       req.WorkId,
+		Title:        req.Title,
+		Contributors: contributors,
+		AuthorId:     PrimaryAuthorId(contributors),
+		Isbn:         req.Isbn,
+		SeriesId:     req.SeriesId,
+		SeriesNo:     req.SeriesNo,
+		Year:         req.Year,
+		Publisher:    req.Publisher,
+		Language:     req.Language,
+		Format:       req.Format,
+		PagesNo:      req.PagesNo,
+		HoursNo:      req.HoursNo,
+		Genre:        req.Genre,
+		Blurb:        req.Blurb,
+		Cover:        req.Cover,
+		NotABook:     req.NotABook,
 	}
 }
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"slices"
+	"strings"
+)
+
+type Role string
+
+const (
+	RoleAuthor      Role = "author"
+	RoleTranslator  Role = "translator"
+	RoleIllustrator Role = "illustrator"
+	RoleNarrator    Role = "narrator"
+	RoleEditor      Role = "editor"
+)
+
+func NewRole(s string) Role {
+	switch strings.ToLower(s) {
+	case "author":
+		return RoleAuthor
+	case "translator":
+		return RoleTranslator
+	case "illustrator":
+		return RoleIllustrator
+	case "narrator":
+		return RoleNarrator
+	case "editor":
+		return RoleEditor
+	default:
+		return ""
+	}
+}
+
+type Contributor struct {
+	AuthorId string `json:"author_id" bson:"author_id"`
+	Role     Role   `json:"role" bson:"role"`
+	Order    int    `json:"order" bson:"order"`
+}
+
+// PrimaryAuthorId returns the author with the lowest order among the
+// contributors with the author role, or "" when there is none.
+func PrimaryAuthorId(cc []Contributor) string {
+	primary := -1
+	for i, c := range cc {
+		if c.Role == RoleAuthor && (primary < 0 || c.Order < cc[primary].Order) {
+			primary = i
+		}
+	}
+	if primary < 0 {
+		return ""
+	}
+	return cc[primary].AuthorId
+}
+
+// HasContributor reports whether the author contributed to the book with the
+// given role, or with any role when role is empty.
+func (b *Book) HasContributor(authorId string, role Role) bool {
+	if b.AuthorId == authorId && (role == "" || role == RoleAuthor) {
+		return true
+	}
+	return slices.ContainsFunc(b.Contributors, func(c Contributor) bool {
+		return c.AuthorId == authorId && (role == "" || c.Role == role)
+	})
+}
+
+// Normalize canonicalizes the contributor roles and derives AuthorId from the
+// contributors, first promoting a lone AuthorId on books stored before
+// contributors existed.
+func (b *Book) Normalize() {
+	if len(b.Contributors) == 0 && b.AuthorId != "" {
+		b.Contributors = []Contributor{{AuthorId: b.AuthorId, Role: RoleAuthor}}
+	}
+	for i := range b.Contributors {
+		if role := NewRole(string(b.Contributors[i].Role)); role != "" {
+			b.Contributors[i].Role = role
+		}
+	}
+	b.AuthorId = PrimaryAuthorId(b.Contributors)
+}
+
+// RemoveContributor drops every contribution of the author and recomputes
+// the primary author.
+func (b *Book) RemoveContributor(authorId string) {
+	b.Contributors = slices.DeleteFunc(b.Contributors, func(c Contributor) bool {
+		return c.AuthorId == authorId
+	})
+	b.AuthorId = PrimaryAuthorId(b.Contributors)
+}
+
+// RemoveContributor drops every contribution of the author.
+func (w *Work) RemoveContributor(authorId string) {
+	w.Contributors = slices.DeleteFunc(w.Contributors, func(c Contributor) bool {
+		return c.AuthorId == authorId
+	})
+}
+
+// ReplaceContributor credits the contributions of an author to another one,
+// as when merging duplicate authors, and recomputes the primary author.
+func (b *Book) ReplaceContributor(authorId, with string) {
+	b.Contributors = replaceContributor(b.Contributors, authorId, with)
+	b.AuthorId = PrimaryAuthorId(b.Contributors)
+}
+
+// ReplaceContributor credits the contributions of an author to another one.
+func (w *Work) ReplaceContributor(authorId, with string) {
+	w.Contributors = replaceContributor(w.Contributors, authorId, with)
+}
+
+// replaceContributor rewrites the contributions of authorId to with, keeping
+// a single contribution per role.
+func replaceContributor(cc []Contributor, authorId, with string) []Contributor {
+	out := make([]Contributor, 0, len(cc))
+	for _, c := range cc {
+		if c.AuthorId == authorId {
+			c.AuthorId = with
+		}
+		if i := slices.IndexFunc(out, func(o Contributor) bool {
+			return o.AuthorId == c.AuthorId && o.Role == c.Role
+		}); i >= 0 {
+			out[i].Order = min(out[i].Order, c.Order)
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func newContributors(req BookRequest) []Contributor {
+	if len(req.Contributors) == 0 {
+		// Clients predating contributors send only the primary author.
+		if req.AuthorId == "" {
+			return nil
+		}
+		return []Contributor{{AuthorId: req.AuthorId, Role: RoleAuthor}}
+	}
+
+	cc := make([]Contributor, 0, len(req.Contributors))
+	for _, c := range req.Contributors {
+		c.Role = NewRole(string(c.Role))
+		cc = append(cc, c)
+	}
+	return cc
+}
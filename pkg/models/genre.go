@@ -1,10 +1,15 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
 
 type Genre struct {
-	Id  string `json:"id" bson:"_id"`
-	Tag string `json:"tag " bson:"tag"`
+	Id   string `json:"id" bson:"_id"`
+	Meta `bson:",inline"`
+	Tag  string `json:"tag" bson:"tag"`
 }
 
 type GenreRequest struct {
@@ -13,7 +18,31 @@ type GenreRequest struct {
 
 func NewGenre(req GenreRequest) *Genre {
 	return &Genre{
-		Id:  uuid.NewString(),
-		Tag: req.Tag,
+		Id:   uuid.NewString(),
+		Meta: NewMeta(),
+		Tag:  req.Tag,
+	}
+}
+
+// ReplaceGenre tags the book with another genre instead of tag.
+func (b *Book) ReplaceGenre(tag, with string) {
+	b.Genre = replaceGenre(b.Genre, tag, with)
+}
+
+// ReplaceGenre tags the work with another genre instead of tag.
+func (w *Work) ReplaceGenre(tag, with string) {
+	w.Genre = replaceGenre(w.Genre, tag, with)
+}
+
+func replaceGenre(tags []string, tag, with string) []string {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if t == tag {
+			t = with
+		}
+		if !slices.Contains(out, t) {
+			out = append(out, t)
+		}
 	}
+	return out
 }
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ListOptions holds the pagination and ordering shared by every list endpoint.
+type ListOptions struct {
+	Limit  int
+	Offset int
+	Sort   []SortField
+}
+
+// List is the envelope returned by every list endpoint.
+type List[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func NewList[T any](items []T, total int64, opts ListOptions) *List[T] {
+	l := &List[T]{
+		Items: items,
+		Total: total,
+	}
+	if next := opts.Offset + len(items); len(items) > 0 && int64(next) < total {
+		l.NextCursor = EncodeCursor(next)
+	}
+	return l
+}
+
+// ParseListOptions reads limit, offset, cursor and sort from the query string.
+// Sort is a comma separated list of fields, each optionally prefixed with "-"
+// for descending order, and must only reference the given sortable fields.
+func ParseListOptions(v url.Values, sortable ...string) (ListOptions, error) {
+	opts := ListOptions{Limit: DefaultLimit}
+
+	if s := v.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return opts, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+		}
+		opts.Limit = limit
+	}
+
+	cursor, offset := v.Get("cursor"), v.Get("offset")
+	switch {
+	case cursor != "" && offset != "":
+		return opts, errors.New("cursor and offset are mutually exclusive")
+	case cursor != "":
+		n, err := DecodeCursor(cursor)
+		if err != nil {
+			return opts, err
+		}
+		opts.Offset = n
+	case offset != "":
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 {
+			return opts, errors.New("offset must be a non-negative integer")
+		}
+		opts.Offset = n
+	}
+
+	if s := v.Get("sort"); s != "" {
+		for _, f := range strings.Split(s, ",") {
+			sf := SortField{Field: strings.TrimSpace(f)}
+			if strings.HasPrefix(sf.Field, "-") {
+				sf.Field, sf.Desc = sf.Field[1:], true
+			}
+			if !slices.Contains(sortable, sf.Field) {
+				return opts, fmt.Errorf("cannot sort by %q", sf.Field)
+			}
+			opts.Sort = append(opts.Sort, sf)
+		}
+	}
+
+	return opts, nil
+}
+
+// EncodeCursor returns the opaque cursor pointing at the given offset.
+func EncodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("o:" + strconv.Itoa(offset)))
+}
+
+func DecodeCursor(cursor string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(b), "o:") {
+		return 0, errors.New("invalid cursor")
+	}
+	n, err := strconv.Atoi(string(b[2:]))
+	if err != nil || n < 0 {
+		return 0, errors.New("invalid cursor")
+	}
+	return n, nil
+}
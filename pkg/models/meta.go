@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Meta is the bookkeeping every entity carries. Version starts at 1 and is
+// incremented by every update, which only applies if the stored version is
+// still the one the update was based on.
+type Meta struct {
+	Version   int64     `json:"version" bson:"version"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+func NewMeta() Meta {
+	now := now()
+	return Meta{
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Next returns the metadata of the entity once updated.
+func (m Meta) Next() Meta {
+	return Meta{
+		Version:   m.Version + 1,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: now(),
+	}
+}
+
+// now is truncated to the millisecond precision Mongo stores.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
@@ -4,6 +4,7 @@ import "github.com/google/uuid"
 
 type Series struct {
 	Id   string `json:"id" bson:"_id"`
+	Meta `bson:",inline"`
 	Name string `json:"name" bson:"name"`
 }
 
@@ -14,6 +15,7 @@ type SeriesRequest struct {
 func NewSeries(req SeriesRequest) *Series {
 	return &Series{
 		Id:   uuid.NewString(),
+		Meta: NewMeta(),
 		Name: req.Name,
 	}
 }
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Work is what editions have in common: a hardcover, a paperback and an
+// audiobook of the same novel are three editions, stored as books, of one work.
+type Work struct {
+	Id           string `json:"id" bson:"_id"`
+	Meta         `bson:",inline"`
+	Title        string        `json:"title" bson:"title"`
+	Contributors []Contributor `json:"contributors" bson:"contributors,omitempty"`
+	SeriesId     string        `json:"series_id" bson:"series_id,omitempty"`
+	SeriesNo     int           `json:"series_no" bson:"series_no,omitempty"`
+	Genre        []string      `json:"genre" bson:"genre,omitempty"`
+	Blurb        string        `json:"blurb" bson:"blurb,omitempty"`
+}
+
+type WorkRequest struct {
+	Title        string        `json:"title"`
+	Contributors []Contributor `json:"contributors"`
+	SeriesId     string        `json:"series_id"`
+	SeriesNo     int           `json:"series_no"`
+	Genre        []string      `json:"genre"`
+	Blurb        string        `json:"blurb"`
+}
+
+// EditionRequest holds the format specific fields of a book created under an
+// existing work, which provides the rest.
+type EditionRequest struct {
+	Isbn      []string `json:"isbn"`
+	Year      int      `json:"year"`
+	Publisher string   `json:"publisher"`
+	Language  string   `json:"language"`
+	Format    Format   `json:"format"`
+	PagesNo   int      `json:"pages_no"`
+	HoursNo   int      `json:"hours_no"`
+	Cover     string   `json:"cover"`
+	NotABook  bool     `json:"not_a_book"`
+}
+
+func NewWork(req WorkRequest) *Work {
+	contributors := newContributors(BookRequest{Contributors: req.Contributors})
+	return &Work{
+		Id:           uuid.NewString(),
+		Meta:         NewMeta(),
+		Title:        req.Title,
+		Contributors: contributors,
+		SeriesId:     req.SeriesId,
+		SeriesNo:     req.SeriesNo,
+		Genre:        req.Genre,
+		Blurb:        req.Blurb,
+	}
+}
+
+// NewWorkFromBook creates the work a book, stored before works existed, is
+// an edition of.
+func NewWorkFromBook(b *Book) *Work {
+	return &Work{
+		Id:           uuid.NewString(),
+		Meta:         NewMeta(),
+		Title:        b.Title,
+		Contributors: b.Contributors,
+		SeriesId:     b.SeriesId,
+		SeriesNo:     b.SeriesNo,
+		Genre:        b.Genre,
+		Blurb:        b.Blurb,
+	}
+}
+
+func NewEdition(w *Work, req EditionRequest) *Book {
+	b := &Book{
+		Id:        uuid.NewString(),
+		Meta:      NewMeta(),
+		Isbn:      req.Isbn,
+		Year:      req.Year,
+		Publisher: req.Publisher,
+		Language:  req.Language,
+		Format:    req.Format,
+		PagesNo:   req.PagesNo,
+		HoursNo:   req.HoursNo,
+		Cover:     req.Cover,
+		NotABook:  req.NotABook,
+	}
+	b.CopyWork(w)
+	return b
+}
+
+// CopyWork makes b an edition of w, taking the fields editions share from it.
+func (b *Book) CopyWork(w *Work) {
+	b.WorkId = w.Id
+	b.Title = w.Title
+	b.Contributors = slices.Clone(w.Contributors)
+	b.AuthorId = PrimaryAuthorId(w.Contributors)
+	b.SeriesId = w.SeriesId
+	b.SeriesNo = w.SeriesNo
+	b.Genre = slices.Clone(w.Genre)
+	b.Blurb = w.Blurb
+}
@@ -0,0 +1,121 @@
+// Package problem implements the RFC 7807 problem details returned by every
+// failed request.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/literalog/cerrors"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem detail. Extensions are serialized as
+// additional top level members.
+type Problem struct {
+	Type       string         `json:"type"`
+	Title      string         `json:"title"`
+	Status     int            `json:"status"`
+	Detail     string         `json:"detail,omitempty"`
+	Instance   string         `json:"instance,omitempty"`
+	Errors     []FieldError   `json:"errors,omitempty"`
+	Extensions map[string]any `json:"-"`
+}
+
+func New(status int, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// With adds an extension member to the problem.
+func (p *Problem) With(key string, value any) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	type problem Problem
+	b, err := json.Marshal((*problem)(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return b, err
+	}
+
+	members := make(map[string]any, len(p.Extensions))
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+	if err := json.Unmarshal(b, &members); err != nil {
+		return nil, err
+	}
+	return json.Marshal(members)
+}
+
+func (p *Problem) UnmarshalJSON(b []byte) error {
+	type problem Problem
+	if err := json.Unmarshal(b, (*problem)(p)); err != nil {
+		return err
+	}
+
+	var members map[string]any
+	if err := json.Unmarshal(b, &members); err != nil {
+		return err
+	}
+	for _, k := range []string{"type", "title", "status", "detail", "instance", "errors"} {
+		delete(members, k)
+	}
+	if len(members) > 0 {
+		p.Extensions = members
+	}
+	return nil
+}
+
+func (p *Problem) Render(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	return json.NewEncoder(w).Encode(p)
+}
+
+// Handle renders err as a problem. Validation errors become 422, cerrors keep
+// their status and anything else is logged and hidden behind a 500.
+func Handle(err error, w http.ResponseWriter) {
+	From(err).Render(w)
+}
+
+// From converts err to the problem Handle renders.
+func From(err error) *Problem {
+	var (
+		p  *Problem
+		ve *ValidationError
+		ce cerrors.Error
+	)
+	switch {
+	case errors.As(err, &p):
+		return p
+	case errors.As(err, &ve):
+		p := New(http.StatusUnprocessableEntity, "request failed validation")
+		p.Errors = ve.Errors
+		return p
+	case errors.As(err, &ce):
+		return New(ce.Status, ce.Err)
+	default:
+		slog.Error("unhandled error", "err", err)
+		return New(http.StatusInternalServerError, "")
+	}
+}
@@ -0,0 +1,49 @@
+package problem
+
+import "strings"
+
+// Codes of the rules a field can fail.
+const (
+	CodeRequired  = "required"
+	CodeInvalid   = "invalid"
+	CodeLength    = "length"
+	CodeDuplicate = "duplicate"
+	CodeNotFound  = "not_found"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects every rule an entity fails.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Add records that the field, given as a path such as "contributors[0].role",
+// failed the rule with the given code.
+func (e *ValidationError) Add(field, code string, err error) {
+	e.Errors = append(e.Errors, FieldError{
+		Field:   field,
+		Code:    code,
+		Message: err.Error(),
+	})
+}
+
+// Err returns nil when no rule failed, so it can be returned directly.
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.Field+": "+fe.Message)
+	}
+	return "invalid request: " + strings.Join(msgs, "; ")
+}